@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ConditionsMode is how the conditions read with --for-file are combined
+type ConditionsMode string
+
+const (
+	// ConditionsModeAll waits for every one of the conditions to be met, as when --for is repeated
+	ConditionsModeAll ConditionsMode = "all"
+	// ConditionsModeAny waits for any one of the conditions to be met
+	ConditionsModeAny ConditionsMode = "any"
+)
+
+// conditionsFile is the content of a file given to --for-file, which is either a list of
+// conditions or a mapping with the conditions and how to combine them:
+//
+//	mode: any
+//	conditions:
+//	- condition=Available
+//	- jsonpath={.status.phase}=Failed
+type conditionsFile struct {
+	Mode       yaml.Node   `yaml:"mode"`
+	Conditions []yaml.Node `yaml:"conditions"`
+}
+
+// readConditionsFile reads the conditions of a file given to --for-file, and how to combine
+// them. Every condition is checked as --for would, and reported with the line it is on.
+func readConditionsFile(filename string, ignoreCase bool) ([]string, ConditionsMode, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read the conditions to wait on: %v", err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, "", fmt.Errorf("unable to parse the conditions to wait on from %s: %v", filename, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, "", fmt.Errorf("%s must list at least one condition to wait on", filename)
+	}
+	root := document.Content[0]
+	var file conditionsFile
+	switch root.Kind {
+	case yaml.SequenceNode:
+		for _, node := range root.Content {
+			file.Conditions = append(file.Conditions, *node)
+		}
+	case yaml.MappingNode:
+		if err := root.Decode(&file); err != nil {
+			return nil, "", fmt.Errorf("%s:%d: %v", filename, root.Line, err)
+		}
+	default:
+		return nil, "", fmt.Errorf("%s:%d: expected a list of conditions, or a mapping with the conditions and a mode", filename, root.Line)
+	}
+	mode := ConditionsModeAll
+	if len(file.Mode.Value) > 0 {
+		mode = ConditionsMode(file.Mode.Value)
+	}
+	if mode != ConditionsModeAll && mode != ConditionsModeAny {
+		return nil, "", fmt.Errorf("%s:%d: mode must be one of: all, any", filename, file.Mode.Line)
+	}
+	if len(file.Conditions) == 0 {
+		return nil, "", fmt.Errorf("%s must list at least one condition to wait on", filename)
+	}
+
+	conditions := make([]string, 0, len(file.Conditions))
+	for _, node := range file.Conditions {
+		if node.Kind != yaml.ScalarNode || len(node.Value) == 0 {
+			return nil, "", fmt.Errorf("%s:%d: expected a condition, as given to --for", filename, node.Line)
+		}
+		isCount, isCovers := hasCountCondition([]string{node.Value}), hasCoversCondition([]string{node.Value})
+		if (isCount || isCovers) && len(file.Conditions) > 1 {
+			return nil, "", fmt.Errorf("%s:%d: a count or a covers condition cannot be combined with other conditions", filename, node.Line)
+		}
+		var err error
+		switch {
+		case isCount:
+			_, err = countWaitFor(node.Value)
+		case isCovers:
+			_, err = coversWaitFor(node.Value, ignoreCase)
+		default:
+			_, err = conditionFuncFor(node.Value, ignoreCase)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("%s:%d: %v", filename, node.Line, err)
+		}
+		conditions = append(conditions, node.Value)
+	}
+	return conditions, mode, nil
+}
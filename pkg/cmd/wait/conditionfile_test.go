@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+func TestReadConditionsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wait-for-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+
+		expectedConditions []string
+		expectedMode       ConditionsMode
+		expectedErr        string
+	}{
+		{
+			name:               "list",
+			content:            "- condition=Available\n- jsonpath={.status.updatedReplicas}=3\n",
+			expectedConditions: []string{"condition=Available", "jsonpath={.status.updatedReplicas}=3"},
+			expectedMode:       ConditionsModeAll,
+			expectedErr:        None,
+		},
+		{
+			name:               "mapping with a mode",
+			content:            "mode: any\nconditions:\n- condition=Complete\n- condition=Failed\n",
+			expectedConditions: []string{"condition=Complete", "condition=Failed"},
+			expectedMode:       ConditionsModeAny,
+			expectedErr:        None,
+		},
+		{
+			name:               "mapping without a mode",
+			content:            "conditions:\n- delete\n",
+			expectedConditions: []string{"delete"},
+			expectedMode:       ConditionsModeAll,
+			expectedErr:        None,
+		},
+		{
+			name:               "single count condition",
+			content:            "- count>=3\n",
+			expectedConditions: []string{"count>=3"},
+			expectedMode:       ConditionsModeAll,
+			expectedErr:        None,
+		},
+		{
+			name:        "invalid condition",
+			content:     "# gate for the release\n- condition=Available\n- jsonpath={.status.replicas}>three\n",
+			expectedErr: "conditions.yaml:3: ",
+		},
+		{
+			name:        "unknown condition",
+			content:     "conditions:\n  - ready\n  - unknown\n",
+			expectedErr: "conditions.yaml:3: unrecognized condition: \"unknown\"",
+		},
+		{
+			name:        "invalid mode",
+			content:     "conditions:\n- ready\nmode: some\n",
+			expectedErr: "conditions.yaml:3: mode must be one of: all, any",
+		},
+		{
+			name:        "count combined with other conditions",
+			content:     "- ready\n- count>=3\n",
+			expectedErr: "conditions.yaml:2: a count or a covers condition cannot be combined with other conditions",
+		},
+		{
+			name:        "condition which is not a string",
+			content:     "- ready\n- condition: Available\n",
+			expectedErr: "conditions.yaml:2: expected a condition, as given to --for",
+		},
+		{
+			name:        "no conditions",
+			content:     "mode: any\n",
+			expectedErr: "conditions.yaml must list at least one condition to wait on",
+		},
+		{
+			name:        "empty file",
+			content:     "",
+			expectedErr: "conditions.yaml must list at least one condition to wait on",
+		},
+		{
+			name:        "scalar",
+			content:     "ready\n",
+			expectedErr: "conditions.yaml:1: expected a list of conditions, or a mapping with the conditions and a mode",
+		},
+		{
+			name:        "invalid YAML",
+			content:     "- ready\n- [delete\n",
+			expectedErr: "unable to parse the conditions to wait on from",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(dir, "conditions.yaml")
+			if err := ioutil.WriteFile(filename, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			conditions, mode, err := readConditionsFile(filename, false)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(conditions, test.expectedConditions) {
+				t.Errorf("expected conditions %q, got %q", test.expectedConditions, conditions)
+			}
+			if mode != test.expectedMode {
+				t.Errorf("expected mode %q, got %q", test.expectedMode, mode)
+			}
+		})
+	}
+
+	if _, _, err := readConditionsFile(filepath.Join(dir, "missing.yaml"), false); err == nil || !strings.Contains(err.Error(), "unable to read the conditions to wait on") {
+		t.Errorf("expected a missing file to fail, got %v", err)
+	}
+}
+
+func TestForFileOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wait-for-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "conditions.yaml")
+	if err := ioutil.WriteFile(filename, []byte("mode: any\nconditions:\n- condition=Complete\n- condition=Failed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+
+	flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+	flags.ForFile = filename
+	o, err := flags.ToOptions([]string{"pod/foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "condition=Complete or condition=Failed"; o.ForCondition != expected {
+		t.Errorf("expected the condition %q, got %q", expected, o.ForCondition)
+	}
+
+	flags = NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+	flags.ForFile = filename
+	flags.ForConditions = []string{"condition=Ready"}
+	if _, err := flags.ToOptions([]string{"pod/foo"}); err == nil || !strings.Contains(err.Error(), "--for-file cannot be combined with --for") {
+		t.Errorf("expected --for-file to be rejected with --for, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// EndpointsWait waits for a Service to have ready addresses to send traffic to, as listed by
+// the Endpoints of the same name, which is what the wait watches rather than the Service
+type EndpointsWait struct {
+	// count is the number of ready addresses required. Zero means at least one.
+	count int
+}
+
+// endpointsMapping is the mapping of the Endpoints of a Service
+var endpointsMapping = &meta.RESTMapping{
+	Resource:         schema.GroupVersionResource{Version: "v1", Resource: "endpoints"},
+	GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Endpoints"},
+	Scope:            meta.RESTScopeNamespace,
+}
+
+// HasEndpoints is a conditionfunc for waiting on a Service to have ready addresses. It waits
+// on the Endpoints of the Service, which may not exist yet, and returns the Service once they
+// list enough ready addresses. It returns a ConditionUnmetError if the resource is not a
+// Service.
+func (w EndpointsWait) HasEndpoints(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if info.Mapping.Resource.GroupResource() != (schema.GroupResource{Resource: "services"}) {
+		return info.Object, false, newConditionUnmetError(info, "has-endpoints only applies to services")
+	}
+	endpointsInfo := &resource.Info{Mapping: endpointsMapping, Namespace: info.Namespace, Name: info.Name}
+	condMet := eventCondition(o.ErrOut, "the endpoints of the service", false, w.checkCondition)
+	endpoints, done, err := getObjAndCheckCondition(ctx, endpointsInfo, o, condMet, w.checkCondition, w.observedAddresses, w.describe)
+	if done {
+		return info.Object, true, nil
+	}
+	return endpoints, false, err
+}
+
+// addresses returns the number of distinct ready and not ready addresses of the Endpoints.
+// Every subset lists the addresses which serve its ports, so an address serving several ports
+// is only counted once.
+func (w EndpointsWait) addresses(obj *unstructured.Unstructured) (ready, notReady int) {
+	readyIPs, notReadyIPs := sets.NewString(), sets.NewString()
+	subsets, _, _ := unstructured.NestedSlice(obj.Object, "subsets")
+	for _, subset := range subsets {
+		subset, ok := subset.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for field, ips := range map[string]sets.String{"addresses": readyIPs, "notReadyAddresses": notReadyIPs} {
+			addresses, _, _ := unstructured.NestedSlice(subset, field)
+			for _, address := range addresses {
+				if address, ok := address.(map[string]interface{}); ok {
+					ip, _, _ := unstructured.NestedString(address, "ip")
+					ips.Insert(ip)
+				}
+			}
+		}
+	}
+	return readyIPs.Len(), notReadyIPs.Difference(readyIPs).Len()
+}
+
+// observedAddresses returns the number of ready and not ready addresses
+func (w EndpointsWait) observedAddresses(obj *unstructured.Unstructured) string {
+	ready, notReady := w.addresses(obj)
+	return fmt.Sprintf("%d ready, %d not ready", ready, notReady)
+}
+
+// describe explains how many ready addresses the service is waited on to have
+func (w EndpointsWait) describe(observed string) string {
+	count := w.count
+	if count == 0 {
+		count = 1
+	}
+	return fmt.Sprintf("addresses of the service (last observed: %s) to be ready, at least %d of them", observed, count)
+}
+
+func (w EndpointsWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	ready, _ := w.addresses(obj)
+	if w.count > 0 {
+		return ready >= w.count, nil
+	}
+	return ready > 0, nil
+}
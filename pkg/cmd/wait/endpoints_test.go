@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestWaitForEndpoints(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "endpoints"}: "EndpointsList",
+	}
+	newEndpoints := func(subsets ...interface{}) *unstructured.Unstructured {
+		obj := newUnstructured("v1", "Endpoints", "ns-foo", "web")
+		obj.Object["subsets"] = subsets
+		return obj
+	}
+	subset := func(ready []string, notReady ...string) map[string]interface{} {
+		addresses := func(ips []string) []interface{} {
+			list := []interface{}{}
+			for _, ip := range ips {
+				list = append(list, map[string]interface{}{"ip": ip})
+			}
+			return list
+		}
+		return map[string]interface{}{"addresses": addresses(ready), "notReadyAddresses": addresses(notReady)}
+	}
+	service := &resource.Info{
+		Mapping: &meta.RESTMapping{
+			Resource:         schema.GroupVersionResource{Version: "v1", Resource: "services"},
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Service"},
+		},
+		Name:      "web",
+		Namespace: "ns-foo",
+		Object:    newUnstructured("v1", "Service", "ns-foo", "web"),
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		info      *resource.Info
+		endpoints *unstructured.Unstructured
+		watched   *unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "a ready address",
+			condition:   "has-endpoints",
+			info:        service,
+			endpoints:   newEndpoints(subset([]string{"10.0.0.1"}, "10.0.0.2")),
+			expectedErr: None,
+		},
+		{
+			name:        "no ready address",
+			condition:   "has-endpoints",
+			info:        service,
+			endpoints:   newEndpoints(subset(nil, "10.0.0.1", "10.0.0.2")),
+			expectedErr: "timed out waiting for the condition on endpoints/web: addresses of the service (last observed: 0 ready, 2 not ready) to be ready, at least 1 of them",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "endpoints created while watching",
+			condition:   "has-endpoints",
+			info:        service,
+			watched:     newEndpoints(subset([]string{"10.0.0.1"})),
+			expectedErr: None,
+		},
+		{
+			name:        "no endpoints",
+			condition:   "has-endpoints",
+			info:        service,
+			expectedErr: "timed out waiting for the condition on endpoints/web",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "enough ready addresses",
+			condition:   "has-endpoints=2",
+			info:        service,
+			endpoints:   newEndpoints(subset([]string{"10.0.0.1"}), subset([]string{"10.0.0.2"})),
+			expectedErr: None,
+		},
+		{
+			name:        "addresses serving several ports counted once",
+			condition:   "has-endpoints=3",
+			info:        service,
+			endpoints:   newEndpoints(subset([]string{"10.0.0.1"}, "10.0.0.2"), subset([]string{"10.0.0.1", "10.0.0.2"})),
+			expectedErr: "addresses of the service (last observed: 2 ready, 0 not ready) to be ready",
+			exitCode:    ExitCodeTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "endpoints", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if test.endpoints == nil {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(test.endpoints), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("endpoints", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Add(test.watched)
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.info),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+				return
+			}
+			if len(result.Satisfied) != 1 || result.Satisfied[0].GetObjectKind().GroupVersionKind().Kind != "Service" {
+				t.Errorf("expected the service to meet the condition, got %v", result.Satisfied)
+			}
+		})
+	}
+}
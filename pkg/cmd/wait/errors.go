@@ -0,0 +1,268 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+	utilexec "k8s.io/utils/exec"
+)
+
+// Exit codes used by the wait command, so that scripts can tell why a wait failed.
+// Any other error, such as an invalid condition or an error from the API server,
+// exits with 1.
+const (
+	// ExitCodeTimeout is used when the timeout was reached before the condition was met
+	ExitCodeTimeout = 2
+	// ExitCodeNoMatchingResources is used when no resources matched the query
+	ExitCodeNoMatchingResources = 3
+	// ExitCodeConditionUnmet is used when a condition can no longer be met
+	ExitCodeConditionUnmet = 4
+	// ExitCodeNotMet is used when a condition checked once, rather than waited on, is not met
+	ExitCodeNotMet = 5
+	// ExitCodeMaxPollsExceeded is used when the condition was checked WaitOptions.MaxPolls
+	// times without being met
+	ExitCodeMaxPollsExceeded = 6
+	// ExitCodeInterrupted is used when the wait was interrupted with SIGINT or SIGTERM, as is
+	// customary for programs stopped with Ctrl-C
+	ExitCodeInterrupted = 130
+)
+
+// ErrRelist is returned by a ConditionFunc, as is or wrapped, to have the resources looked up
+// again with the ResourceFinder right away rather than keep waiting on the resources found so
+// far, for instance once the resource it checks shows that a selector now matches more of them.
+// See ConditionFunc.
+var ErrRelist = errors.New("the resources have to be looked up again")
+
+// TimeoutError is returned when the condition was not met on a resource before the timeout.
+type TimeoutError struct {
+	// Resource is the resource type, e.g. "pods"
+	Resource string
+	// Name is the name of the resource
+	Name string
+	// Detail optionally describes the condition and the last value observed for it
+	Detail string
+	// Observed is the last value observed for the condition, if the condition reports one
+	Observed string
+}
+
+func (e *TimeoutError) Error() string {
+	if len(e.Detail) > 0 {
+		return fmt.Sprintf("%s on %s/%s: %s", wait.ErrWaitTimeout.Error(), e.Resource, e.Name, e.Detail)
+	}
+	return fmt.Sprintf("%s on %s/%s", wait.ErrWaitTimeout.Error(), e.Resource, e.Name)
+}
+
+// Unwrap returns wait.ErrWaitTimeout, so callers checking for it continue to work.
+func (e *TimeoutError) Unwrap() error {
+	return wait.ErrWaitTimeout
+}
+
+// NoMatchingResourcesError is returned when there are no resources matching a query.
+type NoMatchingResourcesError struct{}
+
+func (e *NoMatchingResourcesError) Error() string {
+	return "no matching resources found"
+}
+
+// ConditionUnmetError is returned when the condition was not met on a resource for a reason
+// other than the timeout, for instance because the resource reached a state from which the
+// condition can never be met.
+type ConditionUnmetError struct {
+	// Resource is the resource type, e.g. "pods"
+	Resource string
+	// Name is the name of the resource
+	Name string
+	// Reason explains why the condition is unmet
+	Reason string
+}
+
+func (e *ConditionUnmetError) Error() string {
+	reason := e.Reason
+	if len(reason) == 0 {
+		reason = "unknown reason"
+	}
+	return fmt.Sprintf("condition unsatisfied on %s/%s: %s", e.Resource, e.Name, reason)
+}
+
+// NotMetError is returned when a condition is checked once rather than waited on, and is not
+// met at the time of the check.
+type NotMetError struct {
+	// Resource is the resource type, e.g. "pods". It is empty for a count condition.
+	Resource string
+	// Name is the name of the resource
+	Name string
+	// Detail optionally describes the condition and the value observed for it
+	Detail string
+	// Observed is the value observed for the condition, if the condition reports one
+	Observed string
+}
+
+func (e *NotMetError) Error() string {
+	msg := "condition not met"
+	if len(e.Resource) > 0 {
+		msg += fmt.Sprintf(" on %s/%s", e.Resource, e.Name)
+	}
+	if len(e.Detail) > 0 {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// MaxPollsExceededError is returned when the condition was checked WaitOptions.MaxPolls times
+// on a resource, or the resources were counted that many times, without it being met.
+type MaxPollsExceededError struct {
+	// Resource is the resource type, e.g. "pods". It is empty for a count condition.
+	Resource string
+	// Name is the name of the resource
+	Name string
+	// Polls is the number of times the condition was checked
+	Polls int
+	// Detail optionally describes the condition and the last value observed for it
+	Detail string
+	// Observed is the last value observed for the condition, if the condition reports one
+	Observed string
+}
+
+func (e *MaxPollsExceededError) Error() string {
+	msg := fmt.Sprintf("condition not met after %d checks", e.Polls)
+	if len(e.Resource) > 0 {
+		msg += fmt.Sprintf(" on %s/%s", e.Resource, e.Name)
+	}
+	if len(e.Detail) > 0 {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// InterruptedError is returned when the wait was interrupted with SIGINT or SIGTERM before the
+// condition was met on every resource.
+type InterruptedError struct {
+	// Met is the number of resources that met the condition before the interruption
+	Met int
+	// Matched is the number of resources found
+	Matched int
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted after %d of %d resources met the condition", e.Met, e.Matched)
+}
+
+// DeadlinePassedError is returned when the wait started after WaitOptions.Until had passed, so
+// the condition was checked once, and it was not met.
+type DeadlinePassedError struct {
+	// Deadline is the WaitOptions.Until which had passed
+	Deadline time.Time
+	// Err is the error the condition failed with when it was checked
+	Err error
+}
+
+func (e *DeadlinePassedError) Error() string {
+	return fmt.Sprintf("the deadline %s had already passed: %v", e.Deadline.Format(time.RFC3339), e.Err)
+}
+
+// Unwrap returns the error the condition failed with.
+func (e *DeadlinePassedError) Unwrap() error {
+	return e.Err
+}
+
+// newConditionUnmetError returns a ConditionUnmetError for the resource
+func newConditionUnmetError(info *resource.Info, format string, args ...interface{}) error {
+	return &ConditionUnmetError{
+		Resource: info.Mapping.Resource.Resource,
+		Name:     info.Name,
+		Reason:   fmt.Sprintf(format, args...),
+	}
+}
+
+// observedFor returns the last value observed for the condition on the resource err is about,
+// or "" if err does not report one
+func observedFor(err error) string {
+	var (
+		timeoutErr  *TimeoutError
+		notMetErr   *NotMetError
+		maxPollsErr *MaxPollsExceededError
+	)
+	switch {
+	case errors.As(err, &timeoutErr):
+		return timeoutErr.Observed
+	case errors.As(err, &notMetErr):
+		return notMetErr.Observed
+	case errors.As(err, &maxPollsErr):
+		return maxPollsErr.Observed
+	}
+	return ""
+}
+
+// exitErrorFor wraps errors returned by RunWait with the exit code the command should exit with.
+func exitErrorFor(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := exitCodeFor(err)
+	if code == 0 {
+		return err
+	}
+	return utilexec.CodeExitError{Err: fmt.Errorf("error: %v", err), Code: code}
+}
+
+// exitCodeFor returns the exit code for err, or 0 if it has none of its own. The errors of
+// several resources only have one if they all have the same.
+func exitCodeFor(err error) int {
+	var (
+		aggregate         utilerrors.Aggregate
+		timeoutErr        *TimeoutError
+		noMatchingErr     *NoMatchingResourcesError
+		conditionUnmetErr *ConditionUnmetError
+		notMetErr         *NotMetError
+		maxPollsErr       *MaxPollsExceededError
+		interruptedErr    *InterruptedError
+		deadlineErr       *DeadlinePassedError
+	)
+	switch {
+	case errors.As(err, &deadlineErr):
+		// the deadline passing ends the wait as a timeout does, whatever the errors it wraps
+		return ExitCodeTimeout
+	case errors.As(err, &aggregate):
+		code := 0
+		for i, err := range aggregate.Errors() {
+			if i > 0 && exitCodeFor(err) != code {
+				return 0
+			}
+			code = exitCodeFor(err)
+		}
+		return code
+	case errors.As(err, &timeoutErr), errors.Is(err, wait.ErrWaitTimeout):
+		return ExitCodeTimeout
+	case errors.As(err, &noMatchingErr):
+		return ExitCodeNoMatchingResources
+	case errors.As(err, &conditionUnmetErr):
+		return ExitCodeConditionUnmet
+	case errors.As(err, &notMetErr):
+		return ExitCodeNotMet
+	case errors.As(err, &maxPollsErr):
+		return ExitCodeMaxPollsExceeded
+	case errors.As(err, &interruptedErr):
+		return ExitCodeInterrupted
+	}
+	return 0
+}
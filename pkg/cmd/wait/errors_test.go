@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestExitErrorFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:         "timeout",
+			err:          &TimeoutError{Resource: "pods", Name: "foo"},
+			expectedCode: ExitCodeTimeout,
+		},
+		{
+			name:         "wrapped timeout",
+			err:          fmt.Errorf("%w (unsatisfied condition: condition=Ready)", &TimeoutError{Resource: "pods", Name: "foo"}),
+			expectedCode: ExitCodeTimeout,
+		},
+		{
+			name:         "timeout waiting for a count",
+			err:          fmt.Errorf("%w: found 1 resources, expected count>=3", wait.ErrWaitTimeout),
+			expectedCode: ExitCodeTimeout,
+		},
+		{
+			name:         "no matching resources",
+			err:          errNoMatchingResources,
+			expectedCode: ExitCodeNoMatchingResources,
+		},
+		{
+			name:         "condition unmet",
+			err:          &ConditionUnmetError{Resource: "jobs", Name: "foo", Reason: "job failed"},
+			expectedCode: ExitCodeConditionUnmet,
+		},
+		{
+			name:         "not met",
+			err:          &NotMetError{Resource: "pods", Name: "foo", Detail: "expected condition Ready (last observed: False) to be true"},
+			expectedCode: ExitCodeNotMet,
+		},
+		{
+			name:         "count not met",
+			err:          &NotMetError{Detail: "found 1 resources, expected count>=3"},
+			expectedCode: ExitCodeNotMet,
+		},
+		{
+			name:         "max polls exceeded",
+			err:          &MaxPollsExceededError{Resource: "pods", Name: "foo", Polls: 3},
+			expectedCode: ExitCodeMaxPollsExceeded,
+		},
+		{
+			name:         "interrupted",
+			err:          &InterruptedError{Met: 1, Matched: 2},
+			expectedCode: ExitCodeInterrupted,
+		},
+		{
+			name: "several timeouts",
+			err: utilerrors.NewAggregate([]error{
+				&TimeoutError{Resource: "pods", Name: "foo"},
+				&TimeoutError{Resource: "pods", Name: "bar"},
+			}),
+			expectedCode: ExitCodeTimeout,
+		},
+		{
+			name: "several different errors",
+			err: utilerrors.NewAggregate([]error{
+				&TimeoutError{Resource: "pods", Name: "foo"},
+				&ConditionUnmetError{Resource: "jobs", Name: "bar", Reason: "job failed"},
+			}),
+			expectedCode: 1,
+		},
+		{
+			name:         "other error",
+			err:          errors.New("the server is currently unable to handle the request"),
+			expectedCode: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := exitErrorFor(test.err)
+			if test.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			code := 1
+			if exitErr, ok := err.(utilexec.ExitError); ok {
+				code = exitErr.ExitStatus()
+			}
+			if code != test.expectedCode {
+				t.Errorf("expected exit code %d, got %d", test.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestTimeoutErrorUnwrap(t *testing.T) {
+	err := &TimeoutError{Resource: "pods", Name: "foo"}
+	if !errors.Is(err, wait.ErrWaitTimeout) {
+		t.Errorf("expected %v to be wait.ErrWaitTimeout", err)
+	}
+	if err.Error() != "timed out waiting for the condition on pods/foo" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// crdGroupKind is the kind of a CustomResourceDefinition
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// EstablishedWait waits for a CustomResourceDefinition to be usable: established, with the names
+// of its resources accepted. It stops waiting as soon as the names are not accepted, since they
+// conflict with those of another definition.
+type EstablishedWait struct{}
+
+// IsEstablished is a conditionfunc for waiting on the Established and NamesAccepted conditions of
+// a CustomResourceDefinition to be True. It returns a ConditionUnmetError once NamesAccepted is
+// False, or if the resource is not a CustomResourceDefinition.
+func (w EstablishedWait) IsEstablished(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if info.Mapping.Resource.GroupResource() != (schema.GroupResource{Group: crdGroupKind.Group, Resource: "customresourcedefinitions"}) {
+		return info.Object, false, newConditionUnmetError(info, "established only applies to customresourcedefinitions")
+	}
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the definition to be established", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedConditions, w.describe)
+}
+
+// conditionStatus returns the status of the condition of the given type, or "<none>" if the
+// definition does not report it yet
+func (w EstablishedWait) conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	condition, found := jobCondition(obj, conditionType)
+	if !found {
+		return "<none>"
+	}
+	status, _, _ := unstructured.NestedString(condition, "status")
+	return status
+}
+
+// observedConditions returns the statuses of the Established and NamesAccepted conditions
+func (w EstablishedWait) observedConditions(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("Established=%s,NamesAccepted=%s", w.conditionStatus(obj, "Established"), w.conditionStatus(obj, "NamesAccepted"))
+}
+
+// describe explains that the definition is waited on to be established
+func (w EstablishedWait) describe(observed string) string {
+	return fmt.Sprintf("conditions Established and NamesAccepted (last observed: %s) to be true", observed)
+}
+
+func (w EstablishedWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	if namesAccepted, found := jobCondition(obj, "NamesAccepted"); found {
+		if status, _, _ := unstructured.NestedString(namesAccepted, "status"); strings.EqualFold(status, "False") {
+			reason, _, _ := unstructured.NestedString(namesAccepted, "reason")
+			message, _, _ := unstructured.NestedString(namesAccepted, "message")
+			if len(message) == 0 {
+				return false, newConditionUnmetError(info, "the names of the definition are not accepted (%s)", reason)
+			}
+			return false, newConditionUnmetError(info, "the names of the definition are not accepted (%s): %s", reason, message)
+		}
+	}
+	return strings.EqualFold(w.conditionStatus(obj, "Established"), "True") && strings.EqualFold(w.conditionStatus(obj, "NamesAccepted"), "True"), nil
+}
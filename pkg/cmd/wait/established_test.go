@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestWaitForEstablished(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}: "CustomResourceDefinitionList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+			},
+			Name: "crontabs.stable.example.com",
+		},
+	}
+	newCRD := func() *unstructured.Unstructured {
+		return newUnstructured("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "crontabs.stable.example.com")
+	}
+	namesConflict := func() *unstructured.Unstructured {
+		obj := addCondition(newCRD(), "Established", "False")
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		conditions = append(conditions, map[string]interface{}{
+			"type":    "NamesAccepted",
+			"status":  "False",
+			"reason":  "MultipleNameConflicts",
+			"message": `"crontabs" is already in use`,
+		})
+		unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		listed  *unstructured.Unstructured
+		watched *unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "established",
+			listed:      addCondition(addCondition(newCRD(), "NamesAccepted", "True"), "Established", "True"),
+			expectedErr: None,
+		},
+		{
+			name:        "established while watching",
+			listed:      addCondition(newCRD(), "NamesAccepted", "True"),
+			watched:     addCondition(addCondition(newCRD(), "NamesAccepted", "True"), "Established", "True"),
+			expectedErr: None,
+		},
+		{
+			name:        "names not accepted yet",
+			listed:      addCondition(newCRD(), "Established", "True"),
+			expectedErr: "timed out waiting for the condition on customresourcedefinitions/crontabs.stable.example.com: conditions Established and NamesAccepted (last observed: Established=True,NamesAccepted=<none>) to be true",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "not established",
+			listed:      addCondition(addCondition(newCRD(), "NamesAccepted", "True"), "Established", "False"),
+			expectedErr: "(last observed: Established=False,NamesAccepted=True) to be true",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "names conflict",
+			listed:      addCondition(newCRD(), "NamesAccepted", "True"),
+			watched:     namesConflict(),
+			expectedErr: `condition unsatisfied on customresourcedefinitions/crontabs.stable.example.com: the names of the definition are not accepted (MultipleNameConflicts): "crontabs" is already in use`,
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "names conflict without a message",
+			listed:      addConditionWithReason(newCRD(), "NamesAccepted", "False", "MultipleNameConflicts"),
+			expectedErr: "the names of the definition are not accepted (MultipleNameConflicts)",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "customresourcedefinitions", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(test.listed), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("customresourcedefinitions", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(test.watched)
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor("established", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
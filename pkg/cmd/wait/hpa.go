@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// hpaConditionsAnnotation holds the conditions of a horizontal pod autoscaler, as JSON, when
+// it is read through autoscaling/v1, which has no conditions in its status
+const hpaConditionsAnnotation = "autoscaling.alpha.kubernetes.io/conditions"
+
+// HPAStableWait waits for a horizontal pod autoscaler to have settled on its desired number of
+// replicas, and stops waiting as soon as it reports that it cannot scale its target
+type HPAStableWait struct{}
+
+// IsHPAStable is a conditionfunc for waiting on a horizontal pod autoscaler to be stable: its
+// latest spec has been observed, its current replicas are its desired replicas, and it is able
+// to scale without backing off. ScalingLimited does not keep it from being stable, since an
+// autoscaler held at its minimum or maximum replicas has settled as well. It returns a
+// ConditionUnmetError once AbleToScale is false because the scale of the target cannot be read
+// or updated, since the replicas will not settle then.
+func (w HPAStableWait) IsHPAStable(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the horizontal pod autoscaler to be stable", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedStatus, w.describe)
+}
+
+// hpaCondition returns the condition of the given type of a horizontal pod autoscaler, from its
+// status or else from its conditions annotation
+func hpaCondition(obj *unstructured.Unstructured, conditionType string) (status, reason, message string, found bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if len(conditions) == 0 {
+		if annotation, ok := obj.GetAnnotations()[hpaConditionsAnnotation]; ok {
+			if err := json.Unmarshal([]byte(annotation), &conditions); err != nil {
+				// an annotation which is not valid reports no conditions
+				conditions = nil
+			}
+		}
+	}
+	for _, conditionUncast := range conditions {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(condition, "type"); name == conditionType {
+			status, _, _ = unstructured.NestedString(condition, "status")
+			reason, _, _ = unstructured.NestedString(condition, "reason")
+			message, _, _ = unstructured.NestedString(condition, "message")
+			return status, reason, message, true
+		}
+	}
+	return "", "", "", false
+}
+
+// observedStatus returns the current and desired replicas of the horizontal pod autoscaler,
+// followed by its AbleToScale and ScalingLimited conditions if it reports them
+func (w HPAStableWait) observedStatus(obj *unstructured.Unstructured) string {
+	current, _, _ := unstructured.NestedInt64(obj.Object, "status", "currentReplicas")
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredReplicas")
+	observed := fmt.Sprintf("current %d, desired %d replicas", current, desired)
+	for _, conditionType := range []string{"AbleToScale", "ScalingLimited"} {
+		if status, reason, _, found := hpaCondition(obj, conditionType); found {
+			observed += fmt.Sprintf(", %s %s", conditionType, status)
+			if len(reason) > 0 {
+				observed += fmt.Sprintf(" (%s)", reason)
+			}
+		}
+	}
+	return observed
+}
+
+// describe explains that the horizontal pod autoscaler is waited on to be stable
+func (w HPAStableWait) describe(observed string) string {
+	return fmt.Sprintf("horizontal pod autoscaler (last observed: %s) to be stable", observed)
+}
+
+func (w HPAStableWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	status, reason, message, found := hpaCondition(obj, "AbleToScale")
+	if found && strings.EqualFold(status, "False") && (reason == "FailedGetScale" || reason == "FailedUpdateScale") {
+		if len(message) > 0 {
+			reason += ": " + message
+		}
+		return false, newConditionUnmetError(info, "horizontal pod autoscaler cannot scale: %s", reason)
+	}
+	if found && !strings.EqualFold(status, "True") {
+		// backing off from a recent rescale
+		return false, nil
+	}
+	if observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found && observedGeneration < obj.GetGeneration() {
+		return false, nil
+	}
+	current, currentFound, _ := unstructured.NestedInt64(obj.Object, "status", "currentReplicas")
+	desired, desiredFound, _ := unstructured.NestedInt64(obj.Object, "status", "desiredReplicas")
+	return currentFound && desiredFound && current == desired, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestWaitForHPAStable(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}: "HorizontalPodAutoscalerList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	newHPA := func(current, desired int64) *unstructured.Unstructured {
+		obj := newUnstructured("autoscaling/v2", "HorizontalPodAutoscaler", "ns-foo", "name-foo")
+		unstructured.SetNestedField(obj.Object, current, "status", "currentReplicas")
+		unstructured.SetNestedField(obj.Object, desired, "status", "desiredReplicas")
+		return obj
+	}
+
+	tests := []struct {
+		name        string
+		listed      func() *unstructured.Unstructured
+		watched     func() *unstructured.Unstructured
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name: "stable",
+			listed: func() *unstructured.Unstructured {
+				return addConditionWithReason(addConditionWithReason(newHPA(3, 3), "AbleToScale", "True", "ReadyForNewScale"), "ScalingLimited", "False", "DesiredWithinRange")
+			},
+			expectedErr: None,
+		},
+		{
+			name: "stable at its maximum",
+			listed: func() *unstructured.Unstructured {
+				return addConditionWithReason(addConditionWithReason(newHPA(10, 10), "AbleToScale", "True", "ReadyForNewScale"), "ScalingLimited", "True", "TooManyReplicas")
+			},
+			expectedErr: None,
+		},
+		{
+			name: "stable with the conditions of autoscaling/v1",
+			listed: func() *unstructured.Unstructured {
+				obj := newHPA(3, 3)
+				obj.SetAnnotations(map[string]string{hpaConditionsAnnotation: `[{"type":"AbleToScale","status":"True","reason":"ReadyForNewScale"}]`})
+				return obj
+			},
+			expectedErr: None,
+		},
+		{
+			name: "settles while watching",
+			listed: func() *unstructured.Unstructured {
+				return addConditionWithReason(newHPA(2, 5), "AbleToScale", "True", "SucceededRescale")
+			},
+			watched: func() *unstructured.Unstructured {
+				return addConditionWithReason(newHPA(5, 5), "AbleToScale", "True", "ReadyForNewScale")
+			},
+			expectedErr: None,
+		},
+		{
+			name: "backing off",
+			listed: func() *unstructured.Unstructured {
+				return addConditionWithReason(newHPA(3, 3), "AbleToScale", "False", "BackoffBoth")
+			},
+			expectedErr: "timed out waiting for the condition on horizontalpodautoscalers/name-foo: horizontal pod autoscaler (last observed: current 3, desired 3 replicas, AbleToScale False (BackoffBoth)) to be stable",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name: "spec not observed yet",
+			listed: func() *unstructured.Unstructured {
+				obj := newHPA(3, 3)
+				obj.SetGeneration(2)
+				unstructured.SetNestedField(obj.Object, int64(1), "status", "observedGeneration")
+				return obj
+			},
+			expectedErr: "timed out waiting for the condition on horizontalpodautoscalers/name-foo",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name: "cannot scale",
+			listed: func() *unstructured.Unstructured {
+				return addConditionWithReason(newHPA(2, 5), "AbleToScale", "False", "FailedGetScale")
+			},
+			expectedErr: "condition unsatisfied on horizontalpodautoscalers/name-foo: horizontal pod autoscaler cannot scale: FailedGetScale",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name: "cannot scale while watching",
+			listed: func() *unstructured.Unstructured {
+				return newHPA(2, 5)
+			},
+			watched: func() *unstructured.Unstructured {
+				return addConditionWithReason(newHPA(2, 5), "AbleToScale", "False", "FailedUpdateScale")
+			},
+			expectedErr: "condition unsatisfied on horizontalpodautoscalers/name-foo: horizontal pod autoscaler cannot scale: FailedUpdateScale",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "horizontalpodautoscalers", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(test.listed()), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("horizontalpodautoscalers", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(test.watched())
+					return true, fakeWatch, nil
+				})
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: HPAStableWait{}.IsHPAStable,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"syscall"
+)
+
+// interruptSignals stop the wait on the first of them, and the command on the second
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// cancelOnInterrupt returns a context which is cancelled on the first signal received on
+// signals, and a function which returns true once it has been. The second signal calls exit
+// with ExitCodeInterrupted right away.
+func cancelOnInterrupt(ctx context.Context, signals <-chan os.Signal, exit func(code int)) (context.Context, func() bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	var interrupted int32
+	go func() {
+		if _, ok := <-signals; !ok {
+			return
+		}
+		atomic.StoreInt32(&interrupted, 1)
+		cancel()
+		if _, ok := <-signals; !ok {
+			return
+		}
+		exit(ExitCodeInterrupted)
+	}()
+	return ctx, func() bool { return atomic.LoadInt32(&interrupted) == 1 }
+}
+
+// runInterruptible runs the wait until it is over or a signal is received on signals. The
+// first signal stops the wait, reports the last state observed on every resource that has not
+// met the condition, unless the failure is reported as JSON, and returns an InterruptedError.
+// The second one calls exit right away.
+func (o *WaitOptions) runInterruptible(signals <-chan os.Signal, exit func(code int)) (Result, error) {
+	ctx, interrupted := cancelOnInterrupt(context.Background(), signals, exit)
+	result, err := o.Wait(ctx)
+	if o.PrintResourceVersion {
+		o.printResourceVersions(result)
+	}
+	if o.PrintSummary {
+		o.printSummary(result)
+	}
+	if err == nil || !interrupted() {
+		return result, err
+	}
+	if o.ErrorFormat != ErrorFormatJSON {
+		for _, status := range result.Resources {
+			if !status.Met && status.Err != nil {
+				fmt.Fprintf(o.ErrOut, "%v\n", status.Err)
+			}
+		}
+	}
+	return result, &InterruptedError{Met: len(result.Satisfied), Matched: result.Matched}
+}
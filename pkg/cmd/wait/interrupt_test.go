@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestCancelOnInterrupt(t *testing.T) {
+	signals := make(chan os.Signal, 2)
+	exited := make(chan int, 1)
+	ctx, interrupted := cancelOnInterrupt(context.Background(), signals, func(code int) { exited <- code })
+	if interrupted() {
+		t.Fatal("expected no interruption before a signal")
+	}
+
+	signals <- os.Interrupt
+	select {
+	case <-ctx.Done():
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("expected the first signal to cancel the context")
+	}
+	if !interrupted() {
+		t.Error("expected an interruption after the first signal")
+	}
+	select {
+	case code := <-exited:
+		t.Fatalf("expected the first signal not to exit, exited with %d", code)
+	default:
+	}
+
+	signals <- os.Interrupt
+	select {
+	case code := <-exited:
+		if code != ExitCodeInterrupted {
+			t.Errorf("expected to exit with %d, got %d", ExitCodeInterrupted, code)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("expected the second signal to exit")
+	}
+}
+
+func TestRunInterruptible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		pollInterval time.Duration
+		interrupt    bool
+		errorFormat  ErrorFormat
+
+		expectedErr    string
+		expectedErrOut string
+		exitCode       int
+	}{
+		{
+			name:           "interrupted while polling",
+			timeout:        time.Minute,
+			pollInterval:   time.Hour,
+			interrupt:      true,
+			expectedErr:    "interrupted after 0 of 1 resources met the condition",
+			expectedErrOut: "context canceled on theresource/name-foo: condition Ready (last observed: False) to be true\n",
+			exitCode:       ExitCodeInterrupted,
+		},
+		{
+			name:           "interrupted while watching",
+			timeout:        time.Minute,
+			interrupt:      true,
+			expectedErr:    "interrupted after 0 of 1 resources met the condition",
+			expectedErrOut: "context canceled on theresource/name-foo: condition Ready (last observed: False) to be true\n",
+			exitCode:       ExitCodeInterrupted,
+		},
+		{
+			name:         "interrupted with the failure reported as JSON",
+			timeout:      time.Minute,
+			pollInterval: time.Hour,
+			interrupt:    true,
+			errorFormat:  ErrorFormatJSON,
+			expectedErr:  "interrupted after 0 of 1 resources met the condition",
+			exitCode:     ExitCodeInterrupted,
+		},
+		{
+			name:         "not interrupted",
+			timeout:      10 * time.Millisecond,
+			pollInterval: time.Millisecond,
+			expectedErr:  "timed out waiting for the condition on theresource/name-foo",
+			exitCode:     ExitCodeTimeout,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signals := make(chan os.Signal, 2)
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if test.interrupt {
+					signals <- os.Interrupt
+				}
+				return true, newUnstructuredList(addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "False")), nil
+			})
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+				PollInterval:   test.pollInterval,
+				ErrorFormat:    test.errorFormat,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: ConditionalWait{conditionName: "Ready", conditionStatus: "true"}.IsConditionMet,
+				IOStreams:   streams,
+			}
+			_, err := o.runInterruptible(signals, func(code int) { t.Errorf("unexpected exit with %d", code) })
+
+			if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+				t.Fatalf("expected %q, got %v", test.expectedErr, err)
+			}
+			if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+			}
+			if errOut.String() != test.expectedErrOut {
+				t.Errorf("expected %q on stderr, got %q", test.expectedErrOut, errOut.String())
+			}
+		})
+	}
+}
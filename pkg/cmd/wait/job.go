@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// JobWait waits for a Job to complete, and stops waiting as soon as it has failed
+type JobWait struct{}
+
+// IsJobComplete is a conditionfunc for waiting on the Complete condition of a Job. It returns a
+// ConditionUnmetError once the Failed condition is true, since the job will not complete then.
+func (w JobWait) IsJobComplete(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the job to complete", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedStatus, w.describe)
+}
+
+// jobCondition returns the condition of the given type from the status of a Job, or of any
+// resource which reports conditions the same way, such as a CustomResourceDefinition
+func jobCondition(obj *unstructured.Unstructured, conditionType string) (map[string]interface{}, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, conditionUncast := range conditions {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(condition, "type"); name == conditionType {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+// observedStatus returns the number of active, succeeded and failed pods of the Job
+func (w JobWait) observedStatus(obj *unstructured.Unstructured) string {
+	active, _, _ := unstructured.NestedInt64(obj.Object, "status", "active")
+	succeeded, _, _ := unstructured.NestedInt64(obj.Object, "status", "succeeded")
+	failed, _, _ := unstructured.NestedInt64(obj.Object, "status", "failed")
+	return fmt.Sprintf("active %d, succeeded %d, failed %d", active, succeeded, failed)
+}
+
+// describe explains that the job is waited on to complete
+func (w JobWait) describe(observed string) string {
+	return fmt.Sprintf("job (last observed: %s) to complete", observed)
+}
+
+func (w JobWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	if condition, found := jobCondition(obj, "Failed"); found {
+		if status, _, _ := unstructured.NestedString(condition, "status"); strings.EqualFold(status, "True") {
+			reason, _, _ := unstructured.NestedString(condition, "reason")
+			if message, _, _ := unstructured.NestedString(condition, "message"); len(message) > 0 {
+				reason += ": " + message
+			}
+			return false, newConditionUnmetError(info, "job failed: %s", reason)
+		}
+	}
+	if condition, found := jobCondition(obj, "Complete"); found {
+		status, _, _ := unstructured.NestedString(condition, "status")
+		return strings.EqualFold(status, "True"), nil
+	}
+	return false, nil
+}
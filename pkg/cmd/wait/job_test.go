@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestWaitForJobComplete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "batch", Version: "v1", Resource: "jobs"}: "JobList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	newJob := func() *unstructured.Unstructured {
+		obj := newUnstructured("batch/v1", "Job", "ns-foo", "name-foo")
+		unstructured.SetNestedField(obj.Object, int64(1), "status", "active")
+		return obj
+	}
+
+	tests := []struct {
+		name        string
+		listed      func() *unstructured.Unstructured
+		watched     func() *unstructured.Unstructured
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name: "complete",
+			listed: func() *unstructured.Unstructured {
+				return addCondition(newJob(), "Complete", "True")
+			},
+			expectedErr: None,
+		},
+		{
+			name:   "completes while watching",
+			listed: newJob,
+			watched: func() *unstructured.Unstructured {
+				return addCondition(newJob(), "Complete", "True")
+			},
+			expectedErr: None,
+		},
+		{
+			name: "failed",
+			listed: func() *unstructured.Unstructured {
+				return addConditionWithReason(newJob(), "Failed", "True", "BackoffLimitExceeded")
+			},
+			expectedErr: "condition unsatisfied on jobs/name-foo: job failed: BackoffLimitExceeded",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:   "fails while watching",
+			listed: newJob,
+			watched: func() *unstructured.Unstructured {
+				return addConditionWithReason(newJob(), "Failed", "True", "DeadlineExceeded")
+			},
+			expectedErr: "condition unsatisfied on jobs/name-foo: job failed: DeadlineExceeded",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "still running",
+			listed:      newJob,
+			expectedErr: "timed out waiting for the condition on jobs/name-foo: job (last observed: active 1, succeeded 0, failed 0) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "jobs", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(test.listed()), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("jobs", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(test.watched())
+					return true, fakeWatch, nil
+				})
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: JobWait{}.IsJobComplete,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
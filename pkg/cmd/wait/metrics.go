@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Metrics is told about every wait, for programs which expose metrics about the waits they
+// run, for instance with Prometheus. The condition is the kind of the condition waited for,
+// such as "condition", "jsonpath" or "delete", or several of them separated by commas, so it
+// can be used as a label. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// WaitStarted is called when the wait on a resource starts
+	WaitStarted(condition string)
+	// WaitFinished is called when the wait on a resource ends, with how it ended, how many
+	// times the condition was checked and how long the wait took
+	WaitFinished(condition string, outcome WaitOutcome, polls int, elapsed time.Duration)
+}
+
+// WaitOutcome is how the wait on a resource ended, for Metrics
+type WaitOutcome string
+
+const (
+	// WaitOutcomeMet is used when the condition was met
+	WaitOutcomeMet WaitOutcome = "met"
+	// WaitOutcomeTimeout is used when the timeout was reached before the condition was met
+	WaitOutcomeTimeout WaitOutcome = "timeout"
+	// WaitOutcomeUnmet is used when the condition can no longer be met
+	WaitOutcomeUnmet WaitOutcome = "unmet"
+	// WaitOutcomeNotMet is used when the condition was checked once and was not met
+	WaitOutcomeNotMet WaitOutcome = "not-met"
+	// WaitOutcomeMaxPolls is used when the condition was checked WaitOptions.MaxPolls times
+	// without being met
+	WaitOutcomeMaxPolls WaitOutcome = "max-polls"
+	// WaitOutcomeCancelled is used when the wait was stopped, for instance because another
+	// resource met the condition in WaitModeAny
+	WaitOutcomeCancelled WaitOutcome = "cancelled"
+	// WaitOutcomeRelist is used when the condition asked for the resources to be looked up
+	// again with ErrRelist, after which the resource is waited on anew
+	WaitOutcomeRelist WaitOutcome = "relist"
+	// WaitOutcomeError is used for any other error
+	WaitOutcomeError WaitOutcome = "error"
+)
+
+// waitOutcomeFor returns how a wait which returned met and err ended
+func waitOutcomeFor(met bool, err error) WaitOutcome {
+	if met {
+		return WaitOutcomeMet
+	}
+	switch exitCodeFor(err) {
+	case ExitCodeTimeout:
+		return WaitOutcomeTimeout
+	case ExitCodeConditionUnmet:
+		return WaitOutcomeUnmet
+	case ExitCodeNotMet:
+		return WaitOutcomeNotMet
+	case ExitCodeMaxPollsExceeded:
+		return WaitOutcomeMaxPolls
+	case ExitCodeInterrupted:
+		return WaitOutcomeCancelled
+	}
+	if errors.Is(err, context.Canceled) {
+		return WaitOutcomeCancelled
+	}
+	return WaitOutcomeError
+}
+
+// conditionKindLabel returns the kinds of the conditions, as given to Metrics
+func conditionKindLabel(conditions []string) string {
+	kinds := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		if hasCountCondition([]string{condition}) {
+			kinds = append(kinds, "count")
+			continue
+		}
+		spec, err := parseCondition(condition)
+		if err != nil {
+			kinds = append(kinds, "unknown")
+			continue
+		}
+		kinds = append(kinds, string(spec.kind))
+	}
+	return strings.Join(kinds, ",")
+}
+
+// metricsCondition returns the kinds of the conditions waited for, as given to Metrics
+func (o *WaitOptions) metricsCondition() string {
+	if len(o.conditionKinds) > 0 {
+		return o.conditionKinds
+	}
+	if aggregate := o.aggregate(); aggregate != nil {
+		return conditionKindLabel([]string{aggregate.String()})
+	}
+	return conditionKindLabel([]string{o.ForCondition})
+}
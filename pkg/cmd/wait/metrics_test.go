@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// fakeMetrics records the waits it is told about
+type fakeMetrics struct {
+	mu       sync.Mutex
+	inFlight int
+	finished []string
+}
+
+func (m *fakeMetrics) WaitStarted(condition string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight++
+}
+
+func (m *fakeMetrics) WaitFinished(condition string, outcome WaitOutcome, polls int, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+	m.finished = append(m.finished, fmt.Sprintf("%s %s %d", condition, outcome, polls))
+}
+
+func TestWaitMetrics(t *testing.T) {
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	conditionFnReturning := func(done bool, err error) ConditionFunc {
+		return func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+			o.recordProgress(info, time.Now(), "", done)
+			return info.Object, done, err
+		}
+	}
+
+	tests := []struct {
+		name         string
+		forCondition string
+		conditionFn  ConditionFunc
+		count        *CountWait
+
+		expected []string
+	}{
+		{
+			name:         "met",
+			forCondition: "condition=Ready",
+			conditionFn:  conditionFnReturning(true, nil),
+			expected:     []string{"condition met 1"},
+		},
+		{
+			name:         "timeout",
+			forCondition: "jsonpath={.status.phase}=Running",
+			conditionFn:  conditionFnReturning(false, wait.ErrWaitTimeout),
+			expected:     []string{"jsonpath timeout 1"},
+		},
+		{
+			name:         "unmet",
+			forCondition: "delete",
+			conditionFn:  conditionFnReturning(false, nil),
+			expected:     []string{"delete unmet 1"},
+		},
+		{
+			name:         "not met",
+			forCondition: "condition=Ready",
+			conditionFn:  conditionFnReturning(false, &NotMetError{}),
+			expected:     []string{"condition not-met 1"},
+		},
+		{
+			name:         "cancelled",
+			forCondition: "condition=Ready",
+			conditionFn:  conditionFnReturning(false, context.Canceled),
+			expected:     []string{"condition cancelled 1"},
+		},
+		{
+			name:         "error",
+			forCondition: "condition=Ready",
+			conditionFn:  conditionFnReturning(false, errors.New("boom")),
+			expected:     []string{"condition error 1"},
+		},
+		{
+			name:         "count",
+			forCondition: "count=1",
+			count:        &CountWait{operator: "=", count: 1},
+			expected:     []string{"count met 1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := &fakeMetrics{}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        time.Second,
+				ForCondition:   test.forCondition,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: test.conditionFn,
+				Count:       test.count,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+				Metrics:     metrics,
+			}
+			o.Wait(context.Background())
+
+			if metrics.inFlight != 0 {
+				t.Errorf("expected no waits in flight, got %d", metrics.inFlight)
+			}
+			if fmt.Sprint(metrics.finished) != fmt.Sprint(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, metrics.finished)
+			}
+		})
+	}
+}
+
+func TestConditionKindLabel(t *testing.T) {
+	tests := []struct {
+		conditions []string
+		expected   string
+	}{
+		{conditions: []string{"delete"}, expected: "delete"},
+		{conditions: []string{"condition=Ready", "jsonpath={.status.phase}=Running"}, expected: "condition,jsonpath"},
+		{conditions: []string{"count>=3"}, expected: "count"},
+		{conditions: []string{"nonsense"}, expected: "unknown"},
+	}
+	for _, test := range tests {
+		if got := conditionKindLabel(test.conditions); got != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.conditions, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// notifyTimeout bounds how long posting the outcome of a wait to --notify-url may take
+const notifyTimeout = 10 * time.Second
+
+// Notification is posted as JSON to --notify-url once the wait is over, whether or not the
+// condition was met.
+type Notification struct {
+	// Outcome is how the wait ended as a whole
+	Outcome WaitOutcome `json:"outcome"`
+	// Error is the error the wait failed with, if it failed
+	Error string `json:"error,omitempty"`
+	// Matched is the number of resources found
+	Matched int `json:"matched"`
+	// ElapsedSeconds is the total time spent waiting
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+	// Resources holds the outcome of the wait on every resource. It is empty for a count
+	// condition.
+	Resources []ResourceNotification `json:"resources,omitempty"`
+}
+
+// ResourceNotification is the outcome of the wait on one resource, in a Notification
+type ResourceNotification struct {
+	// Resource is the resource type and name, e.g. "pods/foo"
+	Resource string `json:"resource"`
+	// Namespace is the namespace of the resource, if it is namespaced
+	Namespace string `json:"namespace,omitempty"`
+	// Outcome is how the wait on the resource ended
+	Outcome WaitOutcome `json:"outcome"`
+	// ElapsedSeconds is how long the resource was waited on
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// notificationFor returns the Notification for a wait which returned result and err
+func notificationFor(result Result, err error) Notification {
+	notification := Notification{
+		Outcome:        waitOutcomeFor(err == nil, err),
+		Matched:        result.Matched,
+		ElapsedSeconds: result.Elapsed.Seconds(),
+	}
+	if err != nil {
+		notification.Error = err.Error()
+	}
+	for _, status := range result.Resources {
+		notification.Resources = append(notification.Resources, ResourceNotification{
+			Resource:       status.Resource + "/" + status.Name,
+			Namespace:      status.Namespace,
+			Outcome:        waitOutcomeFor(status.Met, status.Err),
+			ElapsedSeconds: status.Elapsed.Seconds(),
+		})
+	}
+	return notification
+}
+
+// validateNotifyURL returns an error unless rawURL is an absolute http or https URL
+func validateNotifyURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("--notify-url must be an http or https URL, got %q", rawURL)
+	}
+	return nil
+}
+
+// newNotifyHook returns a CompletionHook which posts the Notification of the wait to rawURL
+// with client
+func newNotifyHook(rawURL string, client *http.Client) func(Result, error) error {
+	return func(result Result, err error) error {
+		body, marshalErr := json.Marshal(notificationFor(result, err))
+		if marshalErr != nil {
+			return marshalErr
+		}
+		resp, postErr := client.Post(rawURL, "application/json", bytes.NewReader(body))
+		if postErr != nil {
+			return fmt.Errorf("unable to notify of the outcome of the wait: %v", postErr)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unable to notify %s of the outcome of the wait: %s", rawURL, resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestNotifyHook(t *testing.T) {
+	result := Result{
+		Matched: 2,
+		Elapsed: 3 * time.Second,
+		Resources: []ResourceStatus{
+			{Resource: "pods", Namespace: "ns-foo", Name: "foo", Met: true, Elapsed: time.Second},
+			{Resource: "pods", Namespace: "ns-foo", Name: "bar", Err: &TimeoutError{Resource: "pods", Name: "bar"}, Elapsed: 2 * time.Second},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+
+		expected    Notification
+		expectedErr string
+	}{
+		{
+			name:       "notified",
+			statusCode: http.StatusNoContent,
+			err:        &TimeoutError{Resource: "pods", Name: "bar"},
+			expected: Notification{
+				Outcome:        WaitOutcomeTimeout,
+				Error:          "timed out waiting for the condition on pods/bar",
+				Matched:        2,
+				ElapsedSeconds: 3,
+				Resources: []ResourceNotification{
+					{Resource: "pods/foo", Namespace: "ns-foo", Outcome: WaitOutcomeMet, ElapsedSeconds: 1},
+					{Resource: "pods/bar", Namespace: "ns-foo", Outcome: WaitOutcomeTimeout, ElapsedSeconds: 2},
+				},
+			},
+			expectedErr: None,
+		},
+		{
+			name:        "rejected",
+			statusCode:  http.StatusInternalServerError,
+			expectedErr: "of the outcome of the wait: 500 Internal Server Error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posted []Notification
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				var notification Notification
+				if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("unexpected %s request with content type %q", req.Method, req.Header.Get("Content-Type"))
+				}
+				if err := json.NewDecoder(req.Body).Decode(&notification); err != nil {
+					t.Error(err)
+				}
+				posted = append(posted, notification)
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			err := newNotifyHook(server.URL, server.Client())(result, test.err)
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if len(posted) != 1 || !reflect.DeepEqual(posted[0], test.expected) {
+				t.Errorf("expected %+v to be posted once, got %+v", test.expected, posted)
+			}
+		})
+	}
+}
+
+func TestValidateNotifyURL(t *testing.T) {
+	for _, rawURL := range []string{"http://localhost:8080/hook", "https://example.com/waits"} {
+		if err := validateNotifyURL(rawURL); err != nil {
+			t.Errorf("%s: %v", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{"example.com/waits", "ftp://example.com", "http://", "://"} {
+		if err := validateNotifyURL(rawURL); err == nil {
+			t.Errorf("%s: expected an error", rawURL)
+		}
+	}
+}
+
+func TestWaitCompletionHook(t *testing.T) {
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		done    bool
+		hookErr error
+
+		expectedErr     string
+		expectedWarning string
+	}{
+		{
+			name:        "met",
+			done:        true,
+			expectedErr: None,
+		},
+		{
+			name:        "unmet",
+			expectedErr: "condition unsatisfied on theresource/name-foo",
+		},
+		{
+			name:            "hook fails",
+			done:            true,
+			hookErr:         errors.New("unable to notify"),
+			expectedErr:     None,
+			expectedWarning: "warning: unable to notify\n",
+		},
+		{
+			name:            "hook fails on an unmet condition",
+			hookErr:         errors.New("unable to notify"),
+			expectedErr:     "condition unsatisfied on theresource/name-foo",
+			expectedWarning: "warning: unable to notify\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				calls     int
+				hookedRes Result
+				hookedErr error
+			)
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        time.Second,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					o.recordProgress(info, time.Now(), "", test.done)
+					return info.Object, test.done, nil
+				},
+				CompletionHook: func(result Result, err error) error {
+					calls++
+					hookedRes, hookedErr = result, err
+					return test.hookErr
+				},
+				IOStreams: streams,
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if calls != 1 {
+				t.Fatalf("expected the hook to be called once, got %d", calls)
+			}
+			if hookedErr != err || !reflect.DeepEqual(hookedRes, result) {
+				t.Errorf("expected the hook to be called with %+v and %v, got %+v and %v", result, err, hookedRes, hookedErr)
+			}
+			if hookedRes.Polls != 1 {
+				t.Errorf("expected the hook to be called with 1 poll, got %d", hookedRes.Polls)
+			}
+			if errOut.String() != test.expectedWarning {
+				t.Errorf("expected %q on stderr, got %q", test.expectedWarning, errOut.String())
+			}
+		})
+	}
+}
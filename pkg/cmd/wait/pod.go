@@ -0,0 +1,299 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// ContainersReadyWait waits for the containers of a pod to be ready. Init containers are not
+// counted.
+type ContainersReadyWait struct {
+	// count is the number of containers which must be ready. Zero means all of them.
+	count int
+}
+
+// IsContainersReady is a conditionfunc for waiting on the containers of a pod to be ready
+func (w ContainersReadyWait) IsContainersReady(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	condMet := eventCondition(o.ErrOut, "the containers to be ready", false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedReadiness, w.describe)
+}
+
+// containerReadiness returns the names of the containers in the pod spec, split by whether
+// their status reports them as ready. Containers without a status yet are not ready.
+func containerReadiness(obj *unstructured.Unstructured) (ready, notReady []string) {
+	readyStatus := map[string]bool{}
+	statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+	for _, status := range statuses {
+		status, ok := status.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(status, "name")
+		isReady, _, _ := unstructured.NestedBool(status, "ready")
+		readyStatus[name] = isReady
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "containers")
+	for _, container := range containers {
+		container, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		if readyStatus[name] {
+			ready = append(ready, name)
+		} else {
+			notReady = append(notReady, name)
+		}
+	}
+	return ready, notReady
+}
+
+// observedReadiness returns the number of ready containers and the names of the others
+func (w ContainersReadyWait) observedReadiness(obj *unstructured.Unstructured) string {
+	ready, notReady := containerReadiness(obj)
+	observed := fmt.Sprintf("%d/%d ready", len(ready), len(ready)+len(notReady))
+	if len(notReady) > 0 {
+		observed += ", not ready: " + strings.Join(notReady, ",")
+	}
+	return observed
+}
+
+// describe explains how many containers are waited on to be ready
+func (w ContainersReadyWait) describe(observed string) string {
+	if w.count > 0 {
+		return fmt.Sprintf("%d containers (last observed: %s) to be ready", w.count, observed)
+	}
+	return fmt.Sprintf("all containers (last observed: %s) to be ready", observed)
+}
+
+func (w ContainersReadyWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	ready, notReady := containerReadiness(obj)
+	if w.count > 0 {
+		return len(ready) >= w.count, nil
+	}
+	return len(ready) > 0 && len(notReady) == 0, nil
+}
+
+// ContainerReadyWait waits for one container of a pod, given by name, to be ready, whatever the
+// readiness of the others. It stops waiting right away if the pod has no such container, which
+// is more likely a typo than a container yet to be added.
+type ContainerReadyWait struct {
+	// container is the name of the container
+	container string
+}
+
+// IsContainerReady is a conditionfunc for waiting on a container of a pod to be ready. It returns
+// a ConditionUnmetError if the container is not in the spec of the pod.
+func (w ContainerReadyWait) IsContainerReady(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the container to be ready", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedState, w.describe)
+}
+
+// containerStatus returns the status of the named container of the pod, or nil if it has none
+// yet
+func containerStatus(obj *unstructured.Unstructured, container string) map[string]interface{} {
+	statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+	for _, status := range statuses {
+		status, ok := status.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(status, "name"); name == container {
+			return status
+		}
+	}
+	return nil
+}
+
+// observedState returns the state of the container, with the reason it is waiting or
+// terminated, and whether it is ready
+func (w ContainerReadyWait) observedState(obj *unstructured.Unstructured) string {
+	status := containerStatus(obj, w.container)
+	if status == nil {
+		return "no status"
+	}
+	ready, _, _ := unstructured.NestedBool(status, "ready")
+	readiness := "not ready"
+	if ready {
+		readiness = "ready"
+	}
+	if waiting, found, _ := unstructured.NestedMap(status, "state", "waiting"); found {
+		if reason, _, _ := unstructured.NestedString(waiting, "reason"); len(reason) > 0 {
+			return fmt.Sprintf("waiting: %s, %s", reason, readiness)
+		}
+		return "waiting, " + readiness
+	}
+	if terminated, found, _ := unstructured.NestedMap(status, "state", "terminated"); found {
+		exitCode, _, _ := unstructured.NestedInt64(terminated, "exitCode")
+		if reason, _, _ := unstructured.NestedString(terminated, "reason"); len(reason) > 0 {
+			return fmt.Sprintf("terminated: %s with exit code %d, %s", reason, exitCode, readiness)
+		}
+		return fmt.Sprintf("terminated with exit code %d, %s", exitCode, readiness)
+	}
+	if _, found, _ := unstructured.NestedMap(status, "state", "running"); found {
+		return "running, " + readiness
+	}
+	return readiness
+}
+
+// describe explains which container is waited on to be ready
+func (w ContainerReadyWait) describe(observed string) string {
+	return fmt.Sprintf("container %s (last observed: %s) to be ready", w.container, observed)
+}
+
+func (w ContainerReadyWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	var names []string
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "containers")
+	for _, container := range containers {
+		container, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		if name == w.container {
+			ready, _, _ := unstructured.NestedBool(containerStatus(obj, w.container), "ready")
+			return ready, nil
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return false, newConditionUnmetError(info, "the pod has no container %s", w.container)
+	}
+	return false, newConditionUnmetError(info, "the pod has no container %s, its containers are %s", w.container, strings.Join(names, ","))
+}
+
+// InitCompleteWait waits for the init containers of a pod to have terminated successfully, and
+// stops waiting as soon as one of them has failed
+type InitCompleteWait struct {
+	// count is the number of init containers which must have succeeded. Zero means all of them.
+	count int
+}
+
+// initContainerState is the state of an init container, as reported by its status
+type initContainerState struct {
+	name string
+	// state is "succeeded", "failed", "running" or "waiting". Init containers without a status
+	// yet are waiting.
+	state    string
+	exitCode int64
+}
+
+// IsInitComplete is a conditionfunc for waiting on the init containers of a pod to exit with
+// code 0. It returns a ConditionUnmetError once one of them has terminated with another code:
+// the pod may retry it depending on its restart policy, but the wait is mostly the sign that
+// something went wrong.
+func (w InitCompleteWait) IsInitComplete(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the init containers to complete", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedStates, w.describe)
+}
+
+// initContainerStates returns the state of the init containers in the pod spec, in their order
+func initContainerStates(obj *unstructured.Unstructured) []initContainerState {
+	statuses := map[string]map[string]interface{}{}
+	initContainerStatuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "initContainerStatuses")
+	for _, status := range initContainerStatuses {
+		status, ok := status.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(status, "name")
+		statuses[name] = status
+	}
+	var states []initContainerState
+	initContainers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "initContainers")
+	for _, container := range initContainers {
+		container, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		state := initContainerState{name: name, state: "waiting"}
+		status := statuses[name]
+		if exitCode, found, _ := unstructured.NestedInt64(status, "state", "terminated", "exitCode"); found {
+			state.exitCode = exitCode
+			state.state = "succeeded"
+			if exitCode != 0 {
+				state.state = "failed"
+			}
+		} else if _, found, _ := unstructured.NestedMap(status, "state", "running"); found {
+			state.state = "running"
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// observedStates returns the number of init containers which succeeded and the state of the
+// others, with the exit code of the failed ones
+func (w InitCompleteWait) observedStates(obj *unstructured.Unstructured) string {
+	states := initContainerStates(obj)
+	succeeded := 0
+	var others []string
+	for _, state := range states {
+		switch state.state {
+		case "succeeded":
+			succeeded++
+		case "failed":
+			others = append(others, fmt.Sprintf("%s failed with exit code %d", state.name, state.exitCode))
+		default:
+			others = append(others, fmt.Sprintf("%s %s", state.name, state.state))
+		}
+	}
+	observed := fmt.Sprintf("%d/%d succeeded", succeeded, len(states))
+	if len(others) > 0 {
+		observed += ", " + strings.Join(others, ", ")
+	}
+	return observed
+}
+
+// describe explains how many init containers are waited on to succeed
+func (w InitCompleteWait) describe(observed string) string {
+	if w.count > 0 {
+		return fmt.Sprintf("%d init containers (last observed: %s) to complete", w.count, observed)
+	}
+	return fmt.Sprintf("all init containers (last observed: %s) to complete", observed)
+}
+
+func (w InitCompleteWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	states := initContainerStates(obj)
+	succeeded := 0
+	for _, state := range states {
+		switch state.state {
+		case "succeeded":
+			succeeded++
+		case "failed":
+			return false, newConditionUnmetError(info, "init container %s terminated with exit code %d", state.name, state.exitCode)
+		}
+	}
+	if w.count > 0 {
+		return succeeded >= w.count, nil
+	}
+	return succeeded == len(states), nil
+}
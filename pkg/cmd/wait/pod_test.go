@@ -0,0 +1,409 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestWaitForContainersReady(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		ready     map[string]bool
+
+		expectedErr string
+	}{
+		{
+			name:        "all containers ready",
+			condition:   "containers-ready",
+			ready:       map[string]bool{"app": true, "sidecar": true},
+			expectedErr: None,
+		},
+		{
+			name:        "init containers are not counted",
+			condition:   "containers-ready",
+			ready:       map[string]bool{"init": false, "app": true, "sidecar": true},
+			expectedErr: None,
+		},
+		{
+			name:        "a container not ready",
+			condition:   "containers-ready",
+			ready:       map[string]bool{"app": true, "sidecar": false},
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: all containers (last observed: 1/2 ready, not ready: sidecar) to be ready",
+		},
+		{
+			name:        "a container without a status",
+			condition:   "containers-ready",
+			ready:       map[string]bool{"app": true},
+			expectedErr: "not ready: sidecar",
+		},
+		{
+			name:        "enough containers ready",
+			condition:   "containers-ready=1",
+			ready:       map[string]bool{"app": false, "sidecar": true},
+			expectedErr: None,
+		},
+		{
+			name:        "not enough containers ready",
+			condition:   "containers-ready=2",
+			ready:       map[string]bool{"app": false, "sidecar": true},
+			expectedErr: "2 containers (last observed: 1/2 ready, not ready: app) to be ready",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				unstructured.SetNestedSlice(obj.Object, []interface{}{
+					map[string]interface{}{"name": "init"},
+				}, "spec", "initContainers")
+				unstructured.SetNestedSlice(obj.Object, []interface{}{
+					map[string]interface{}{"name": "app"},
+					map[string]interface{}{"name": "sidecar"},
+				}, "spec", "containers")
+				var statuses []interface{}
+				for _, name := range []string{"app", "sidecar"} {
+					if ready, ok := test.ready[name]; ok {
+						statuses = append(statuses, map[string]interface{}{"name": name, "ready": ready})
+					}
+				}
+				unstructured.SetNestedSlice(obj.Object, statuses, "status", "containerStatuses")
+				if ready, ok := test.ready["init"]; ok {
+					unstructured.SetNestedSlice(obj.Object, []interface{}{
+						map[string]interface{}{"name": "init", "ready": ready},
+					}, "status", "initContainerStatuses")
+				}
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForContainerReady(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}: "PodList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			},
+			Name:      "busybox1",
+			Namespace: "ns-foo",
+		},
+	}
+	newPod := func(statuses ...interface{}) *unstructured.Unstructured {
+		obj := newUnstructured("v1", "Pod", "ns-foo", "busybox1")
+		unstructured.SetNestedSlice(obj.Object, []interface{}{
+			map[string]interface{}{"name": "app"},
+			map[string]interface{}{"name": "sidecar"},
+		}, "spec", "containers")
+		unstructured.SetNestedSlice(obj.Object, statuses, "status", "containerStatuses")
+		return obj
+	}
+	status := func(name string, ready bool, state map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": name, "ready": ready, "state": state}
+	}
+	running := map[string]interface{}{"running": map[string]interface{}{"startedAt": "2021-11-17T00:00:00Z"}}
+	crashLooping := map[string]interface{}{"waiting": map[string]interface{}{"reason": "CrashLoopBackOff"}}
+	terminated := map[string]interface{}{"terminated": map[string]interface{}{"reason": "Error", "exitCode": int64(137)}}
+
+	tests := []struct {
+		name      string
+		condition string
+		listed    *unstructured.Unstructured
+		watched   *unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "ready while another is not",
+			condition:   "container-ready=app",
+			listed:      newPod(status("app", true, running), status("sidecar", false, crashLooping)),
+			expectedErr: None,
+		},
+		{
+			name:        "ready while watching",
+			condition:   "container-ready=sidecar",
+			listed:      newPod(status("app", true, running), status("sidecar", false, running)),
+			watched:     newPod(status("app", true, running), status("sidecar", true, running)),
+			expectedErr: None,
+		},
+		{
+			name:        "waiting",
+			condition:   "container-ready=sidecar",
+			listed:      newPod(status("app", true, running), status("sidecar", false, crashLooping)),
+			expectedErr: "timed out waiting for the condition on pods/busybox1: container sidecar (last observed: waiting: CrashLoopBackOff, not ready) to be ready",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "running",
+			condition:   "container-ready=sidecar",
+			listed:      newPod(status("app", true, running), status("sidecar", false, running)),
+			expectedErr: "container sidecar (last observed: running, not ready) to be ready",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "terminated",
+			condition:   "container-ready=app",
+			listed:      newPod(status("app", false, terminated), status("sidecar", true, running)),
+			expectedErr: "container app (last observed: terminated: Error with exit code 137, not ready) to be ready",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "no status yet",
+			condition:   "container-ready=sidecar",
+			listed:      newPod(status("app", true, running)),
+			expectedErr: "container sidecar (last observed: no status) to be ready",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "no such container",
+			condition:   "container-ready=ap",
+			listed:      newPod(status("app", true, running), status("sidecar", true, running)),
+			expectedErr: "condition unsatisfied on pods/busybox1: the pod has no container ap, its containers are app,sidecar",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(test.listed), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("pods", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(test.watched)
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForInitComplete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	terminated := func(exitCode int64) map[string]interface{} {
+		return map[string]interface{}{"terminated": map[string]interface{}{"exitCode": exitCode}}
+	}
+	running := map[string]interface{}{"running": map[string]interface{}{}}
+
+	tests := []struct {
+		name      string
+		condition string
+		states    map[string]map[string]interface{}
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "all init containers succeeded",
+			condition:   "init-complete",
+			states:      map[string]map[string]interface{}{"migrate": terminated(0), "seed": terminated(0)},
+			expectedErr: None,
+		},
+		{
+			name:        "an init container running",
+			condition:   "init-complete",
+			states:      map[string]map[string]interface{}{"migrate": terminated(0), "seed": running},
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: all init containers (last observed: 1/2 succeeded, seed running) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "an init container without a status",
+			condition:   "init-complete",
+			states:      map[string]map[string]interface{}{"migrate": running},
+			expectedErr: "all init containers (last observed: 0/2 succeeded, migrate running, seed waiting) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "an init container failed",
+			condition:   "init-complete",
+			states:      map[string]map[string]interface{}{"migrate": terminated(0), "seed": terminated(3)},
+			expectedErr: "condition unsatisfied on theresource/name-foo: init container seed terminated with exit code 3",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "enough init containers succeeded",
+			condition:   "init-complete=1",
+			states:      map[string]map[string]interface{}{"migrate": terminated(0), "seed": running},
+			expectedErr: None,
+		},
+		{
+			name:        "not enough init containers succeeded",
+			condition:   "init-complete=2",
+			states:      map[string]map[string]interface{}{"migrate": terminated(0), "seed": running},
+			expectedErr: "2 init containers (last observed: 1/2 succeeded, seed running) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				unstructured.SetNestedSlice(obj.Object, []interface{}{
+					map[string]interface{}{"name": "migrate"},
+					map[string]interface{}{"name": "seed"},
+				}, "spec", "initContainers")
+				var statuses []interface{}
+				for _, name := range []string{"migrate", "seed"} {
+					if state, ok := test.states[name]; ok {
+						statuses = append(statuses, map[string]interface{}{"name": name, "state": state})
+					}
+				}
+				unstructured.SetNestedSlice(obj.Object, statuses, "status", "initContainerStatuses")
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/klog/v2"
+)
+
+// ProgressEvent is written as a line of JSON to WaitOptions.ProgressWriter every time
+// a condition is checked against a resource.
+type ProgressEvent struct {
+	// Resource is the resource type and name, e.g. "pods/foo"
+	Resource string `json:"resource"`
+	// Namespace is the namespace of the resource, if it is namespaced
+	Namespace string `json:"namespace,omitempty"`
+	// Observed is the value the condition was checked against, for instance the value
+	// a JSONPath expression resolved to, if the condition reports one
+	Observed string `json:"observed,omitempty"`
+	// ElapsedSeconds is the time since the wait on this resource started
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+	// Done is true if the condition was met
+	Done bool `json:"done"`
+}
+
+// recordProgress counts a check of the condition, records the value observed for the summary
+// of the wait, logs it at -v=4 and writes a ProgressEvent to the ProgressWriter, if one is set.
+func (o *WaitOptions) recordProgress(info *resource.Info, start time.Time, observed string, done bool) {
+	if o.checks != nil {
+		atomic.AddInt64(o.checks, 1)
+	}
+	if o.observed != nil && len(observed) > 0 {
+		o.observed.Store(observed)
+	}
+	if klogV := klog.V(4); klogV.Enabled() {
+		klogV.Infof("Checked %s on %s/%s after %v: observed %q, met: %t",
+			o.ForCondition, info.Mapping.Resource.Resource, info.Name, o.clock().Since(start), observedForLog(info, observed), done)
+	}
+	if o.ProgressWriter == nil {
+		return
+	}
+	event := ProgressEvent{
+		Resource:       info.Mapping.Resource.Resource + "/" + info.Name,
+		Namespace:      info.Namespace,
+		Observed:       observed,
+		ElapsedSeconds: o.clock().Since(start).Seconds(),
+		Done:           done,
+	}
+	if err := json.NewEncoder(o.ProgressWriter).Encode(event); err != nil {
+		klog.V(1).Infof("unable to write wait progress: %v", err)
+	}
+}
+
+// observedForLog returns the observed value to log for the resource. The values observed on
+// Secrets are only logged at -v=10, since they are likely to be sensitive.
+func observedForLog(info *resource.Info, observed string) string {
+	isSecret := info.Mapping.Resource.Group == "" && info.Mapping.Resource.Resource == "secrets"
+	if isSecret && len(observed) > 0 && !klog.V(10).Enabled() {
+		return "<redacted>"
+	}
+	return observed
+}
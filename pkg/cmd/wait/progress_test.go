@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestWaitProgress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		fakeClient  func() *dynamicfakeclient.FakeDynamicClient
+		conditionFn func() ConditionFunc
+
+		expectedEvents []ProgressEvent
+	}{
+		{
+			name: "conditional wait",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, newUnstructuredList(addCondition(
+						newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+						"the-condition", "False",
+					)), nil
+				})
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Action(watch.Modified, addCondition(
+						newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+						"the-condition", "True",
+					))
+					return true, fakeWatch, nil
+				})
+				return fakeClient
+			},
+			conditionFn: func() ConditionFunc {
+				return ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet
+			},
+
+			expectedEvents: []ProgressEvent{
+				{Resource: "theresource/name-foo", Namespace: "ns-foo", Observed: "False"},
+				{Resource: "theresource/name-foo", Namespace: "ns-foo", Observed: "True", Done: true},
+			},
+		},
+		{
+			name: "jsonpath wait",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, newUnstructuredList(createUnstructured(t, podYAML)), nil
+				})
+				return fakeClient
+			},
+			conditionFn: func() ConditionFunc {
+				j, _ := newJSONPathParser("{.status.phase}")
+				return JSONPathWait{jsonPathCondition: "Running", jsonPathParser: j, errOut: ioutil.Discard}.IsJSONPathConditionMet
+			},
+
+			expectedEvents: []ProgressEvent{
+				{Resource: "theresource/name-foo", Namespace: "ns-foo", Observed: "Running", Done: true},
+			},
+		},
+		{
+			name: "deletion wait",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				return dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			},
+			conditionFn: func() ConditionFunc {
+				return IsDeleted
+			},
+
+			expectedEvents: []ProgressEvent{
+				{Resource: "theresource/name-foo", Namespace: "ns-foo", Done: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			progress := &bytes.Buffer{}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  test.fakeClient(),
+				Timeout:        10 * time.Second,
+
+				Printer:        printers.NewDiscardingPrinter(),
+				ConditionFn:    test.conditionFn(),
+				IOStreams:      genericclioptions.NewTestIOStreamsDiscard(),
+				ProgressWriter: progress,
+			}
+			if err := o.RunWait(); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(progress.String()), "\n")
+			if len(lines) != len(test.expectedEvents) {
+				t.Fatalf("expected %d progress events, got %q", len(test.expectedEvents), progress.String())
+			}
+			for i, line := range lines {
+				event := ProgressEvent{}
+				if err := json.Unmarshal([]byte(line), &event); err != nil {
+					t.Fatal(err)
+				}
+				event.ElapsedSeconds = 0
+				if event != test.expectedEvents[i] {
+					t.Errorf("expected %#v, got %#v", test.expectedEvents[i], event)
+				}
+			}
+		})
+	}
+}
+
+func TestObservedForLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource schema.GroupVersionResource
+		observed string
+
+		expected string
+	}{
+		{
+			name:     "pod",
+			resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			observed: "Running",
+			expected: "Running",
+		},
+		{
+			name:     "secret",
+			resource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"},
+			observed: "aHVudGVyMg==",
+			expected: "<redacted>",
+		},
+		{
+			name:     "secret without an observed value",
+			resource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"},
+			observed: "",
+			expected: "",
+		},
+		{
+			name:     "secrets of another group",
+			resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "secrets"},
+			observed: "Ready",
+			expected: "Ready",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info := &resource.Info{Mapping: &meta.RESTMapping{Resource: test.resource}, Name: "name-foo"}
+			if got := observedForLog(info, test.observed); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
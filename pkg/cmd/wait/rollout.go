@@ -0,0 +1,359 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+)
+
+// RolloutWait waits for the rollout of a Deployment, DaemonSet or StatefulSet to complete, as
+// kubectl rollout status does, and stops waiting as soon as it cannot, for instance because a
+// Deployment exceeded its progress deadline
+type RolloutWait struct{}
+
+// IsRolledOut is a conditionfunc for waiting on a rollout. It returns a ConditionUnmetError if
+// the rollout failed, or if the resource has no rollout status to wait on. Daemon sets are
+// waited on with a DaemonSetWait, and stateful sets with a StatefulSetWait.
+func (w RolloutWait) IsRolledOut(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	switch info.Mapping.GroupVersionKind.GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}:
+		return DaemonSetWait{}.IsDaemonSetRolledOut(ctx, info, o)
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		return StatefulSetWait{}.IsStatefulSetRolledOut(ctx, info, o)
+	}
+	viewer, err := polymorphichelpers.StatusViewerFor(info.Mapping.GroupVersionKind.GroupKind())
+	if err != nil {
+		return info.Object, false, newConditionUnmetError(info, "rollout only applies to deployments, daemonsets and statefulsets")
+	}
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		_, done, err := viewer.Status(obj, 0)
+		if err != nil {
+			return false, newConditionUnmetError(info, "%v", err)
+		}
+		return done, nil
+	}
+	observe := func(obj *unstructured.Unstructured) string {
+		status, _, err := viewer.Status(obj, 0)
+		if err != nil {
+			return err.Error()
+		}
+		return strings.TrimSpace(status)
+	}
+	condMet := eventCondition(o.ErrOut, "the rollout to complete", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, observe, w.describe)
+}
+
+// describe explains that the rollout is waited on to complete
+func (w RolloutWait) describe(observed string) string {
+	return fmt.Sprintf("rollout (last observed: %s) to complete", observed)
+}
+
+// DaemonSetWait waits for the rollout of a daemon set to complete on every node it is scheduled
+// on, and stops waiting as soon as it is scheduled on none
+type DaemonSetWait struct{}
+
+// IsDaemonSetRolledOut is a conditionfunc for waiting on the rollout of a daemon set: its
+// latest generation has been observed, and its pods are updated, ready and available on every
+// node it should run on. It returns a ConditionUnmetError once the daemon set is found not to
+// be scheduled on any node, which would otherwise count as rolled out right away while it is
+// mostly the sign of a node selector, affinity or toleration matching no node. It also returns
+// one for a daemon set which is not updated with the RollingUpdate strategy, since the rollout
+// of an OnDelete daemon set only progresses as its pods are deleted.
+func (w DaemonSetWait) IsDaemonSetRolledOut(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the rollout to complete", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedStatus, w.describe)
+}
+
+// observedStatus returns the number of pods of the daemon set which are desired, updated, ready
+// and available, and the generation it observed
+func (w DaemonSetWait) observedStatus(obj *unstructured.Unstructured) string {
+	status := func(field string) int64 {
+		value, _, _ := unstructured.NestedInt64(obj.Object, "status", field)
+		return value
+	}
+	return fmt.Sprintf("desired %d, updated %d, ready %d, available %d, observed generation %d of %d",
+		status("desiredNumberScheduled"), status("updatedNumberScheduled"), status("numberReady"), status("numberAvailable"),
+		status("observedGeneration"), obj.GetGeneration())
+}
+
+// describe explains that the rollout of the daemon set is waited on to complete
+func (w DaemonSetWait) describe(observed string) string {
+	return fmt.Sprintf("daemon set rollout (last observed: %s) to complete", observed)
+}
+
+func (w DaemonSetWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	if strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type"); len(strategy) > 0 && strategy != "RollingUpdate" {
+		return false, newConditionUnmetError(info, "rollout status is only available for the RollingUpdate strategy type, not %s", strategy)
+	}
+	if observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); observedGeneration < obj.GetGeneration() {
+		return false, nil
+	}
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	if desired == 0 {
+		return false, newConditionUnmetError(info, "daemon set is not scheduled on any node, check that its node selector, affinity and tolerations match some nodes")
+	}
+	for _, field := range []string{"updatedNumberScheduled", "numberReady", "numberAvailable"} {
+		if value, _, _ := unstructured.NestedInt64(obj.Object, "status", field); value < desired {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// RolloutSettledWait waits for a deployment to complete a rollout of another generation than the
+// one observed when the wait started, such as the one of an automatic rollback, so that the
+// rollout which was already complete then does not count
+type RolloutSettledWait struct {
+	// anyGeneration also accepts a rollout complete at the generation first observed
+	anyGeneration bool
+	// generations records the generation first observed on every deployment. It is shared by
+	// the copies of the RolloutSettledWait.
+	generations *generationTracker
+}
+
+// newRolloutSettledWait returns a RolloutSettledWait which records the generations it observes
+// from scratch
+func newRolloutSettledWait(anyGeneration bool) RolloutSettledWait {
+	return RolloutSettledWait{anyGeneration: anyGeneration, generations: &generationTracker{initial: map[string]int64{}}}
+}
+
+// IsRolloutSettled is a conditionfunc for waiting on a deployment to complete a rollout after
+// the first check, which records the generation of the deployment without meeting the
+// condition unless anyGeneration is set. It returns a ConditionUnmetError if the rollout
+// exceeds its progress deadline.
+func (w RolloutSettledWait) IsRolloutSettled(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	viewer, err := polymorphichelpers.StatusViewerFor(schema.GroupKind{Group: "apps", Kind: "Deployment"})
+	if err != nil {
+		return info.Object, false, err
+	}
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		initial := w.generations.initialGeneration(obj)
+		_, done, err := viewer.Status(obj, 0)
+		if err != nil {
+			return false, newConditionUnmetError(info, "%v", err)
+		}
+		return done && (w.anyGeneration || obj.GetGeneration() != initial), nil
+	}
+	observe := func(obj *unstructured.Unstructured) string {
+		status, _, err := viewer.Status(obj, 0)
+		if err != nil {
+			status = err.Error()
+		}
+		return fmt.Sprintf("generation %d, first observed %d: %s", obj.GetGeneration(), w.generations.initialGeneration(obj), strings.TrimSpace(status))
+	}
+	condMet := eventCondition(o.ErrOut, "the rollout to complete", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, observe, w.describe)
+}
+
+// describe explains which rollout is waited on to complete
+func (w RolloutSettledWait) describe(observed string) string {
+	if w.anyGeneration {
+		return fmt.Sprintf("rollout (last observed: %s) to complete", observed)
+	}
+	return fmt.Sprintf("rollout (last observed: %s) to complete at another generation than the first observed", observed)
+}
+
+// generationTracker records the generation of every object the first time it is checked, as
+// changeTracker does for the value of a JSONPath expression
+type generationTracker struct {
+	mu      sync.Mutex
+	initial map[string]int64
+}
+
+// initialGeneration returns the generation first observed on obj, which is the one of obj the
+// first time it is called for it. Objects are told apart by kind, namespace and name.
+func (t *generationTracker) initialGeneration(obj *unstructured.Unstructured) int64 {
+	key := fmt.Sprintf("%s/%s/%s", obj.GroupVersionKind().GroupKind(), obj.GetNamespace(), obj.GetName())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	initial, ok := t.initial[key]
+	if !ok {
+		initial = obj.GetGeneration()
+		t.initial[key] = initial
+	}
+	return initial
+}
+
+// StatefulSetWait waits for the rollout of a stateful set to complete, taking the partition of
+// its rolling update into account: only the pods with an ordinal at or above the partition are
+// updated, so a partitioned canary rollout is complete once those are.
+type StatefulSetWait struct{}
+
+// IsStatefulSetRolledOut is a conditionfunc for waiting on the rollout of a stateful set: its
+// latest generation has been observed, all its replicas are ready, and as many of them are
+// updated as the partition of its rolling update lets through. It returns a ConditionUnmetError
+// for a stateful set which is not updated with the RollingUpdate strategy, since the rollout of
+// an OnDelete stateful set only progresses as its pods are deleted.
+func (w StatefulSetWait) IsStatefulSetRolledOut(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, "the rollout to complete", false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedStatus, w.describe)
+}
+
+// statefulSetRollout returns the number of replicas of the stateful set, the partition of its
+// rolling update and the number of replicas that partition lets be updated
+func statefulSetRollout(obj *unstructured.Unstructured) (replicas, partition, toUpdate int64) {
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	partition, _, _ = unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	toUpdate = replicas - partition
+	if toUpdate < 0 {
+		toUpdate = 0
+	}
+	return replicas, partition, toUpdate
+}
+
+// observedStatus returns the partition of the stateful set, the number of its pods which are
+// updated and ready, and the generation it observed
+func (w StatefulSetWait) observedStatus(obj *unstructured.Unstructured) string {
+	replicas, partition, toUpdate := statefulSetRollout(obj)
+	status := func(field string) int64 {
+		value, _, _ := unstructured.NestedInt64(obj.Object, "status", field)
+		return value
+	}
+	return fmt.Sprintf("partition %d, updated %d of %d, ready %d of %d, observed generation %d of %d",
+		partition, status("updatedReplicas"), toUpdate, status("readyReplicas"), replicas,
+		status("observedGeneration"), obj.GetGeneration())
+}
+
+// describe explains that the rollout of the stateful set is waited on to complete
+func (w StatefulSetWait) describe(observed string) string {
+	return fmt.Sprintf("stateful set rollout (last observed: %s) to complete", observed)
+}
+
+func (w StatefulSetWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	if strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type"); len(strategy) > 0 && strategy != "RollingUpdate" {
+		return false, newConditionUnmetError(info, "rollout status is only available for the RollingUpdate strategy type, not %s", strategy)
+	}
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observedGeneration == 0 || observedGeneration < obj.GetGeneration() {
+		return false, nil
+	}
+	replicas, _, toUpdate := statefulSetRollout(obj)
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	return ready >= replicas && updated >= toUpdate, nil
+}
+
+// NewReplicaSetWait waits for the new replica set of a deployment, the one with the pod template
+// of the deployment, to have a number of ready replicas, as when verifying a canary
+type NewReplicaSetWait struct {
+	// count is the number of ready replicas required
+	count int
+}
+
+// replicaSetsMapping is the mapping of the replica sets of a Deployment
+var replicaSetsMapping = &meta.RESTMapping{
+	Resource:         schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"},
+	GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	Scope:            meta.RESTScopeNamespace,
+}
+
+// IsNewReplicaSetReady is a conditionfunc for waiting on the new replica set of a deployment to
+// have enough ready replicas. The replica sets the deployment controls are listed on every check
+// of the deployment, which is polled every --poll-interval, or every second, since a replica set
+// may become ready before its deployment reports it. It keeps waiting while the deployment has no
+// new replica set yet, and returns a ConditionUnmetError if the resource is not a Deployment.
+func (w NewReplicaSetWait) IsNewReplicaSetReady(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if info.Mapping.Resource.GroupResource() != (schema.GroupResource{Group: "apps", Resource: "deployments"}) {
+		return info.Object, false, newConditionUnmetError(info, "new-replicaset-ready only applies to deployments")
+	}
+	pollOptions := *o
+	if !pollOptions.polling() {
+		pollOptions.PollInterval = resourcesPollInterval
+	}
+	// newRS is the new replica set found on the last check, which is reported on timeout
+	var newRS *appsv1.ReplicaSet
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		rs, err := w.newReplicaSet(ctx, o, obj)
+		if err != nil && isTransientError(err) {
+			fmt.Fprintf(o.ErrOut, "error: An error occurred while listing the replica sets of the deployment: %v\n", err)
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		newRS = rs
+		return newRS != nil && int(newRS.Status.ReadyReplicas) >= w.count, nil
+	}
+	observe := func(*unstructured.Unstructured) string {
+		if newRS == nil {
+			return "no new replica set yet"
+		}
+		replicas := int32(1)
+		if newRS.Spec.Replicas != nil {
+			replicas = *newRS.Spec.Replicas
+		}
+		return fmt.Sprintf("replica set %s, %d ready of %d", newRS.Name, newRS.Status.ReadyReplicas, replicas)
+	}
+	condMet := eventCondition(o.ErrOut, "the new replica set to be ready", false, check)
+	return getObjAndCheckCondition(ctx, info, &pollOptions, condMet, check, observe, w.describe)
+}
+
+// newReplicaSet returns the replica set controlled by the deployment with the pod template of the
+// deployment, ignoring the pod-template-hash label the controller adds to it, or nil if the
+// deployment controller has not created it yet
+func (w NewReplicaSetWait) newReplicaSet(ctx context.Context, o *WaitOptions, obj *unstructured.Unstructured) (*appsv1.ReplicaSet, error) {
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+		return nil, fmt.Errorf("unable to read the deployment: %v", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of the deployment: %v", err)
+	}
+	list, err := o.DynamicClient.Resource(replicaSetsMapping.Resource).Namespace(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]*appsv1.ReplicaSet, 0, len(list.Items))
+	for i := range list.Items {
+		rs := &appsv1.ReplicaSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, rs); err != nil {
+			return nil, fmt.Errorf("unable to read the replica set %s: %v", list.Items[i].GetName(), err)
+		}
+		if metav1.IsControlledBy(rs, deployment) {
+			owned = append(owned, rs)
+		}
+	}
+	return deploymentutil.FindNewReplicaSet(deployment, owned), nil
+}
+
+// describe explains how many ready replicas the new replica set is waited on to have
+func (w NewReplicaSetWait) describe(observed string) string {
+	return fmt.Sprintf("new replica set of the deployment (last observed: %s) to have at least %d ready replicas", observed, w.count)
+}
@@ -0,0 +1,616 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+)
+
+func TestWaitForRollout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}:  "DeploymentList",
+		{Group: "apps", Version: "v1", Resource: "daemonsets"}:   "DaemonSetList",
+		{Group: "apps", Version: "v1", Resource: "statefulsets"}: "StatefulSetList",
+		{Version: "v1", Resource: "pods"}:                        "PodList",
+	}
+	newDeployment := func(generation, observedGeneration, updated, available int64, conditions ...interface{}) *unstructured.Unstructured {
+		obj := newUnstructuredWithGeneration("apps/v1", "Deployment", "ns-foo", "nginx", generation)
+		unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas")
+		obj.Object["status"] = map[string]interface{}{
+			"observedGeneration": observedGeneration,
+			"replicas":           updated,
+			"updatedReplicas":    updated,
+			"availableReplicas":  available,
+			"conditions":         conditions,
+		}
+		return obj
+	}
+	newDaemonSet := func(strategy string, generation, observedGeneration, desired, updated, ready, available int64) *unstructured.Unstructured {
+		obj := newUnstructuredWithGeneration("apps/v1", "DaemonSet", "ns-foo", "nginx", generation)
+		unstructured.SetNestedField(obj.Object, strategy, "spec", "updateStrategy", "type")
+		obj.Object["status"] = map[string]interface{}{
+			"observedGeneration":     observedGeneration,
+			"desiredNumberScheduled": desired,
+			"updatedNumberScheduled": updated,
+			"numberReady":            ready,
+			"numberAvailable":        available,
+		}
+		return obj
+	}
+	newStatefulSet := func(strategy string, partition, generation, observedGeneration, updated, ready int64) *unstructured.Unstructured {
+		obj := newUnstructuredWithGeneration("apps/v1", "StatefulSet", "ns-foo", "nginx", generation)
+		unstructured.SetNestedField(obj.Object, int64(4), "spec", "replicas")
+		unstructured.SetNestedField(obj.Object, strategy, "spec", "updateStrategy", "type")
+		if partition > 0 {
+			unstructured.SetNestedField(obj.Object, partition, "spec", "updateStrategy", "rollingUpdate", "partition")
+		}
+		obj.Object["status"] = map[string]interface{}{
+			"observedGeneration": observedGeneration,
+			"replicas":           int64(4),
+			"updatedReplicas":    updated,
+			"readyReplicas":      ready,
+		}
+		return obj
+	}
+	progressDeadlineExceeded := map[string]interface{}{
+		"type":   "Progressing",
+		"status": "False",
+		"reason": "ProgressDeadlineExceeded",
+	}
+
+	tests := []struct {
+		name    string
+		kind    schema.GroupVersionKind
+		obj     *unstructured.Unstructured
+		watched *unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "rolled out",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:         newDeployment(2, 2, 3, 3),
+			expectedErr: None,
+		},
+		{
+			name:        "rolled out while watching",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:         newDeployment(2, 2, 1, 1),
+			watched:     newDeployment(2, 2, 3, 3),
+			expectedErr: None,
+		},
+		{
+			name:        "replicas being updated",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:         newDeployment(2, 2, 1, 1),
+			expectedErr: `timed out waiting for the condition on deployments/nginx: rollout (last observed: Waiting for deployment "nginx" rollout to finish: 1 out of 3 new replicas have been updated...) to complete`,
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "updated replicas not available",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:         newDeployment(2, 2, 3, 2),
+			expectedErr: `2 of 3 updated replicas are available`,
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "new generation not observed",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:         newDeployment(3, 2, 3, 3),
+			expectedErr: "rollout (last observed: Waiting for deployment spec update to be observed...) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "progress deadline exceeded",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:         newDeployment(2, 2, 1, 1, progressDeadlineExceeded),
+			expectedErr: `condition unsatisfied on deployments/nginx: deployment "nginx" exceeded its progress deadline`,
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "daemon set rolled out",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("RollingUpdate", 1, 1, 2, 2, 2, 2),
+			expectedErr: None,
+		},
+		{
+			name:        "daemon set rolled out while watching",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("RollingUpdate", 2, 1, 2, 2, 2, 2),
+			watched:     newDaemonSet("RollingUpdate", 2, 2, 2, 2, 2, 2),
+			expectedErr: None,
+		},
+		{
+			name:        "daemon set pods not ready",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("RollingUpdate", 1, 1, 3, 3, 2, 3),
+			expectedErr: "timed out waiting for the condition on daemonsets/nginx: daemon set rollout (last observed: desired 3, updated 3, ready 2, available 3, observed generation 1 of 1) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "daemon set pods being updated",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("RollingUpdate", 1, 1, 3, 1, 3, 3),
+			expectedErr: "daemon set rollout (last observed: desired 3, updated 1, ready 3, available 3, observed generation 1 of 1) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "daemon set new generation not observed",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("RollingUpdate", 1, 0, 0, 0, 0, 0),
+			expectedErr: "daemon set rollout (last observed: desired 0, updated 0, ready 0, available 0, observed generation 0 of 1) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "daemon set scheduled on no node",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("RollingUpdate", 1, 1, 0, 0, 0, 0),
+			expectedErr: "condition unsatisfied on daemonsets/nginx: daemon set is not scheduled on any node, check that its node selector, affinity and tolerations match some nodes",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "daemon set updated on delete",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			obj:         newDaemonSet("OnDelete", 1, 1, 2, 2, 2, 2),
+			expectedErr: "condition unsatisfied on daemonsets/nginx: rollout status is only available for the RollingUpdate strategy type, not OnDelete",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "stateful set rolled out",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 0, 2, 2, 4, 4),
+			expectedErr: None,
+		},
+		{
+			name:        "stateful set rolled out while watching",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 0, 2, 2, 3, 4),
+			watched:     newStatefulSet("RollingUpdate", 0, 2, 2, 4, 4),
+			expectedErr: None,
+		},
+		{
+			name:        "stateful set pods being updated",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 0, 2, 2, 3, 4),
+			expectedErr: "timed out waiting for the condition on statefulsets/nginx: stateful set rollout (last observed: partition 0, updated 3 of 4, ready 4 of 4, observed generation 2 of 2) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "stateful set partitioned rollout complete",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 3, 2, 2, 1, 4),
+			expectedErr: None,
+		},
+		{
+			name:        "stateful set partition above the replicas",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 5, 2, 2, 0, 4),
+			expectedErr: None,
+		},
+		{
+			name:        "stateful set partitioned rollout in progress",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 2, 2, 2, 1, 4),
+			expectedErr: "stateful set rollout (last observed: partition 2, updated 1 of 2, ready 4 of 4, observed generation 2 of 2) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "stateful set partitioned rollout with pods not ready",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 3, 2, 2, 1, 3),
+			expectedErr: "stateful set rollout (last observed: partition 3, updated 1 of 1, ready 3 of 4, observed generation 2 of 2) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "stateful set new generation not observed",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("RollingUpdate", 3, 3, 2, 1, 4),
+			expectedErr: "stateful set rollout (last observed: partition 3, updated 1 of 1, ready 4 of 4, observed generation 2 of 3) to complete",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "stateful set updated on delete",
+			kind:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			obj:         newStatefulSet("OnDelete", 0, 1, 1, 4, 4),
+			expectedErr: "condition unsatisfied on statefulsets/nginx: rollout status is only available for the RollingUpdate strategy type, not OnDelete",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "no rollout",
+			kind:        schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			obj:         newUnstructured("v1", "Pod", "ns-foo", "nginx"),
+			expectedErr: "condition unsatisfied on pods/nginx: rollout only applies to deployments, daemonsets and statefulsets",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resourceName := strings.ToLower(test.kind.Kind) + "s"
+			infos := []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource:         schema.GroupVersionResource{Group: test.kind.Group, Version: test.kind.Version, Resource: resourceName},
+						GroupVersionKind: test.kind,
+					},
+					Name:      "nginx",
+					Namespace: "ns-foo",
+				},
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", resourceName, func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(test.obj), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor(resourceName, func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(test.watched)
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor("rollout", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForRolloutSettled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource:         schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+				GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			},
+			Name:      "nginx",
+			Namespace: "ns-foo",
+		},
+	}
+	newDeployment := func(generation, updated int64, conditions ...interface{}) *unstructured.Unstructured {
+		obj := newUnstructuredWithGeneration("apps/v1", "Deployment", "ns-foo", "nginx", generation)
+		unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas")
+		obj.Object["status"] = map[string]interface{}{
+			"observedGeneration": generation,
+			"replicas":           updated,
+			"updatedReplicas":    updated,
+			"availableReplicas":  updated,
+			"conditions":         conditions,
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		obj       *unstructured.Unstructured
+		watched   []*unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "rolled back after the first check",
+			condition:   "rollout-settled",
+			obj:         newDeployment(2, 3),
+			watched:     []*unstructured.Unstructured{newDeployment(3, 1), newDeployment(3, 3)},
+			expectedErr: None,
+		},
+		{
+			name:        "no rollout after the first check",
+			condition:   "rollout-settled",
+			obj:         newDeployment(2, 3),
+			expectedErr: "timed out waiting for the condition on deployments/nginx: rollout (last observed: generation 2, first observed 2: deployment \"nginx\" successfully rolled out) to complete at another generation than the first observed",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "rolling out at the first check",
+			condition:   "rollout-settled",
+			obj:         newDeployment(2, 1),
+			watched:     []*unstructured.Unstructured{newDeployment(2, 3)},
+			expectedErr: "rollout (last observed: generation 2, first observed 2: deployment \"nginx\" successfully rolled out) to complete at another generation than the first observed",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "rolling out at the first check with any generation",
+			condition:   "rollout-settled=any-generation",
+			obj:         newDeployment(2, 1),
+			watched:     []*unstructured.Unstructured{newDeployment(2, 3)},
+			expectedErr: None,
+		},
+		{
+			name:        "rolled out at the first check with any generation",
+			condition:   "rollout-settled=any-generation",
+			obj:         newDeployment(2, 3),
+			expectedErr: None,
+		},
+		{
+			name:      "progress deadline exceeded",
+			condition: "rollout-settled",
+			obj:       newDeployment(2, 3),
+			watched: []*unstructured.Unstructured{newDeployment(3, 1, map[string]interface{}{
+				"type":   "Progressing",
+				"status": "False",
+				"reason": "ProgressDeadlineExceeded",
+			})},
+			expectedErr: `condition unsatisfied on deployments/nginx: deployment "nginx" exceeded its progress deadline`,
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "deployments", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(test.obj), nil
+			})
+			fakeClient.PrependWatchReactor("deployments", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				fakeWatch := watch.NewRaceFreeFake()
+				for _, obj := range test.watched {
+					fakeWatch.Modify(obj)
+				}
+				return true, fakeWatch, nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForNewReplicaSetReady(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+		{Group: "apps", Version: "v1", Resource: "replicasets"}: "ReplicaSetList",
+	}
+	template := func(image string, labels map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "web", "image": image}},
+			},
+		}
+	}
+	deployment := newUnstructured("apps/v1", "Deployment", "ns-foo", "web")
+	deployment.SetUID("deployment-uid")
+	deployment.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		"template": template("nginx:1.21", map[string]interface{}{"app": "web"}),
+	}
+	newReplicaSet := func(name, image, ownerUID string, replicas, ready int64) *unstructured.Unstructured {
+		obj := newUnstructured("apps/v1", "ReplicaSet", "ns-foo", name)
+		obj.SetLabels(map[string]string{"app": "web", "pod-template-hash": name[len("web-"):]})
+		controller := true
+		obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: types.UID(ownerUID), Controller: &controller}})
+		obj.Object["spec"] = map[string]interface{}{
+			"replicas": replicas,
+			"template": template(image, map[string]interface{}{"app": "web", "pod-template-hash": name[len("web-"):]}),
+		}
+		obj.Object["status"] = map[string]interface{}{"replicas": replicas, "readyReplicas": ready}
+		return obj
+	}
+	oldRS := newReplicaSet("web-6b7c9", "nginx:1.20", "deployment-uid", 3, 3)
+	info := &resource.Info{
+		Mapping: &meta.RESTMapping{
+			Resource:         schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		},
+		Name:      "web",
+		Namespace: "ns-foo",
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		info      *resource.Info
+		// replicaSets is the list of replica sets returned on each list, the last one being
+		// returned from then on. A nil list returns a transient error.
+		replicaSets [][]*unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "new replica set ready",
+			condition:   "new-replicaset-ready=2",
+			info:        info,
+			replicaSets: [][]*unstructured.Unstructured{{oldRS, newReplicaSet("web-5d4f8", "nginx:1.21", "deployment-uid", 3, 2)}},
+			expectedErr: None,
+		},
+		{
+			name:        "new replica set not ready enough",
+			condition:   "new-replicaset-ready=2",
+			info:        info,
+			replicaSets: [][]*unstructured.Unstructured{{oldRS, newReplicaSet("web-5d4f8", "nginx:1.21", "deployment-uid", 3, 1)}},
+			expectedErr: "timed out waiting for the condition on deployments/web: new replica set of the deployment (last observed: replica set web-5d4f8, 1 ready of 3) to have at least 2 ready replicas",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "no new replica set yet",
+			condition:   "new-replicaset-ready=1",
+			info:        info,
+			replicaSets: [][]*unstructured.Unstructured{{oldRS}},
+			expectedErr: "timed out waiting for the condition on deployments/web: new replica set of the deployment (last observed: no new replica set yet) to have at least 1 ready replicas",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "replica set of the template controlled by another deployment",
+			condition:   "new-replicaset-ready=1",
+			info:        info,
+			replicaSets: [][]*unstructured.Unstructured{{newReplicaSet("web-5d4f8", "nginx:1.21", "other-uid", 3, 3)}},
+			expectedErr: "(last observed: no new replica set yet)",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "new replica set created on a later poll",
+			condition:   "new-replicaset-ready=1",
+			info:        info,
+			replicaSets: [][]*unstructured.Unstructured{{oldRS}, nil, {oldRS, newReplicaSet("web-5d4f8", "nginx:1.21", "deployment-uid", 1, 1)}},
+			expectedErr: None,
+		},
+		{
+			name:      "not a deployment",
+			condition: "new-replicaset-ready=1",
+			info: &resource.Info{
+				Mapping: &meta.RESTMapping{
+					Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+				},
+				Name:      "web",
+				Namespace: "ns-foo",
+			},
+			expectedErr: "new-replicaset-ready only applies to deployments",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "deployments", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(deployment), nil
+			})
+			lists := 0
+			fakeClient.PrependReactor("list", "replicasets", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if selector := action.(clienttesting.ListAction).GetListRestrictions().Labels.String(); selector != "app=web" {
+					return true, nil, fmt.Errorf("expected the replica sets to be listed with the selector of the deployment, got %q", selector)
+				}
+				replicaSets := test.replicaSets[len(test.replicaSets)-1]
+				if lists < len(test.replicaSets) {
+					replicaSets = test.replicaSets[lists]
+				}
+				lists++
+				if replicaSets == nil {
+					return true, nil, apierrors.NewTooManyRequests("slow down", 1)
+				}
+				return true, newUnstructuredList(replicaSets...), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.info),
+				DynamicClient:  fakeClient,
+				Timeout:        time.Second,
+				PollInterval:   time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			if len(test.expectedErr) != 0 {
+				o.Timeout = 10 * time.Millisecond
+			}
+
+			_, err = o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
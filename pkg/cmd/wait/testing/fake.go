@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing runs the wait loop of k8s.io/kubectl/pkg/cmd/wait against fake resources, so
+// that programs which implement their own wait.ConditionFunc can test it without a cluster.
+// It is kept out of the wait package so that programs using the wait package do not build in
+// its fakes.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/kubectl/pkg/cmd/wait"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// defaultTimeout is the Timeout of the wait when none is given, so that a condition which is
+// never met fails the test rather than hanging it
+const defaultTimeout = time.Second
+
+// RunCondition waits with fn on a single resource, whose successive states are objects, with
+// the run loop of the wait package, and returns the outcome of the wait. objects may be typed,
+// such as a *corev1.Pod, or unstructured, and must all be the same resource.
+//
+// The first object is the state of the resource when the wait starts. When watching, the
+// others are sent as modifications on the watch, in order. When polling, as with a positive
+// PollInterval, each check sees the next of them. Either way, the resource stays in the last
+// state once all of them have been seen.
+//
+// o may be nil. Otherwise, its ResourceFinder, DynamicClient and ConditionFn are replaced, and
+// the rest of it is used as is, except that a zero Timeout defaults to a second, a nil Printer
+// discards the resources which meet the condition, and missing IOStreams discard the output.
+func RunCondition(fn wait.ConditionFunc, objects []runtime.Object, o *wait.WaitOptions) (wait.Result, error) {
+	if len(objects) == 0 {
+		return wait.Result{}, fmt.Errorf("at least one object is required to wait on")
+	}
+	if len(objects) > int(watch.DefaultChanSize) {
+		return wait.Result{}, fmt.Errorf("at most %d objects can be waited on, got %d", watch.DefaultChanSize, len(objects))
+	}
+	states := make([]*unstructured.Unstructured, 0, len(objects))
+	for i, obj := range objects {
+		state, err := toUnstructured(obj)
+		if err != nil {
+			return wait.Result{}, fmt.Errorf("unable to use object %d: %v", i, err)
+		}
+		if i > 0 && (state.GroupVersionKind() != states[0].GroupVersionKind() || state.GetNamespace() != states[0].GetNamespace() || state.GetName() != states[0].GetName()) {
+			return wait.Result{}, fmt.Errorf("object %d is not the same resource as the first one", i)
+		}
+		states = append(states, state)
+	}
+
+	gvk := states[0].GroupVersionKind()
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	script := &objectScript{states: states}
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: gvk.Kind + "List"})
+	fakeClient.PrependReactor("list", gvr.Resource, func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, &unstructured.UnstructuredList{Object: map[string]interface{}{}, Items: []unstructured.Unstructured{*script.next()}}, nil
+	})
+	fakeClient.PrependReactor("get", gvr.Resource, func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, script.next(), nil
+	})
+	fakeClient.PrependWatchReactor(gvr.Resource, func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+		return true, script.watch(), nil
+	})
+
+	scope := meta.RESTScopeNamespace
+	if len(states[0].GetNamespace()) == 0 {
+		scope = meta.RESTScopeRoot
+	}
+	info := &resource.Info{
+		Mapping: &meta.RESTMapping{
+			Resource:         gvr,
+			GroupVersionKind: gvk,
+			Scope:            scope,
+		},
+		Namespace: states[0].GetNamespace(),
+		Name:      states[0].GetName(),
+		Object:    states[0].DeepCopy(),
+	}
+
+	if o == nil {
+		o = &wait.WaitOptions{}
+	}
+	o.ResourceFinder = genericclioptions.NewSimpleFakeResourceFinder(info)
+	o.DynamicClient = fakeClient
+	o.ConditionFn = fn
+	if o.Timeout == 0 {
+		o.Timeout = defaultTimeout
+	}
+	if o.Printer == nil {
+		o.Printer = printers.NewDiscardingPrinter()
+	}
+	if o.Out == nil || o.ErrOut == nil {
+		o.IOStreams = genericclioptions.NewTestIOStreamsDiscard()
+	}
+	return o.Wait(context.Background())
+}
+
+// toUnstructured converts obj to an unstructured object, looking its kind up in the kubectl
+// scheme when it is typed and does not set it
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("the object is nil")
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	if u.GroupVersionKind().Empty() {
+		kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("the object does not set its apiVersion and kind: %v", err)
+		}
+		u.SetGroupVersionKind(kinds[0])
+	}
+	if len(u.GetName()) == 0 {
+		return nil, fmt.Errorf("the object has no name")
+	}
+	return u, nil
+}
+
+// objectScript hands out the successive states of the resource: one for every list or get, and
+// all those not seen yet for a watch
+type objectScript struct {
+	mu     sync.Mutex
+	states []*unstructured.Unstructured
+	// seen is the number of states handed out so far
+	seen int
+}
+
+// next returns the next state, or the last one once all of them have been seen
+func (s *objectScript) next() *unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen < len(s.states) {
+		s.seen++
+	}
+	return s.states[s.seen-1].DeepCopy()
+}
+
+// watch returns a watch sending every state not seen yet as a modification. It stays open
+// once they have been sent, as a watch on a resource which no longer changes does.
+func (s *objectScript) watch() watch.Interface {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fakeWatch := watch.NewRaceFreeFake()
+	for _, state := range s.states[s.seen:] {
+		fakeWatch.Modify(state.DeepCopy())
+	}
+	s.seen = len(s.states)
+	return fakeWatch
+}
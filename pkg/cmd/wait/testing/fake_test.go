@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/cmd/wait"
+)
+
+func newPod(ready corev1.ConditionStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-foo", Name: "busybox1"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+		},
+	}
+}
+
+func TestRunCondition(t *testing.T) {
+	var checks int
+	countingReady := func(ctx context.Context, info *resource.Info, o *wait.WaitOptions) (runtime.Object, bool, error) {
+		checks++
+		return wait.NewReadyWait().IsReady(ctx, info, o)
+	}
+
+	tests := []struct {
+		name         string
+		objects      []runtime.Object
+		pollInterval time.Duration
+
+		expectedErr string
+	}{
+		{
+			name:        "met while watching",
+			objects:     []runtime.Object{newPod(corev1.ConditionFalse), newPod(corev1.ConditionFalse), newPod(corev1.ConditionTrue)},
+			expectedErr: "",
+		},
+		{
+			name:         "met while polling",
+			objects:      []runtime.Object{newPod(corev1.ConditionFalse), newPod(corev1.ConditionTrue)},
+			pollInterval: time.Millisecond,
+			expectedErr:  "",
+		},
+		{
+			name:        "never met",
+			objects:     []runtime.Object{newPod(corev1.ConditionFalse)},
+			expectedErr: "timed out waiting for the condition on pods/busybox1",
+		},
+		{
+			name:        "no object",
+			expectedErr: "at least one object is required to wait on",
+		},
+		{
+			name:        "several resources",
+			objects:     []runtime.Object{newPod(corev1.ConditionFalse), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-foo", Name: "busybox2"}}},
+			expectedErr: "object 1 is not the same resource as the first one",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checks = 0
+			result, err := RunCondition(countingReady, test.objects, &wait.WaitOptions{
+				Timeout:      50 * time.Millisecond,
+				PollInterval: test.pollInterval,
+			})
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if len(result.Satisfied) != 1 {
+				t.Errorf("expected the pod to meet the condition, got %v", result.Satisfied)
+			}
+			if checks != 1 {
+				t.Errorf("expected the condition to be checked once, got %d", checks)
+			}
+		})
+	}
+}
@@ -17,35 +17,58 @@ limitations under the License.
 package wait
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/spf13/cobra"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/klog/v2"
 	cmdget "k8s.io/kubectl/pkg/cmd/get"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	utilexec "k8s.io/utils/exec"
 )
 
 var (
@@ -56,28 +79,83 @@ var (
 		is seen in the Status field of every given resource.
 
 		Alternatively, the command can wait for the given set of resources to be deleted
-		by providing the "delete" keyword as the value to the --for flag.
+		by providing the "delete" keyword as the value to the --for flag, or to be created
+		by providing the "create" keyword.
+
+		The --for flag also accepts these conditions, some of which only apply to some kinds:
+
+		    * condition=NAME, condition=NAME=STATUS and condition!=NAME
+		    * jsonpath, jsonpath-all, jsonpath-any and jsonpath-cmp, comparing JSONPath expressions
+		    * template, count, persists, synced, ready and no-finalizers
+		    * label, annotation, owned-by and has-key
+		    * rollout, rollout-settled and new-replicaset-ready for workloads
+		    * job-complete, hpa-stable, established and bound
+		    * containers-ready, container-ready, init-complete and has-endpoints
 
 		A successful message will be printed to stdout indicating when the specified
-        condition has been met. You can use -o option to change to output destination.`))
+		condition has been met. The command exits with 0 once the condition is met on every
+		resource, 2 if the timeout is reached first, 3 if no resources matched, 4 if the
+		condition can no longer be met, 5 if it is not met with --check-now, 130 if it is
+		interrupted and 1 for any other error.`))
 
 	waitExample = templates.Examples(i18n.T(`
 		# Wait for the pod "busybox1" to contain the status condition of type "Ready"
 		kubectl wait --for=condition=Ready pod/busybox1
 
-		# The default value of status condition is true; you can set it to false
+		# The default value of status condition is true; you can set it to false, or to several statuses
 		kubectl wait --for=condition=Ready=false pod/busybox1
+		kubectl wait --for=condition=Ready=True,Unknown pod/busybox1
 
 		# Wait for the pod "busybox1" to contain the status phase to be "Running".
 		kubectl wait --for=jsonpath='{.status.phase}'=Running pod/busybox1
 
+		# Wait for the pod "busybox1" to reach either the "Running" or "Succeeded" phase; values may also be
+		# separated by commas, and empty ones are ignored
+		kubectl wait --for=jsonpath='{.status.phase}'='Running|Succeeded' pod/busybox1
+
+		# Wait for the deployment "nginx" to have at least 3 ready replicas, or as many as it asks for
+		kubectl wait --for=jsonpath='{.status.readyReplicas}'>=3 deployment/nginx
+		kubectl wait --for=jsonpath-cmp='{.status.readyReplicas}'>='{.spec.replicas}' deployment/nginx
+
+		# Wait for the rollout of the deployment "nginx" to complete, failing if it exceeds its progress deadline
+		kubectl wait --for=rollout deployment/nginx
+
+		# Wait for the job "pi" to complete, failing right away if it fails
+		kubectl wait --for=job-complete job/pi
+
+		# Wait for the container "app" of the pod "busybox1" to be ready, whatever its sidecars
+		kubectl wait --for=container-ready=app pod/busybox1
+
+		# Wait for the service "web" to have 2 ready addresses to send traffic to
+		kubectl wait --for=has-endpoints=2 service/web
+
+		# Wait for at least 3 pods labeled "app=nginx" to exist
+		kubectl wait --for=count>=3 pod -l app=nginx
+
+		# Wait for the pod "busybox1" to have stayed ready for 30 seconds
+		kubectl wait --for=condition=Ready --settle=30s --timeout=5m pod/busybox1
+
+		# Print the logs of the first of the pods labeled "app=nginx" to be ready
+		kubectl logs $(kubectl wait --for=condition=Ready --mode=any pod -l app=nginx -o name)
+
+		# Check that the deployment "nginx" stays present and available for 30s
+		kubectl wait --for=persists --for=condition=Available deployment/nginx --timeout=30s
+
 		# Wait for the pod "busybox1" to be deleted, with a timeout of 60s, after having issued the "delete" command
 		kubectl delete pod/busybox1
-		kubectl wait --for=delete pod/busybox1 --timeout=60s`))
+		kubectl wait --for=delete pod/busybox1 --timeout=60s
+
+		# Wait for the pod "busybox1" to be created, with a timeout of 60s, after having issued the "apply" command
+		kubectl apply -f busybox1.yaml &
+		kubectl wait --for=create -f busybox1.yaml --timeout=60s`))
 )
 
 // errNoMatchingResources is returned when there is no resources matching a query.
-var errNoMatchingResources = errors.New("no matching resources found")
+var errNoMatchingResources = &NoMatchingResourcesError{}
+
+// resourcesPollInterval is how often resources are looked for with WaitOptions.WaitForResources
+// when they are watched rather than polled
+const resourcesPollInterval = time.Second
 
 // WaitFlags directly reflect the information that CLI is gathering via flags.  They will be converted to Options, which
 // reflect the runtime requirements for the command.  This structure reduces the transformation to wiring and makes
@@ -87,8 +165,33 @@ type WaitFlags struct {
 	PrintFlags           *genericclioptions.PrintFlags
 	ResourceBuilderFlags *genericclioptions.ResourceBuilderFlags
 
-	Timeout      time.Duration
-	ForCondition string
+	Timeout       time.Duration
+	Deadline      string
+	PollInterval  time.Duration
+	ForConditions []string
+	ForFile       string
+	IgnoreCase    bool
+	StableFor     time.Duration
+	Settle        time.Duration
+	MinReady      time.Duration
+	InitialDelay  time.Duration
+	PollJitter    time.Duration
+
+	WaitForResources     bool
+	CheckNow             bool
+	ShowTiming           bool
+	Quiet                bool
+	ResourceVersion      string
+	PrintResourceVersion bool
+	RequireTransition    string
+	Concurrency          int
+	Mode                 string
+	MaxTransientRetries  int
+	ChunkSize            int64
+	NotifyURL            string
+	Subresource          string
+	ErrorFormat          string
+	Exclude              []string
 
 	genericclioptions.IOStreams
 }
@@ -106,7 +209,13 @@ func NewWaitFlags(restClientGetter genericclioptions.RESTClientGetter, streams g
 			WithLocal(false).
 			WithLatest(),
 
-		Timeout: 30 * time.Second,
+		Timeout:             30 * time.Second,
+		Concurrency:         1,
+		Mode:                string(WaitModeAll),
+		RequireTransition:   string(TransitionModeNone),
+		ErrorFormat:         string(ErrorFormatText),
+		MaxTransientRetries: 5,
+		ChunkSize:           cmdutil.DefaultChunkSize,
 
 		IOStreams: streams,
 	}
@@ -117,16 +226,19 @@ func NewCmdWait(restClientGetter genericclioptions.RESTClientGetter, streams gen
 	flags := NewWaitFlags(restClientGetter, streams)
 
 	cmd := &cobra.Command{
-		Use:     "wait ([-f FILENAME] | resource.group/resource.name | resource.group [(-l label | --all)]) [--for=delete|--for condition=available|--for=jsonpath='{}'=value]",
+		Use:     "wait ([-f FILENAME] | resource.group/resource.name | resource.group [(-l label | --all)]) [--for=create|--for=delete|--for condition=available|--for=jsonpath='{}'=value]",
 		Short:   i18n.T("Experimental: Wait for a specific condition on one or many resources"),
 		Long:    waitLong,
 		Example: waitExample,
 
 		DisableFlagsInUseLine: true,
 		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(flags.timeoutFromEnv(cmd))
 			o, err := flags.ToOptions(args)
 			cmdutil.CheckErr(err)
-			cmdutil.CheckErr(o.RunWait())
+			signals := make(chan os.Signal, 2)
+			signal.Notify(signals, interruptSignals...)
+			cmdutil.CheckErr(o.reportFailure(o.runInterruptible(signals, os.Exit)))
 		},
 		SuggestFor: []string{"list", "ps"},
 	}
@@ -136,22 +248,117 @@ func NewCmdWait(restClientGetter genericclioptions.RESTClientGetter, streams gen
 	return cmd
 }
 
+// timeoutEnvVar names the environment variable holding the default of --timeout
+const timeoutEnvVar = "KUBECTL_WAIT_TIMEOUT"
+
+// timeoutFromEnv sets the Timeout from the KUBECTL_WAIT_TIMEOUT environment variable, unless
+// --timeout is set on the command line, which always wins. When neither sets it, a --deadline
+// alone bounds the wait rather than the default Timeout.
+func (flags *WaitFlags) timeoutFromEnv(cmd *cobra.Command) error {
+	if cmd.Flags().Changed("timeout") {
+		return nil
+	}
+	value, ok := os.LookupEnv(timeoutEnvVar)
+	if !ok || len(value) == 0 {
+		if len(flags.Deadline) > 0 {
+			flags.Timeout = 0
+		}
+		return nil
+	}
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || timeout < 0 {
+		return fmt.Errorf("%s must be a non-negative duration such as 10m, got %q", timeoutEnvVar, value)
+	}
+	flags.Timeout = timeout
+	return nil
+}
+
 // AddFlags registers flags for a cli
 func (flags *WaitFlags) AddFlags(cmd *cobra.Command) {
 	flags.PrintFlags.AddFlags(cmd)
 	flags.ResourceBuilderFlags.AddFlags(cmd.Flags())
 
-	cmd.Flags().DurationVar(&flags.Timeout, "timeout", flags.Timeout, "The length of time to wait before giving up.  Zero means check once and don't wait, negative means wait for a week.")
-	cmd.Flags().StringVar(&flags.ForCondition, "for", flags.ForCondition, "The condition to wait on: [delete|condition=condition-name|jsonpath='{JSONPath expression}'=JSONPath Condition]. The default status value of condition-name is true, you can set false with condition=condition-name=false.")
+	cmd.Flags().DurationVar(&flags.Timeout, "timeout", flags.Timeout, "The length of time to wait before giving up.  Zero means wait until the condition is met, with no deadline. Defaults to the value of the KUBECTL_WAIT_TIMEOUT environment variable if it is set.")
+	cmd.Flags().StringVar(&flags.Deadline, "deadline", flags.Deadline, "If set, the RFC3339 time, such as 2024-01-01T00:00:00Z, by which to give up, whichever of it and --timeout comes first. Without --timeout or KUBECTL_WAIT_TIMEOUT, only the deadline bounds the wait. A deadline which has already passed checks the condition once, as --check-now does, and exits with 2 if it is not met.")
+	cmd.Flags().DurationVar(&flags.PollInterval, "poll-interval", flags.PollInterval, "If positive, fetch the resources again at this interval until the condition is met instead of watching them for changes.")
+	cmd.Flags().StringArrayVar(&flags.ForConditions, "for", flags.ForConditions, "The condition to wait on: [create|delete|condition=condition-name|jsonpath='{JSONPath expression}'=JSONPath Condition|any other condition listed in the description]. The default status value of condition-name is true, you can set false with condition=condition-name=false. A JSONPath Condition may list values separated by | or , to match any of them, ignoring empty ones. May be repeated to wait for all of them.")
+	cmd.Flags().StringVar(&flags.ForFile, "for-file", flags.ForFile, "A YAML file listing the conditions to wait on, each as given to --for, instead of repeating --for. The file holds either a list of conditions, or a mapping with the list under conditions and a mode of all or any to wait for all of the conditions, the default, or any one of them. Every condition is checked when the file is read, and reported with its line. Cannot be combined with --for.")
+	cmd.Flags().DurationVar(&flags.Settle, "settle", flags.Settle, "If positive, only consider the condition met once it has held continuously for this long, even if the observed value changes in the meantime. Any check on which it is not met starts the window again, as minReadySeconds does for the pods of a deployment. The window has to fit within --timeout. Ignored by --for=delete.")
+	cmd.Flags().DurationVar(&flags.MinReady, "min-ready", flags.MinReady, "An alias of --settle, named after the minReadySeconds of a deployment.")
+	cmd.Flags().DurationVar(&flags.StableFor, "stable-for", flags.StableFor, "If positive, only consider the condition met once it has held, with the same observed value, for this long. The window has to fit within --timeout. Ignored by --for=delete.")
+	cmd.Flags().BoolVar(&flags.WaitForResources, "wait-for-resources", flags.WaitForResources, "If true, keep looking for resources matching the arguments until some appear instead of failing when there are none. Ignored by --for=delete, for which no matching resources means they are deleted.")
+	cmd.Flags().BoolVar(&flags.CheckNow, "check-now", flags.CheckNow, "If true, check the condition once against every resource as it is now instead of waiting for it, and exit with 0 if it is met or 5 if it is not.")
+	cmd.Flags().StringVar(&flags.RequireTransition, "require-transition", flags.RequireTransition, "Whether the condition has to be seen unmet before it counts as met, to make sure it was driven while waiting. One of: none|wait|fail. With wait, a condition already met at the first check is waited on to become unmet and then met again, and with fail the command fails instead. Ignored by --for=delete.")
+	cmd.Flags().Lookup("require-transition").NoOptDefVal = string(TransitionModeWait)
+	cmd.Flags().BoolVar(&flags.ShowTiming, "show-timing", flags.ShowTiming, "If true, report how many times the condition was checked on each resource that meets it, and how long that took. Only applies to the default output.")
+	cmd.Flags().StringVar(&flags.ResourceVersion, "resource-version", flags.ResourceVersion, "If set, start watching every resource from this resourceVersion instead of from its current state, so that a wait resumed with the resourceVersion printed by --print-resource-version does not count a change it already saw. Only applies when watching, and falls back to the current state when the resourceVersion is too old. Not used by --for=create.")
+	cmd.Flags().BoolVar(&flags.PrintResourceVersion, "print-resource-version", flags.PrintResourceVersion, "If true, print the resourceVersion last observed on every resource to stderr once the wait is over, whether or not the condition was met, to resume from with --resource-version.")
+	cmd.Flags().BoolVarP(&flags.Quiet, "quiet", "q", flags.Quiet, "If true, do not print the resources that meet the condition, so that nothing is printed on success and the exit code tells the outcome. Errors and timeouts are still reported.")
+	cmd.Flags().DurationVar(&flags.InitialDelay, "initial-delay", flags.InitialDelay, "The length of time to wait before the first check of the condition, for resources whose status may be stale right after they are changed. It counts towards --timeout.")
+	cmd.Flags().DurationVar(&flags.PollJitter, "poll-jitter", flags.PollJitter, "If positive, delay the first check, and lengthen every interval between polls, by a random duration of up to this long, so that many waits started at once do not hit the API server in lockstep. It counts towards --timeout.")
+	cmd.Flags().StringVar(&flags.Mode, "mode", flags.Mode, "Whether all the resources have to meet the condition, or any one of them. One of: all|any.")
+	cmd.Flags().IntVar(&flags.MaxTransientRetries, "max-transient-retries", flags.MaxTransientRetries, "The number of transient errors in a row, such as timeouts or throttling, after which to give up on a resource. Zero means not to retry.")
+	cmd.Flags().IntVar(&flags.Concurrency, "concurrency", flags.Concurrency, "The number of resources to wait on at once.")
+	cmdutil.AddChunkSizeFlag(cmd, &flags.ChunkSize)
+	cmd.Flags().StringVar(&flags.Subresource, "subresource", flags.Subresource, "If set, check the condition against this subresource of every resource, rather than the resource itself, polling it every --poll-interval or every second since subresources cannot be watched. One of: status|scale.")
+	cmd.Flags().StringSliceVar(&flags.Exclude, "exclude", flags.Exclude, "Resources not to wait on, as TYPE/NAME, such as pod/foo, even if they match the arguments. May be repeated or separated by commas. A resource listed which does not match is ignored.")
+	cmd.Flags().StringVar(&flags.NotifyURL, "notify-url", flags.NotifyURL, "If set, POST the outcome of the wait as JSON to this URL once it is over, whether or not the condition was met. A failure to notify is reported as a warning and does not change the exit code.")
+	cmd.Flags().StringVar(&flags.ErrorFormat, "error-format", flags.ErrorFormat, "How to report a failed wait on stderr. One of: text|json. With json, a single JSON object gives the kind of failure, the condition expected and, for every resource that did not meet it, the last value observed and how long it was waited on.")
+	cmd.Flags().BoolVar(&flags.IgnoreCase, "ignore-case", flags.IgnoreCase, "If true, compare JSONPath condition values and regular expressions case-insensitively.")
 }
 
 // ToOptions converts from CLI inputs to runtime inputs
 func (flags *WaitFlags) ToOptions(args []string) (*WaitOptions, error) {
-	printer, err := flags.PrintFlags.ToPrinter()
-	if err != nil {
-		return nil, err
+	// -o wide prints a summary of every resource once the wait is over, rather than every
+	// resource as it meets the condition
+	summary := flags.PrintFlags.OutputFormat != nil && *flags.PrintFlags.OutputFormat == "wide"
+	conditionsMode := ConditionsModeAll
+	if len(flags.ForFile) > 0 {
+		if len(flags.ForConditions) > 0 {
+			return nil, fmt.Errorf("--for-file cannot be combined with --for")
+		}
+		conditions, mode, err := readConditionsFile(flags.ForFile, flags.IgnoreCase)
+		if err != nil {
+			return nil, err
+		}
+		flags.ForConditions, conditionsMode = conditions, mode
+	}
+	var (
+		printer printers.ResourcePrinter = printers.NewDiscardingPrinter()
+		err     error
+	)
+	if !summary {
+		if printer, err = flags.PrintFlags.ToPrinter(); err != nil {
+			return nil, err
+		}
+	}
+	if fieldSelector := flags.ResourceBuilderFlags.FieldSelector; fieldSelector != nil && len(*fieldSelector) > 0 {
+		if _, err := fields.ParseSelector(*fieldSelector); err != nil {
+			return nil, fmt.Errorf("invalid --field-selector %q: %v", *fieldSelector, err)
+		}
+	}
+	var builder genericclioptions.ResourceFinder
+	if hasCondition(flags.ForConditions, "create") {
+		// the objects we wait to be created do not exist yet, so they must not be
+		// fetched from the server. Manifests are used as-is for their identity.
+		builderFlags := *flags.ResourceBuilderFlags
+		builderFlags.Latest = false
+		namespace, _, err := flags.RESTClientGetter.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return nil, err
+		}
+		finder, err := flags.resourceFinder(&builderFlags, args)
+		if err != nil {
+			return nil, err
+		}
+		builder = genericclioptions.ResourceFinderForResult(resource.NewDecoratedVisitor(
+			finder.Do(), resource.SetNamespace(namespace)))
+	} else {
+		builder, err = flags.resourceFinder(flags.ResourceBuilderFlags, args)
+		if err != nil {
+			return nil, err
+		}
 	}
-	builder := flags.ResourceBuilderFlags.ToBuilder(flags.RESTClientGetter, args)
 	clientConfig, err := flags.RESTClientGetter.ToRESTConfig()
 	if err != nil {
 		return nil, err
@@ -160,243 +367,2780 @@ func (flags *WaitFlags) ToOptions(args []string) (*WaitOptions, error) {
 	if err != nil {
 		return nil, err
 	}
-	conditionFn, err := conditionFuncFor(flags.ForCondition, flags.ErrOut)
+	var (
+		conditionFn    ConditionFunc
+		conditionFnFor func(*meta.RESTMapping) (ConditionFunc, error)
+		count          *CountWait
+		aggregate      AggregateCondition
+	)
+	switch {
+	case hasCountCondition(flags.ForConditions) && len(flags.ForConditions) > 1:
+		return nil, fmt.Errorf("a count condition cannot be combined with other conditions")
+	case hasCountCondition(flags.ForConditions):
+		count, err = countWaitFor(flags.ForConditions[0])
+	case hasCoversCondition(flags.ForConditions) && len(flags.ForConditions) > 1:
+		return nil, fmt.Errorf("a jsonpath covers condition cannot be combined with other conditions, since it is checked across all the resources")
+	case hasCoversCondition(flags.ForConditions):
+		aggregate, err = coversWaitFor(flags.ForConditions[0], flags.IgnoreCase)
+	case hasCondition(flags.ForConditions, "persists") && len(flags.ForConditions) > 1 && conditionsMode == ConditionsModeAny:
+		return nil, fmt.Errorf("a persists condition can only be combined with other conditions in the all mode of --for-file")
+	case hasCondition(flags.ForConditions, "persists"):
+		conditionFn, err = persistsFuncFor(flags.ForConditions, flags.IgnoreCase)
+		if err == nil {
+			conditionFnFor, err = conditionFnForKinds(flags.ForConditions)
+		}
+	case len(flags.ForConditions) > 1 && conditionsMode == ConditionsModeAny:
+		conditionFn, err = anyConditionsFuncFor(flags.ForConditions, flags.IgnoreCase)
+		if err == nil {
+			conditionFnFor, err = conditionFnForKinds(flags.ForConditions)
+		}
+	case len(flags.ForConditions) > 1:
+		conditionFn, err = allConditionsFuncFor(flags.ForConditions, flags.IgnoreCase)
+		if err == nil {
+			conditionFnFor, err = conditionFnForKinds(flags.ForConditions)
+		}
+	default:
+		conditionFn, err = conditionFuncFor(strings.Join(flags.ForConditions, ""), flags.IgnoreCase)
+		if err == nil {
+			conditionFnFor, err = conditionFnForKinds([]string{strings.Join(flags.ForConditions, "")})
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	effectiveTimeout := flags.Timeout
-	if effectiveTimeout < 0 {
-		effectiveTimeout = 168 * time.Hour
+	if flags.Timeout < 0 {
+		return nil, fmt.Errorf("--timeout must not be negative")
+	}
+	var until time.Time
+	if len(flags.Deadline) > 0 {
+		if until, err = time.Parse(time.RFC3339, flags.Deadline); err != nil {
+			return nil, fmt.Errorf("--deadline must be an RFC3339 time such as 2024-01-01T00:00:00Z, got %q", flags.Deadline)
+		}
+	}
+	if flags.PollInterval < 0 {
+		return nil, fmt.Errorf("--poll-interval must not be negative")
+	}
+	if flags.Mode != string(WaitModeAll) && flags.Mode != string(WaitModeAny) {
+		return nil, fmt.Errorf("--mode must be one of: all, any")
+	}
+	if flags.ErrorFormat != string(ErrorFormatText) && flags.ErrorFormat != string(ErrorFormatJSON) {
+		return nil, fmt.Errorf("--error-format must be one of: text, json")
+	}
+	if flags.MaxTransientRetries < 0 {
+		return nil, fmt.Errorf("--max-transient-retries must not be negative")
+	}
+	if flags.ChunkSize < 0 {
+		return nil, fmt.Errorf("--chunk-size must not be negative")
+	}
+	if flags.Concurrency < 1 {
+		return nil, fmt.Errorf("--concurrency must be at least 1")
+	}
+	if flags.StableFor < 0 {
+		return nil, fmt.Errorf("--stable-for must not be negative")
+	}
+	if flags.Timeout > 0 && flags.StableFor >= flags.Timeout {
+		return nil, fmt.Errorf("--stable-for must be shorter than --timeout, or the condition can never be met")
+	}
+	if flags.MinReady != 0 {
+		if flags.Settle != 0 {
+			return nil, fmt.Errorf("--min-ready is an alias of --settle, and cannot be combined with it")
+		}
+		flags.Settle = flags.MinReady
+	}
+	if flags.Settle < 0 {
+		return nil, fmt.Errorf("--settle must not be negative")
+	}
+	if flags.Timeout > 0 && flags.Settle >= flags.Timeout {
+		return nil, fmt.Errorf("--settle must be shorter than --timeout, or the condition can never be met")
+	}
+	if flags.Settle > 0 && flags.StableFor > 0 {
+		return nil, fmt.Errorf("--settle cannot be combined with --stable-for, which also requires the observed value not to change")
+	}
+	switch TransitionMode(flags.RequireTransition) {
+	case TransitionModeNone, TransitionModeWait, TransitionModeFail:
+	default:
+		return nil, fmt.Errorf("--require-transition must be one of: none, wait, fail")
+	}
+	if flags.CheckNow && TransitionMode(flags.RequireTransition) != TransitionModeNone {
+		return nil, fmt.Errorf("--require-transition cannot be used with --check-now, which checks the condition only once")
+	}
+	if flags.CheckNow && flags.StableFor > 0 {
+		return nil, fmt.Errorf("--stable-for cannot be used with --check-now, which checks the condition only once")
+	}
+	if len(flags.ResourceVersion) > 0 && (flags.CheckNow || flags.PollInterval > 0 || len(flags.Subresource) > 0) {
+		return nil, fmt.Errorf("--resource-version only applies when watching, and cannot be used with --check-now, --poll-interval or --subresource")
+	}
+	if flags.CheckNow && flags.Settle > 0 {
+		return nil, fmt.Errorf("--settle cannot be used with --check-now, which checks the condition only once")
+	}
+	if flags.CheckNow && hasChangedCondition(flags.ForConditions) {
+		return nil, fmt.Errorf("a jsonpath changed or a rollout-settled condition cannot be used with --check-now, which checks the condition only once")
+	}
+	if hasCondition(flags.ForConditions, "persists") {
+		if flags.CheckNow {
+			return nil, fmt.Errorf("a persists condition cannot be used with --check-now, since the resource has to stay present until the timeout")
+		}
+		if flags.Timeout == 0 && len(flags.Deadline) == 0 {
+			return nil, fmt.Errorf("a persists condition requires a positive --timeout, the window the resource has to stay present for")
+		}
+	}
+	if flags.CheckNow && flags.WaitForResources {
+		return nil, fmt.Errorf("--wait-for-resources cannot be used with --check-now, which looks for resources only once")
+	}
+	if summary && (count != nil || aggregate != nil) {
+		return nil, fmt.Errorf("-o wide cannot be used with a count or a covers condition, which do not wait on each resource")
+	}
+	var printerFor func(ResourceStatus) printers.ResourcePrinter
+	if flags.ShowTiming {
+		if printFormatSpecified(flags.PrintFlags) {
+			return nil, fmt.Errorf("--show-timing only applies to the default output, and cannot be used with -o or --template")
+		}
+		printerFor = flags.timingPrinterFor
+	}
+	if flags.Quiet {
+		if flags.ShowTiming {
+			return nil, fmt.Errorf("--show-timing cannot be used with --quiet, which prints nothing on success")
+		}
+		if printFormatSpecified(flags.PrintFlags) {
+			return nil, fmt.Errorf("--quiet cannot be used with -o or --template, which print the resources")
+		}
+	}
+	if flags.InitialDelay < 0 {
+		return nil, fmt.Errorf("--initial-delay must not be negative")
+	}
+	if flags.PollJitter < 0 {
+		return nil, fmt.Errorf("--poll-jitter must not be negative")
+	}
+	if flags.Timeout > 0 && flags.InitialDelay >= flags.Timeout {
+		return nil, fmt.Errorf("--initial-delay must be shorter than --timeout")
+	}
+	if len(flags.Subresource) > 0 {
+		if !supportedSubresources.Has(flags.Subresource) {
+			return nil, fmt.Errorf("--subresource must be one of: %s", strings.Join(supportedSubresources.List(), ", "))
+		}
+		if hasCondition(flags.ForConditions, "delete") || count != nil || aggregate != nil {
+			return nil, fmt.Errorf("--subresource cannot be used with --for=delete, a count condition or a covers condition, which do not check the resources themselves")
+		}
+		discoveryClient, err := flags.RESTClientGetter.ToDiscoveryClient()
+		if err != nil {
+			return nil, err
+		}
+		conditionFnFor = subresourceCheckFor(discoveryClient, flags.Subresource, conditionFnFor)
+	}
+	exclude, err := flags.excludedResources()
+	if err != nil {
+		return nil, err
+	}
+	forCondition := strings.Join(flags.ForConditions, ",")
+	if conditionsMode == ConditionsModeAny {
+		forCondition = strings.Join(flags.ForConditions, " or ")
+	}
+	var completionHook func(Result, error) error
+	if len(flags.NotifyURL) > 0 {
+		if err := validateNotifyURL(flags.NotifyURL); err != nil {
+			return nil, err
+		}
+		completionHook = newNotifyHook(flags.NotifyURL, &http.Client{Timeout: notifyTimeout})
 	}
 
 	o := &WaitOptions{
 		ResourceFinder: builder,
 		DynamicClient:  dynamicClient,
-		Timeout:        effectiveTimeout,
-		ForCondition:   flags.ForCondition,
+		Timeout:        flags.Timeout,
+		Until:          until,
+		PollInterval:   flags.PollInterval,
+		StableFor:      flags.StableFor,
+		Settle:         flags.Settle,
+		InitialDelay:   flags.InitialDelay,
+		PollJitter:     flags.PollJitter,
 
-		Printer:     printer,
-		ConditionFn: conditionFn,
-		IOStreams:   flags.IOStreams,
+		WaitForResources:     flags.WaitForResources,
+		CheckNow:             flags.CheckNow,
+		Quiet:                flags.Quiet,
+		ResourceVersion:      flags.ResourceVersion,
+		PrintResourceVersion: flags.PrintResourceVersion,
+		PrintSummary:         summary,
+		RequireTransition:    TransitionMode(flags.RequireTransition),
+		Concurrency:          flags.Concurrency,
+		Mode:                 WaitMode(flags.Mode),
+		ErrorFormat:          ErrorFormat(flags.ErrorFormat),
+		MaxTransientRetries:  flags.MaxTransientRetries,
+		Subresource:          flags.Subresource,
+		Exclude:              exclude,
+		ForCondition:         forCondition,
+		conditionKinds:       conditionKindLabel(flags.ForConditions),
+
+		Printer:        printer,
+		PrinterFor:     printerFor,
+		ConditionFn:    conditionFn,
+		ConditionFnFor: conditionFnFor,
+		Count:          count,
+		Aggregate:      aggregate,
+		CompletionHook: completionHook,
+		IOStreams:      flags.IOStreams,
 	}
 
 	return o, nil
 }
 
-func conditionFuncFor(condition string, errOut io.Writer) (ConditionFunc, error) {
-	if strings.ToLower(condition) == "delete" {
-		return IsDeleted, nil
+// excludedResources returns the resources given to --exclude, in any namespace
+func (flags *WaitFlags) excludedResources() ([]ResourceLocation, error) {
+	if len(flags.Exclude) == 0 {
+		return nil, nil
 	}
-	if strings.HasPrefix(condition, "condition=") {
-		conditionName := condition[len("condition="):]
-		conditionValue := "true"
-		if equalsIndex := strings.Index(conditionName, "="); equalsIndex != -1 {
-			conditionValue = conditionName[equalsIndex+1:]
-			conditionName = conditionName[0:equalsIndex]
-		}
-
-		return ConditionalWait{
-			conditionName:   conditionName,
-			conditionStatus: conditionValue,
-			errOut:          errOut,
-		}.IsConditionMet, nil
+	mapper, err := flags.RESTClientGetter.ToRESTMapper()
+	if err != nil {
+		return nil, err
 	}
-	if strings.HasPrefix(condition, "jsonpath=") {
-		splitStr := strings.Split(condition, "=")
-		if len(splitStr) != 3 {
-			return nil, fmt.Errorf("jsonpath wait format must be --for=jsonpath='{.status.readyReplicas}'=3")
-		}
-		jsonPathExp, jsonPathCond, err := processJSONPathInput(splitStr[1], splitStr[2])
-		if err != nil {
-			return nil, err
+	exclude := make([]ResourceLocation, 0, len(flags.Exclude))
+	for _, excluded := range flags.Exclude {
+		slash := strings.Index(excluded, "/")
+		if slash <= 0 || slash == len(excluded)-1 {
+			return nil, fmt.Errorf("--exclude must list resources as TYPE/NAME, such as pod/foo, got %q", excluded)
 		}
-		j, err := newJSONPathParser(jsonPathExp)
+		gvr, err := mapper.ResourceFor(schema.ParseGroupResource(excluded[:slash]).WithVersion(""))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to exclude %s: %v", excluded, err)
 		}
-		return JSONPathWait{
-			jsonPathCondition: jsonPathCond,
-			jsonPathParser:    j,
-			errOut:            errOut,
-		}.IsJSONPathConditionMet, nil
+		exclude = append(exclude, ResourceLocation{GroupResource: gvr.GroupResource(), Name: excluded[slash+1:]})
 	}
+	return exclude, nil
+}
 
-	return nil, fmt.Errorf("unrecognized condition: %q", condition)
+// printFormatSpecified returns true if an output format or a template was given, rather than
+// the default "condition met" output
+func printFormatSpecified(printFlags *genericclioptions.PrintFlags) bool {
+	if printFlags.OutputFormat != nil && len(*printFlags.OutputFormat) > 0 {
+		return true
+	}
+	return printFlags.TemplatePrinterFlags != nil && printFlags.TemplatePrinterFlags.TemplateArgument != nil &&
+		len(*printFlags.TemplatePrinterFlags.TemplateArgument) > 0
 }
 
-// newJSONPathParser will create a new JSONPath parser based on the jsonPathExpression
-func newJSONPathParser(jsonPathExpression string) (*jsonpath.JSONPath, error) {
-	j := jsonpath.New("wait")
-	if jsonPathExpression == "" {
-		return nil, errors.New("jsonpath expression cannot be empty")
+// timingPrinterFor returns a printer for the default output which also says how many times
+// the condition was checked on the resource and how long it took, as in
+// "pod/foo condition met after 7 polls in 14.2s"
+func (flags *WaitFlags) timingPrinterFor(status ResourceStatus) printers.ResourcePrinter {
+	polls := fmt.Sprintf("%d polls", status.Polls)
+	if status.Polls == 1 {
+		polls = "1 poll"
 	}
-	if err := j.Parse(jsonPathExpression); err != nil {
-		return nil, err
+	elapsed := status.Elapsed.Round(time.Millisecond)
+	if elapsed >= time.Second {
+		elapsed = status.Elapsed.Round(100 * time.Millisecond)
 	}
-	return j, nil
+	operation := fmt.Sprintf("%s after %s in %s", flags.PrintFlags.NamePrintFlags.Operation, polls, elapsed)
+	return flags.PrintFlags.TypeSetterPrinter.ToPrinter(&printers.NamePrinter{Operation: operation})
 }
 
-// processJSONPathInput will parses the user's JSONPath input and process the string
-func processJSONPathInput(jsonPathExpression, jsonPathCond string) (string, string, error) {
-	relaxedJSONPathExp, err := cmdget.RelaxedJSONPathExpression(jsonPathExpression)
-	if err != nil {
-		return "", "", err
+// resourceFinder returns the ResourceFinder for the resources given by builderFlags and args.
+// Manifests given with -f - are read from stdin right away, since stdin can only be read once
+// while the resources may be looked up several times.
+func (flags *WaitFlags) resourceFinder(builderFlags *genericclioptions.ResourceBuilderFlags, args []string) (genericclioptions.ResourceFinder, error) {
+	finder := &builderResourceFinder{
+		restClientGetter: flags.RESTClientGetter,
+		builderFlags:     builderFlags,
+		args:             args,
+		chunkSize:        flags.ChunkSize,
 	}
-	if jsonPathCond == "" {
-		return "", "", errors.New("jsonpath wait condition cannot be empty")
+	if builderFlags.FileNameFlags == nil || builderFlags.FileNameFlags.Filenames == nil {
+		return finder, nil
 	}
-	jsonPathCond = strings.Trim(jsonPathCond, `'"`)
-
-	return relaxedJSONPathExp, jsonPathCond, nil
+	var filenames []string
+	stdin := false
+	for _, filename := range *builderFlags.FileNameFlags.Filenames {
+		if filename == "-" {
+			stdin = true
+			continue
+		}
+		filenames = append(filenames, filename)
+	}
+	if !stdin {
+		return finder, nil
+	}
+	if flags.In == nil {
+		return nil, fmt.Errorf("-f - requires manifests on stdin")
+	}
+	manifests, err := ioutil.ReadAll(flags.In)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifests from stdin: %v", err)
+	}
+	finderFlags := *builderFlags
+	fileNameFlags := *builderFlags.FileNameFlags
+	fileNameFlags.Filenames = &filenames
+	finderFlags.FileNameFlags = &fileNameFlags
+	finder.builderFlags = &finderFlags
+	finder.manifests = manifests
+	return finder, nil
 }
 
-// ResourceLocation holds the location of a resource
-type ResourceLocation struct {
-	GroupResource schema.GroupResource
-	Namespace     string
-	Name          string
+// builderResourceFinder finds the resources given by builderFlags and args, as
+// ResourceBuilderFlags.ToBuilder does, along with those in manifests read from stdin, if any.
+// The manifests are kept and parsed again every time the resources are looked up, and with
+// Latest the resources they identify by kind, namespace and name are fetched from the server
+// each time. Resources are listed in chunks of chunkSize, following the continue token of
+// every chunk until the whole list has been visited, so that no resource is left out however
+// many there are.
+type builderResourceFinder struct {
+	restClientGetter genericclioptions.RESTClientGetter
+	// builderFlags does not list "-" among its file names
+	builderFlags *genericclioptions.ResourceBuilderFlags
+	args         []string
+	manifests    []byte
+	// chunkSize is the number of resources to list at once, or 0 to list them all at once
+	chunkSize int64
 }
 
-// UIDMap maps ResourceLocation with UID
-type UIDMap map[ResourceLocation]types.UID
-
-// WaitOptions is a set of options that allows you to wait.  This is the object reflects the runtime needs of a wait
-// command, making the logic itself easy to unit test with our existing mocks.
-type WaitOptions struct {
-	ResourceFinder genericclioptions.ResourceFinder
-	// UIDMap maps a resource location to a UID.  It is optional, but ConditionFuncs may choose to use it to make the result
-	// more reliable.  For instance, delete can look for UID consistency during delegated calls.
-	UIDMap        UIDMap
-	DynamicClient dynamic.Interface
-	Timeout       time.Duration
-	ForCondition  string
-
-	Printer     printers.ResourcePrinter
-	ConditionFn ConditionFunc
-	genericclioptions.IOStreams
-}
+// Do returns a visitor for the resources, read from the manifests anew
+func (f *builderResourceFinder) Do() resource.Visitor {
+	namespace, enforceNamespace, namespaceErr := f.restClientGetter.ToRawKubeConfigLoader().Namespace()
 
-// ConditionFunc is the interface for providing condition checks
-type ConditionFunc func(info *resource.Info, o *WaitOptions) (finalObject runtime.Object, done bool, err error)
+	builder := resource.NewBuilder(f.restClientGetter).
+		NamespaceParam(namespace).DefaultNamespace()
+	if f.builderFlags.AllNamespaces != nil {
+		builder.AllNamespaces(*f.builderFlags.AllNamespaces)
+	}
+	if f.builderFlags.Scheme != nil {
+		builder.WithScheme(f.builderFlags.Scheme, f.builderFlags.Scheme.PrioritizedVersionsAllGroups()...)
+	} else {
+		builder.Unstructured()
+	}
+	if f.manifests != nil {
+		builder.Stream(bytes.NewReader(f.manifests), "STDIN")
+	}
+	if f.builderFlags.FileNameFlags != nil {
+		opts := f.builderFlags.FileNameFlags.ToOptions()
+		builder.FilenameParam(enforceNamespace, &opts)
+	}
 
-// RunWait runs the waiting logic
-func (o *WaitOptions) RunWait() error {
-	visitCount := 0
-	visitFunc := func(info *resource.Info, err error) error {
-		if err != nil {
-			return err
+	if f.builderFlags.Local == nil || !*f.builderFlags.Local {
+		all := f.builderFlags.All != nil && *f.builderFlags.All
+		builder.ResourceTypeOrNameArgs(all, f.args...)
+		if f.builderFlags.LabelSelector != nil {
+			builder.LabelSelectorParam(*f.builderFlags.LabelSelector)
 		}
-
-		visitCount++
-		finalObject, success, err := o.ConditionFn(info, o)
-		if success {
-			o.Printer.PrintObj(finalObject, o.Out)
-			return nil
+		if f.builderFlags.FieldSelector != nil {
+			builder.FieldSelectorParam(*f.builderFlags.FieldSelector)
 		}
-		if err == nil {
-			return fmt.Errorf("%v unsatisified for unknown reason", finalObject)
+		if f.builderFlags.Latest {
+			builder.Latest()
+		}
+	} else {
+		builder.Local()
+		if len(f.args) > 0 {
+			builder.AddError(resource.LocalResourceError)
 		}
-		return err
 	}
-	visitor := o.ResourceFinder.Do()
-	isForDelete := strings.ToLower(o.ForCondition) == "delete"
-	if visitor, ok := visitor.(*resource.Result); ok && isForDelete {
-		visitor.IgnoreErrors(apierrors.IsNotFound)
+	if !f.builderFlags.StopOnFirstError {
+		builder.ContinueOnError()
 	}
+	return builder.RequestChunksOf(f.chunkSize).Flatten().AddError(namespaceErr).Do()
+}
 
-	err := visitor.Visit(visitFunc)
-	if err != nil {
-		return err
+// hasCondition returns true if any of the conditions is the given keyword
+func hasCondition(conditions []string, keyword string) bool {
+	for _, condition := range conditions {
+		if strings.ToLower(condition) == keyword {
+			return true
+		}
 	}
-	if visitCount == 0 && !isForDelete {
-		return errNoMatchingResources
+	return false
+}
+
+func hasCountCondition(conditions []string) bool {
+	for _, condition := range conditions {
+		if strings.HasPrefix(strings.ToLower(condition), "count") {
+			return true
+		}
 	}
-	return err
+	return false
 }
 
-// IsDeleted is a condition func for waiting for something to be deleted
-func IsDeleted(info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
-	endTime := time.Now().Add(o.Timeout)
-	for {
-		if len(info.Name) == 0 {
-			return info.Object, false, fmt.Errorf("resource name must be provided")
+// hasChangedCondition returns true if one of the conditions is a jsonpath changed or a
+// rollout-settled condition, which needs more than one check to be met
+func hasChangedCondition(conditions []string) bool {
+	for _, condition := range conditions {
+		spec, err := parseCondition(condition)
+		if err != nil {
+			continue
+		}
+		if spec.kind == conditionKindJSONPath && spec.jsonPathOperator == "changed" || spec.kind == conditionKindRolloutSettled && !spec.anyGeneration {
+			return true
 		}
+	}
+	return false
+}
 
-		nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+// hasCoversCondition returns true if one of the conditions is a jsonpath covers condition, which is
+// checked across all the resources rather than on each of them
+func hasCoversCondition(conditions []string) bool {
+	for _, condition := range conditions {
+		if spec, err := parseCondition(condition); err == nil && spec.kind == conditionKindJSONPath && spec.jsonPathOperator == "covers" {
+			return true
+		}
+	}
+	return false
+}
 
-		// List with a name field selector to get the current resourceVersion to watch from (not the object's resourceVersion)
-		gottenObjList, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: nameSelector})
-		if apierrors.IsNotFound(err) {
-			return info.Object, true, nil
+// countWaitFor parses a count condition such as count>=3
+func countWaitFor(condition string) (*CountWait, error) {
+	expression := condition[len("count"):]
+	for _, operator := range []string{">=", "<=", "!=", "==", "=", ">", "<"} {
+		if !strings.HasPrefix(expression, operator) {
+			continue
 		}
-		if err != nil {
-			// TODO this could do something slightly fancier if we wish
-			return info.Object, false, err
+		count, err := strconv.Atoi(expression[len(operator):])
+		if err != nil || count < 0 {
+			break
 		}
-		if len(gottenObjList.Items) != 1 {
-			return info.Object, true, nil
+		if operator == "==" {
+			operator = "="
 		}
-		gottenObj := &gottenObjList.Items[0]
-		resourceLocation := ResourceLocation{
-			GroupResource: info.Mapping.Resource.GroupResource(),
-			Namespace:     gottenObj.GetNamespace(),
-			Name:          gottenObj.GetName(),
+		return &CountWait{operator: operator, count: count}, nil
+	}
+	return nil, fmt.Errorf("count condition %q must be an operator followed by a non-negative integer, for instance count>=3", condition)
+}
+
+// conditionFnForKinds returns a WaitOptions.ConditionFnFor which rejects the kinds of
+// resources that one of the conditions does not apply to. Conditions other than the
+// shortcuts for one kind, such as rollout, are checked the same way on any kind of resource,
+// so the ConditionFn is used for every kind they are not rejected for, except for namespaces
+// waited on to be deleted, which are waited on with NewNamespaceDeletionWaiter.
+func conditionFnForKinds(conditions []string) (func(*meta.RESTMapping) (ConditionFunc, error), error) {
+	specs := make([]conditionSpec, 0, len(conditions))
+	for _, condition := range conditions {
+		spec, err := parseCondition(condition)
+		if err != nil {
+			return nil, err
 		}
-		if uid, ok := o.UIDMap[resourceLocation]; ok {
-			if gottenObj.GetUID() != uid {
-				return gottenObj, true, nil
+		specs = append(specs, spec)
+	}
+	return func(mapping *meta.RESTMapping) (ConditionFunc, error) {
+		for _, spec := range specs {
+			if err := spec.appliesTo(mapping.GroupVersionKind.GroupKind()); err != nil {
+				return nil, err
 			}
 		}
-
-		watchOptions := metav1.ListOptions{}
-		watchOptions.FieldSelector = nameSelector
-		watchOptions.ResourceVersion = gottenObjList.GetResourceVersion()
-		objWatch, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Watch(context.TODO(), watchOptions)
-		if err != nil {
-			return gottenObj, false, err
+		if len(specs) == 1 && specs[0].kind == conditionKindDelete && mapping.GroupVersionKind.GroupKind() == (schema.GroupKind{Kind: "Namespace"}) {
+			return NewNamespaceDeletionWaiter(), nil
 		}
+		return nil, nil
+	}, nil
+}
 
-		timeout := endTime.Sub(time.Now())
-		errWaitTimeoutWithName := extendErrWaitTimeout(wait.ErrWaitTimeout, info)
-		if timeout < 0 {
-			// we're out of time
-			return gottenObj, false, errWaitTimeoutWithName
+func allConditionsFuncFor(conditions []string, ignoreCase bool) (ConditionFunc, error) {
+	w := AllConditionsWait{conditions: conditions}
+	for _, condition := range conditions {
+		conditionFn, err := conditionFuncFor(condition, ignoreCase)
+		if err != nil {
+			return nil, err
 		}
+		w.conditionFns = append(w.conditionFns, conditionFn)
+	}
+	return w.IsConditionMet, nil
+}
 
-		ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), o.Timeout)
-		watchEvent, err := watchtools.UntilWithoutRetry(ctx, objWatch, Wait{errOut: o.ErrOut}.IsDeleted)
-		cancel()
-		switch {
-		case err == nil:
-			return watchEvent.Object, true, nil
-		case err == watchtools.ErrWatchClosed:
-			continue
-		case err == wait.ErrWaitTimeout:
-			if watchEvent != nil {
-				return watchEvent.Object, false, errWaitTimeoutWithName
-			}
-			return gottenObj, false, errWaitTimeoutWithName
-		default:
-			return gottenObj, false, err
+func anyConditionsFuncFor(conditions []string, ignoreCase bool) (ConditionFunc, error) {
+	w := AnyConditionsWait{conditions: conditions}
+	for _, condition := range conditions {
+		conditionFn, err := conditionFuncFor(condition, ignoreCase)
+		if err != nil {
+			return nil, err
 		}
+		w.conditionFns = append(w.conditionFns, conditionFn)
 	}
+	return w.IsConditionMet, nil
 }
 
-// Wait has helper methods for handling watches, including error handling.
-type Wait struct {
-	errOut io.Writer
+// conditionKind is the kind of a condition to wait on
+type conditionKind string
+
+const (
+	conditionKindDelete             conditionKind = "delete"
+	conditionKindCreate             conditionKind = "create"
+	conditionKindSynced             conditionKind = "synced"
+	conditionKindBound              conditionKind = "bound"
+	conditionKindReady              conditionKind = "ready"
+	conditionKindNoFinalizers       conditionKind = "no-finalizers"
+	conditionKindHasKey             conditionKind = "has-key"
+	conditionKindHasLabel           conditionKind = "label"
+	conditionKindHasAnnotation      conditionKind = "annotation"
+	conditionKindOwnedBy            conditionKind = "owned-by"
+	conditionKindJobComplete        conditionKind = "job-complete"
+	conditionKindHPAStable          conditionKind = "hpa-stable"
+	conditionKindRollout            conditionKind = "rollout"
+	conditionKindContainersReady    conditionKind = "containers-ready"
+	conditionKindContainerReady     conditionKind = "container-ready"
+	conditionKindRolloutSettled     conditionKind = "rollout-settled"
+	conditionKindInitComplete       conditionKind = "init-complete"
+	conditionKindHasEndpoints       conditionKind = "has-endpoints"
+	conditionKindNewReplicaSetReady conditionKind = "new-replicaset-ready"
+	conditionKindEstablished        conditionKind = "established"
+	conditionKindPersists           conditionKind = "persists"
+	conditionKindCondition          conditionKind = "condition"
+	conditionKindTemplate           conditionKind = "template"
+	conditionKindJSONPath           conditionKind = "jsonpath"
+	conditionKindJSONPathCmp        conditionKind = "jsonpath-cmp"
+	conditionKindJSONPathAll        conditionKind = "jsonpath-all"
+	conditionKindJSONPathAny        conditionKind = "jsonpath-any"
+)
+
+// conditionSpec is a condition parsed from --for. Only the fields of its kind are set, and
+// they are not validated any further than needed to tell them apart: that is left to the
+// waiter built from it.
+type conditionSpec struct {
+	kind conditionKind
+
+	// key is the key of a has-key condition
+	key string
+	// metadataKey and metadataValue are those of a label or an annotation condition.
+	// metadataHasValue is unset when only the presence of the key is waited for.
+	metadataKey      string
+	metadataValue    string
+	metadataHasValue bool
+	// ownerKind and ownerName are those of the owner of an owned-by condition. ownerKind may
+	// be qualified with a group, as in replicaset.apps.
+	ownerKind string
+	ownerName string
+	// container is the name of the container of a container-ready condition
+	container string
+	// count is the number of containers of a containers-ready or an init-complete condition,
+	// or 0 for all of them, the number of ready addresses of a has-endpoints condition, or the
+	// number of ready replicas of a new-replicaset-ready condition
+	count int
+	// anyGeneration is set for rollout-settled=any-generation, which is also met by a
+	// rollout completed at the generation first observed
+	anyGeneration bool
+	// conditionName, conditionStatus and the optional conditionReason are those of a
+	// condition. conditionStatus may list several statuses separated by commas.
+	conditionName   string
+	conditionStatus string
+	conditionReason string
+	// conditionNegated is set for condition!=NAME, which is met by any status other than
+	// conditionStatus
+	conditionNegated bool
+	// template is the Go template of a template condition
+	template string
+	// jsonPathExpression, jsonPathOperator and jsonPathCondition are those of a jsonpath,
+	// jsonpath-cmp, jsonpath-all or jsonpath-any condition. The operator is "exists", "absent" or "changed" when there is no value to
+	// compare with, and "covers" when the values are checked across all the resources.
+	jsonPathExpression string
+	jsonPathOperator   string
+	jsonPathCondition  string
 }
 
-// IsDeleted returns true if the object is deleted. It prints any errors it encounters.
-func (w Wait) IsDeleted(event watch.Event) (bool, error) {
-	switch event.Type {
-	case watch.Error:
-		// keep waiting in the event we see an error - we expect the watch to be closed by
-		// the server if the error is unrecoverable.
-		err := apierrors.FromObject(event.Object)
-		fmt.Fprintf(w.errOut, "error: An error occurred while waiting for the object to be deleted: %v", err)
-		return false, nil
+// String formats the condition the way it is given to --for, so that parsing it again
+// returns the same conditionSpec
+func (c conditionSpec) String() string {
+	switch c.kind {
+	case conditionKindHasKey:
+		return fmt.Sprintf("has-key=%s", c.key)
+	case conditionKindHasLabel, conditionKindHasAnnotation:
+		if c.metadataHasValue {
+			return fmt.Sprintf("%s=%s=%s", c.kind, c.metadataKey, c.metadataValue)
+		}
+		return fmt.Sprintf("%s=%s", c.kind, c.metadataKey)
+	case conditionKindOwnedBy:
+		return fmt.Sprintf("owned-by=%s/%s", c.ownerKind, c.ownerName)
+	case conditionKindContainerReady:
+		return fmt.Sprintf("container-ready=%s", c.container)
+	case conditionKindContainersReady:
+		if c.count > 0 {
+			return fmt.Sprintf("containers-ready=%d", c.count)
+		}
+	case conditionKindInitComplete, conditionKindHasEndpoints, conditionKindNewReplicaSetReady:
+		if c.count > 0 {
+			return fmt.Sprintf("%s=%d", c.kind, c.count)
+		}
+	case conditionKindRolloutSettled:
+		if c.anyGeneration {
+			return "rollout-settled=any-generation"
+		}
+	case conditionKindCondition:
+		if c.conditionNegated {
+			return fmt.Sprintf("condition!=%s", c.conditionName)
+		}
+		if len(c.conditionReason) > 0 {
+			return fmt.Sprintf("condition=%s=%s,reason=%s", c.conditionName, c.conditionStatus, c.conditionReason)
+		}
+		return fmt.Sprintf("condition=%s=%s", c.conditionName, c.conditionStatus)
+	case conditionKindTemplate:
+		return fmt.Sprintf("template=%s", c.template)
+	case conditionKindJSONPath:
+		switch c.jsonPathOperator {
+		case "exists":
+			return fmt.Sprintf("jsonpath=%s", c.jsonPathExpression)
+		case "absent":
+			return fmt.Sprintf("jsonpath=!%s", c.jsonPathExpression)
+		case "changed":
+			return fmt.Sprintf("jsonpath=%schanged", c.jsonPathExpression)
+		case "covers":
+			return fmt.Sprintf("jsonpath=%scovers=%s", c.jsonPathExpression, c.jsonPathCondition)
+		}
+		return fmt.Sprintf("jsonpath=%s%s%s", c.jsonPathExpression, c.jsonPathOperator, c.jsonPathCondition)
+	case conditionKindJSONPathCmp, conditionKindJSONPathAll, conditionKindJSONPathAny:
+		return fmt.Sprintf("%s=%s%s%s", c.kind, c.jsonPathExpression, c.jsonPathOperator, c.jsonPathCondition)
+	}
+	return string(c.kind)
+}
+
+// appliesTo returns an error if the condition is a shortcut for some kinds of resources
+// and kind is not one of them. Every other condition applies to any kind, as does a
+// shortcut when the kind is not known.
+func (c conditionSpec) appliesTo(kind schema.GroupKind) error {
+	if kind.Empty() {
+		return nil
+	}
+	switch c.kind {
+	case conditionKindHasKey:
+		if kind != (schema.GroupKind{Kind: "Secret"}) && kind != (schema.GroupKind{Kind: "ConfigMap"}) {
+			return fmt.Errorf("has-key only applies to secrets and configmaps, not %s", kind)
+		}
+	case conditionKindJobComplete:
+		if kind != (schema.GroupKind{Group: "batch", Kind: "Job"}) {
+			return fmt.Errorf("job-complete only applies to jobs, not %s", kind)
+		}
+	case conditionKindHPAStable:
+		if kind != (schema.GroupKind{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}) {
+			return fmt.Errorf("hpa-stable only applies to horizontalpodautoscalers, not %s", kind)
+		}
+	case conditionKindRollout:
+		if _, err := polymorphichelpers.StatusViewerFor(kind); err != nil {
+			return fmt.Errorf("rollout only applies to deployments, daemonsets and statefulsets, not %s", kind)
+		}
+	case conditionKindContainersReady, conditionKindContainerReady, conditionKindInitComplete:
+		if kind != (schema.GroupKind{Kind: "Pod"}) {
+			return fmt.Errorf("%s only applies to pods, not %s", c.kind, kind)
+		}
+	case conditionKindEstablished:
+		if kind != crdGroupKind {
+			return fmt.Errorf("established only applies to customresourcedefinitions, not %s", kind)
+		}
+	case conditionKindHasEndpoints:
+		if kind != (schema.GroupKind{Kind: "Service"}) {
+			return fmt.Errorf("has-endpoints only applies to services, not %s", kind)
+		}
+	case conditionKindRolloutSettled, conditionKindNewReplicaSetReady:
+		if kind != (schema.GroupKind{Group: "apps", Kind: "Deployment"}) {
+			return fmt.Errorf("%s only applies to deployments, not %s", c.kind, kind)
+		}
+	}
+	return nil
+}
+
+// parseCondition parses a condition given to --for. Count conditions are not parsed here,
+// see countWaitFor.
+func parseCondition(condition string) (conditionSpec, error) {
+	keyword := conditionKind(strings.ToLower(condition))
+	switch keyword {
+	case conditionKindDelete, conditionKindCreate, conditionKindSynced, conditionKindBound, conditionKindReady,
+		conditionKindNoFinalizers, conditionKindJobComplete, conditionKindHPAStable, conditionKindRollout, conditionKindContainersReady,
+		conditionKindRolloutSettled, conditionKindInitComplete, conditionKindHasEndpoints, conditionKindEstablished, conditionKindPersists:
+		return conditionSpec{kind: keyword}, nil
+	case "rollout-settled=any-generation":
+		return conditionSpec{kind: conditionKindRolloutSettled, anyGeneration: true}, nil
+	}
+	switch {
+	case strings.HasPrefix(strings.ToLower(condition), "has-key="):
+		key := condition[len("has-key="):]
+		if len(key) == 0 {
+			return conditionSpec{}, fmt.Errorf("has-key requires a key, for instance has-key=tls.crt")
+		}
+		return conditionSpec{kind: conditionKindHasKey, key: key}, nil
+	case strings.HasPrefix(strings.ToLower(condition), "label="):
+		return parseMetadataCondition(conditionKindHasLabel, condition[len("label="):], "label=environment=production")
+	case strings.HasPrefix(strings.ToLower(condition), "annotation="):
+		return parseMetadataCondition(conditionKindHasAnnotation, condition[len("annotation="):], "annotation=example.com/state=done")
+	case strings.HasPrefix(strings.ToLower(condition), "owned-by="):
+		owner := condition[len("owned-by="):]
+		slash := strings.Index(owner, "/")
+		if slash <= 0 || slash == len(owner)-1 {
+			return conditionSpec{}, fmt.Errorf("owned-by requires the kind and the name of the owner, for instance owned-by=ReplicaSet/nginx-5d4f8")
+		}
+		return conditionSpec{kind: conditionKindOwnedBy, ownerKind: owner[:slash], ownerName: owner[slash+1:]}, nil
+	case strings.HasPrefix(strings.ToLower(condition), "containers-ready="):
+		count, err := strconv.Atoi(condition[len("containers-ready="):])
+		if err != nil || count < 1 {
+			return conditionSpec{}, fmt.Errorf("containers-ready count %q must be a positive integer", condition[len("containers-ready="):])
+		}
+		return conditionSpec{kind: conditionKindContainersReady, count: count}, nil
+	case strings.HasPrefix(strings.ToLower(condition), "container-ready="):
+		container := condition[len("container-ready="):]
+		if len(container) == 0 {
+			return conditionSpec{}, fmt.Errorf("container-ready requires the name of a container, for instance container-ready=app")
+		}
+		return conditionSpec{kind: conditionKindContainerReady, container: container}, nil
+	case strings.HasPrefix(strings.ToLower(condition), "init-complete="):
+		count, err := strconv.Atoi(condition[len("init-complete="):])
+		if err != nil || count <= 0 {
+			return conditionSpec{}, fmt.Errorf("init-complete count %q must be a positive integer", condition[len("init-complete="):])
+		}
+		return conditionSpec{kind: conditionKindInitComplete, count: count}, nil
+	case strings.HasPrefix(strings.ToLower(condition), "has-endpoints="):
+		count, err := strconv.Atoi(condition[len("has-endpoints="):])
+		if err != nil || count <= 0 {
+			return conditionSpec{}, fmt.Errorf("has-endpoints count %q must be a positive integer", condition[len("has-endpoints="):])
+		}
+		return conditionSpec{kind: conditionKindHasEndpoints, count: count}, nil
+	case strings.HasPrefix(strings.ToLower(condition), "new-replicaset-ready="):
+		count, err := strconv.Atoi(condition[len("new-replicaset-ready="):])
+		if err != nil || count <= 0 {
+			return conditionSpec{}, fmt.Errorf("new-replicaset-ready count %q must be a positive integer", condition[len("new-replicaset-ready="):])
+		}
+		return conditionSpec{kind: conditionKindNewReplicaSetReady, count: count}, nil
+	case strings.HasPrefix(condition, "condition!="):
+		conditionName := condition[len("condition!="):]
+		switch {
+		case len(conditionName) == 0:
+			return conditionSpec{}, fmt.Errorf("condition!= requires a condition name, for instance condition!=Ready")
+		case strings.Contains(conditionName, ",reason="):
+			return conditionSpec{}, fmt.Errorf("condition!=%s cannot require a reason", conditionName[:strings.Index(conditionName, ",reason=")])
+		case strings.Contains(conditionName, "="):
+			return conditionSpec{}, fmt.Errorf("condition!=%s does not take a status, it waits for any status other than True", conditionName[:strings.Index(conditionName, "=")])
+		}
+		return conditionSpec{
+			kind:             conditionKindCondition,
+			conditionName:    conditionName,
+			conditionStatus:  "True",
+			conditionNegated: true,
+		}, nil
+	case strings.HasPrefix(condition, "condition="):
+		conditionName := condition[len("condition="):]
+		conditionReason := ""
+		if reasonIndex := strings.Index(conditionName, ",reason="); reasonIndex != -1 {
+			conditionReason = conditionName[reasonIndex+len(",reason="):]
+			conditionName = conditionName[0:reasonIndex]
+			if len(conditionReason) == 0 {
+				return conditionSpec{}, fmt.Errorf("condition reason cannot be empty")
+			}
+		}
+		conditionValue := "true"
+		if equalsIndex := strings.Index(conditionName, "="); equalsIndex != -1 {
+			conditionValue = conditionName[equalsIndex+1:]
+			conditionName = conditionName[0:equalsIndex]
+			if strings.Contains(conditionValue, ",") {
+				for _, status := range strings.Split(conditionValue, ",") {
+					if len(status) == 0 {
+						return conditionSpec{}, fmt.Errorf("condition status cannot be empty in %q", conditionValue)
+					}
+				}
+			}
+		}
+		return conditionSpec{
+			kind:            conditionKindCondition,
+			conditionName:   conditionName,
+			conditionStatus: conditionValue,
+			conditionReason: conditionReason,
+		}, nil
+	case strings.HasPrefix(condition, "template="):
+		return conditionSpec{kind: conditionKindTemplate, template: condition[len("template="):]}, nil
+	case strings.HasPrefix(condition, "jsonpath="):
+		jsonPathExp, jsonPathOp, jsonPathCond := splitJSONPathCondition(condition[len("jsonpath="):])
+		return conditionSpec{
+			kind:               conditionKindJSONPath,
+			jsonPathExpression: jsonPathExp,
+			jsonPathOperator:   jsonPathOp,
+			jsonPathCondition:  jsonPathCond,
+		}, nil
+	case strings.HasPrefix(condition, "jsonpath-cmp="), strings.HasPrefix(condition, "jsonpath-all="), strings.HasPrefix(condition, "jsonpath-any="):
+		equals := strings.Index(condition, "=")
+		jsonPathExp, jsonPathOp, jsonPathCond := splitJSONPathCondition(condition[equals+1:])
+		return conditionSpec{
+			kind:               conditionKind(condition[:equals]),
+			jsonPathExpression: jsonPathExp,
+			jsonPathOperator:   jsonPathOp,
+			jsonPathCondition:  jsonPathCond,
+		}, nil
+	}
+	return conditionSpec{}, fmt.Errorf("unrecognized condition: %q", condition)
+}
+
+// splitJSONPathCondition splits what follows "jsonpath=" into the expression, the operator
+// and the value to compare with
+func splitJSONPathCondition(expression string) (jsonPathExp, jsonPathOp, jsonPathCond string) {
+	// Only the first "=" after the expression is a boundary: everything after it
+	// is the raw value, which may itself contain "=". A braced expression ends at
+	// its first "}", so that it may contain "=" in filters such as [?(@.type=="Ready")].
+	expressionEnd := 0
+	if end := strings.Index(expression, "}"); end != -1 && strings.HasPrefix(strings.TrimPrefix(expression, "!"), "{") {
+		expressionEnd = end + 1
+	}
+	splitStr := append([]string{"jsonpath"}, strings.SplitN(expression[expressionEnd:], "=", 2)...)
+	splitStr[1] = expression[:expressionEnd] + splitStr[1]
+	switch {
+	case expressionEnd > 0 && len(splitStr) == 2 && splitStr[1][expressionEnd:] == "changed":
+		jsonPathExp, jsonPathOp = splitStr[1][:expressionEnd], "changed"
+	case expressionEnd > 0 && len(splitStr) == 3 && splitStr[1][expressionEnd:] == "covers":
+		jsonPathExp, jsonPathOp, jsonPathCond = splitStr[1][:expressionEnd], "covers", splitStr[2]
+	case len(splitStr) == 3:
+		// "=", "!=", ">=", "<=", "~=", "*=", "^=" and "$=" all end at the
+		// second "=", so any operator prefix is left on the end of the expression.
+		jsonPathExp, jsonPathOp, jsonPathCond = splitStr[1], "=", splitStr[2]
+		for _, prefix := range []string{"!", ">", "<", "~", "*", "^", "$"} {
+			if strings.HasSuffix(jsonPathExp, prefix) {
+				jsonPathExp, jsonPathOp = strings.TrimSuffix(jsonPathExp, prefix), prefix+"="
+				break
+			}
+		}
+		if jsonPathOp == "=" && strings.HasPrefix(jsonPathCond, "=") {
+			// "==" is accepted as an alias of "="
+			jsonPathCond = jsonPathCond[1:]
+		}
+		if strings.HasSuffix(jsonPathExp, "#") {
+			jsonPathExp, jsonPathOp = strings.TrimSuffix(jsonPathExp, "#"), "#"+jsonPathOp
+		}
+	case strings.ContainsAny(splitStr[1][expressionEnd:], "><"):
+		opIndex := expressionEnd + strings.IndexAny(splitStr[1][expressionEnd:], "><")
+		jsonPathExp, jsonPathOp, jsonPathCond = splitStr[1][:opIndex], splitStr[1][opIndex:opIndex+1], splitStr[1][opIndex+1:]
+		if strings.HasSuffix(jsonPathExp, "#") {
+			jsonPathExp, jsonPathOp = strings.TrimSuffix(jsonPathExp, "#"), "#"+jsonPathOp
+		}
+	case strings.HasPrefix(splitStr[1], "!"):
+		jsonPathExp, jsonPathOp = splitStr[1][1:], "absent"
+	default:
+		jsonPathExp, jsonPathOp = splitStr[1], "exists"
+	}
+	return jsonPathExp, jsonPathOp, jsonPathCond
+}
+
+// parseMetadataCondition parses the KEY or KEY=VALUE of a label or an annotation condition.
+// Keys cannot contain "=", so the value is whatever follows the first one, and may itself
+// contain "=".
+func parseMetadataCondition(kind conditionKind, keyValue, example string) (conditionSpec, error) {
+	spec := conditionSpec{kind: kind, metadataKey: keyValue}
+	if equals := strings.Index(keyValue, "="); equals != -1 {
+		spec.metadataKey, spec.metadataValue, spec.metadataHasValue = keyValue[:equals], keyValue[equals+1:], true
+	}
+	if len(spec.metadataKey) == 0 {
+		return conditionSpec{}, fmt.Errorf("%s requires a key, and optionally a value, for instance %s", kind, example)
+	}
+	return spec, nil
+}
+
+func conditionFuncFor(condition string, ignoreCase bool) (ConditionFunc, error) {
+	spec, err := parseCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+	switch spec.kind {
+	case conditionKindDelete:
+		return IsDeleted, nil
+	case conditionKindCreate:
+		return IsCreated, nil
+	case conditionKindSynced:
+		return GenerationWait{}.IsGenerationObserved, nil
+	case conditionKindBound:
+		return PhaseWait{phase: "Bound", failedPhases: []string{"Lost"}}.IsPhaseReached, nil
+	case conditionKindReady:
+		return NewReadyWait().IsReady, nil
+	case conditionKindNoFinalizers:
+		return FinalizersWait{}.IsFinalizersRemoved, nil
+	case conditionKindHasKey:
+		return KeyWait{key: spec.key}.IsKeyPresent, nil
+	case conditionKindHasLabel:
+		w, err := newLabelWait(spec.metadataKey, spec.metadataValue, spec.metadataHasValue)
+		if err != nil {
+			return nil, err
+		}
+		return w.IsMetadataSet, nil
+	case conditionKindHasAnnotation:
+		w, err := newAnnotationWait(spec.metadataKey, spec.metadataValue, spec.metadataHasValue)
+		if err != nil {
+			return nil, err
+		}
+		return w.IsMetadataSet, nil
+	case conditionKindOwnedBy:
+		return newOwnerWait(spec.ownerKind, spec.ownerName).IsOwnedBy, nil
+	case conditionKindJobComplete:
+		return JobWait{}.IsJobComplete, nil
+	case conditionKindHPAStable:
+		return HPAStableWait{}.IsHPAStable, nil
+	case conditionKindRollout:
+		return RolloutWait{}.IsRolledOut, nil
+	case conditionKindContainersReady:
+		return ContainersReadyWait{count: spec.count}.IsContainersReady, nil
+	case conditionKindContainerReady:
+		return ContainerReadyWait{container: spec.container}.IsContainerReady, nil
+	case conditionKindRolloutSettled:
+		return newRolloutSettledWait(spec.anyGeneration).IsRolloutSettled, nil
+	case conditionKindInitComplete:
+		return InitCompleteWait{count: spec.count}.IsInitComplete, nil
+	case conditionKindHasEndpoints:
+		return EndpointsWait{count: spec.count}.HasEndpoints, nil
+	case conditionKindNewReplicaSetReady:
+		return NewReplicaSetWait{count: spec.count}.IsNewReplicaSetReady, nil
+	case conditionKindEstablished:
+		return EstablishedWait{}.IsEstablished, nil
+	case conditionKindPersists:
+		return PersistsWait{}.IsPersisting, nil
+	case conditionKindCondition:
+		return ConditionalWait{
+			conditionName:   spec.conditionName,
+			conditionStatus: spec.conditionStatus,
+			conditionReason: spec.conditionReason,
+			negated:         spec.conditionNegated,
+		}.IsConditionMet, nil
+	case conditionKindTemplate:
+		w, err := newTemplateWait(spec.template)
+		if err != nil {
+			return nil, err
+		}
+		return w.IsTemplateTrue, nil
+	case conditionKindJSONPath:
+		if spec.jsonPathOperator == "covers" {
+			return nil, fmt.Errorf("jsonpath covers condition %q is checked across all the resources, not on each of them, see WaitOptions.Aggregate", condition)
+		}
+		w, err := newJSONPathWait(spec.jsonPathExpression, spec.jsonPathOperator, spec.jsonPathCondition, ignoreCase)
+		if err != nil {
+			return nil, err
+		}
+		return w.IsJSONPathConditionMet, nil
+	case conditionKindJSONPathCmp:
+		w, err := newJSONPathCmpWait(spec.jsonPathExpression, spec.jsonPathOperator, spec.jsonPathCondition)
+		if err != nil {
+			return nil, err
+		}
+		return w.IsJSONPathConditionMet, nil
+	case conditionKindJSONPathAll, conditionKindJSONPathAny:
+		w, err := newJSONPathQuantifiedWait(spec.kind, spec.jsonPathExpression, spec.jsonPathOperator, spec.jsonPathCondition, ignoreCase)
+		if err != nil {
+			return nil, err
+		}
+		return w.IsJSONPathConditionMet, nil
+	}
+	return nil, fmt.Errorf("unrecognized condition: %q", condition)
+}
+
+// newJSONPathWait validates a JSONPath condition and does all of its parsing up front: the
+// expressions, the regular expression and the expected values are kept on the JSONPathWait,
+// so checking an object does not parse anything again however long the wait.
+func newJSONPathWait(jsonPathExp, jsonPathOp, jsonPathCond string, ignoreCase bool) (JSONPathWait, error) {
+	jsonPathExp, jsonPathCond, err := processJSONPathInput(jsonPathExp, jsonPathOp, jsonPathCond)
+	if err != nil {
+		return JSONPathWait{}, err
+	}
+	j, err := newJSONPathParser(jsonPathExp)
+	if err != nil {
+		return JSONPathWait{}, err
+	}
+	w := JSONPathWait{
+		jsonPathExpression: jsonPathExp,
+		jsonPathCondition:  jsonPathCond,
+		jsonPathOperator:   jsonPathOp,
+		jsonPathParser:     j,
+		multiValue:         isMultiValueExpression(jsonPathExp),
+		ignoreCase:         ignoreCase,
+	}
+	switch {
+	case jsonPathOp == "~=":
+		expr := jsonPathCond
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		if w.jsonPathRegexp, err = regexp.Compile(expr); err != nil {
+			return JSONPathWait{}, fmt.Errorf("jsonpath wait condition %q is not a valid regular expression: %v", jsonPathCond, err)
+		}
+	case isAgeCondition(jsonPathCond):
+		w.compareAge = true
+		if w.age, err = parseAge(jsonPathCond); err != nil {
+			return JSONPathWait{}, err
+		}
+	case isJSONPathExpression(jsonPathCond):
+		valueExp, err := cmdget.RelaxedJSONPathExpression(jsonPathCond)
+		if err != nil {
+			return JSONPathWait{}, jsonPathSyntaxError(jsonPathCond, nil)
+		}
+		if w.jsonPathValueParser, err = newJSONPathParser(valueExp); err != nil {
+			return JSONPathWait{}, err
+		}
+	case isLengthOperator(jsonPathOp):
+		w.expectedValues = []string{strings.TrimSpace(jsonPathCond)}
+	case jsonPathOp == "changed":
+		w.changes = &changeTracker{initial: map[string]string{}}
+	case !isExistenceOperator(jsonPathOp):
+		w.expectedValues = expectedValues(jsonPathCond)
+	}
+	return w, nil
+}
+
+// newJSONPathCmpWait validates a jsonpath-cmp condition, which compares the numbers two JSONPath
+// expressions resolve to on the same object, as in {.status.readyReplicas}>={.spec.replicas}
+func newJSONPathCmpWait(jsonPathExp, jsonPathOp, jsonPathCond string) (JSONPathWait, error) {
+	switch jsonPathOp {
+	case "=", "!=", ">", ">=", "<", "<=":
+	default:
+		return JSONPathWait{}, fmt.Errorf("jsonpath-cmp compares numbers with =, !=, >, >=, < or <=, as in jsonpath-cmp='{.status.readyReplicas}'>='{.spec.replicas}'")
+	}
+	if !isJSONPathExpression(strings.Trim(jsonPathCond, `'"`)) {
+		return JSONPathWait{}, fmt.Errorf("jsonpath-cmp compares with another JSONPath expression, not %q; use jsonpath= to compare with a value", jsonPathCond)
+	}
+	w, err := newJSONPathWait(jsonPathExp, jsonPathOp, jsonPathCond, false)
+	if err != nil {
+		return JSONPathWait{}, err
+	}
+	w.numeric = true
+	return w, nil
+}
+
+// newJSONPathQuantifiedWait validates a jsonpath-all or jsonpath-any condition, which compares
+// every value the expression resolves to rather than a single one, and so needs a value to
+// compare them with
+func newJSONPathQuantifiedWait(kind conditionKind, jsonPathExp, jsonPathOp, jsonPathCond string, ignoreCase bool) (JSONPathWait, error) {
+	if isExistenceOperator(jsonPathOp) || isLengthOperator(jsonPathOp) || jsonPathOp == "changed" || jsonPathOp == "covers" {
+		return JSONPathWait{}, fmt.Errorf("%s compares every value of the expression with =, !=, >, >=, <, <=, ~=, *=, ^= or $=, as in %s='{.status.containerStatuses[*].ready}'=true", kind, kind)
+	}
+	w, err := newJSONPathWait(jsonPathExp, jsonPathOp, jsonPathCond, ignoreCase)
+	if err != nil {
+		return JSONPathWait{}, err
+	}
+	w.quantifier = kind
+	return w, nil
+}
+
+// newJSONPathParser will create a new JSONPath parser based on the jsonPathExpression
+func newJSONPathParser(jsonPathExpression string) (*jsonpath.JSONPath, error) {
+	j := jsonpath.New("wait").AllowMissingKeys(true)
+	if jsonPathExpression == "" {
+		return nil, errors.New("jsonpath expression cannot be empty")
+	}
+	if err := j.Parse(jsonPathExpression); err != nil {
+		return nil, jsonPathSyntaxError(jsonPathExpression, err)
+	}
+	return j, nil
+}
+
+// jsonPathSyntaxError explains why expression is not a valid JSONPath expression. err is the
+// parser's error, or nil when the expression does not have the {...} shape at all. The parser
+// does not report where it failed, so the caret is placed from what the error message says.
+func jsonPathSyntaxError(expression string, err error) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "jsonpath expression %q is not valid", expression)
+	if err != nil {
+		fmt.Fprintf(&msg, ": %v", err)
+		if pos := jsonPathErrorPosition(expression, err); pos >= 0 {
+			fmt.Fprintf(&msg, "\n  %s\n  %s^", expression, strings.Repeat(" ", pos))
+		}
+	}
+	switch opening, closing := strings.Count(expression, "{"), strings.Count(expression, "}"); {
+	case opening > closing:
+		msg.WriteString("\nThe expression is missing a closing }, as in {.status.phase}.")
+	case opening < closing:
+		msg.WriteString("\nThe expression is missing an opening {, as in {.status.phase}.")
+	case opening > 1:
+		msg.WriteString("\nOnly one {...} expression can be waited for at a time.")
+	}
+	msg.WriteString("\nExpressions look like {.status.phase} or {.status.conditions[?(@.type==\"Ready\")].status}; " +
+		"the braces and the leading dot may be left out, as in status.phase.")
+	return errors.New(msg.String())
+}
+
+// jsonPathErrorPosition returns the column in expression that a JSONPath parser error refers
+// to, or -1 if the error does not say.
+func jsonPathErrorPosition(expression string, err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "unrecognized character in action: U+"):
+		// the character is formatted with %#U, as in "U+0021 '!'"
+		code := strings.TrimPrefix(msg, "unrecognized character in action: U+")
+		if i := strings.IndexByte(code, ' '); i >= 0 {
+			code = code[:i]
+		}
+		r, parseErr := strconv.ParseUint(code, 16, 32)
+		if parseErr != nil {
+			return -1
+		}
+		if i := strings.IndexRune(expression, rune(r)); i >= 0 {
+			return utf8.RuneCountInString(expression[:i])
+		}
+	case strings.HasPrefix(msg, "unclosed"), strings.HasPrefix(msg, "unterminated"):
+		return utf8.RuneCountInString(expression) - 1
+	}
+	return -1
+}
+
+// processJSONPathInput will parses the user's JSONPath input and process the string
+func processJSONPathInput(jsonPathExpression, jsonPathOperator, jsonPathCond string) (string, string, error) {
+	relaxedJSONPathExp, err := cmdget.RelaxedJSONPathExpression(jsonPathExpression)
+	if err != nil {
+		return "", "", jsonPathSyntaxError(jsonPathExpression, nil)
+	}
+	if isExistenceOperator(jsonPathOperator) || jsonPathOperator == "changed" {
+		return relaxedJSONPathExp, "", nil
+	}
+	if jsonPathCond == "" {
+		return "", "", errors.New("jsonpath wait condition cannot be empty")
+	}
+	jsonPathCond = strings.Trim(jsonPathCond, `'"`)
+	if strings.HasPrefix(jsonPathCond, "@") {
+		if jsonPathCond, err = readJSONPathCondition(jsonPathCond[1:]); err != nil {
+			return "", "", err
+		}
+	}
+	switch {
+	case isLengthOperator(jsonPathOperator):
+		if jsonPathOperator == "#~=" || isStringOperator(jsonPathOperator[1:]) {
+			return "", "", fmt.Errorf("the %q operator is not supported, length operators are #=, #!=, #>, #>=, #< and #<=", jsonPathOperator)
+		}
+		if n, err := strconv.ParseInt(strings.TrimSpace(jsonPathCond), 10, 64); err != nil || n < 0 {
+			return "", "", fmt.Errorf("jsonpath wait condition %q must be a non-negative integer when used with the %q operator", jsonPathCond, jsonPathOperator)
+		}
+	case jsonPathOperator != "~=" && isJSONPathExpression(jsonPathCond):
+		// the expected value is read from the object by the caller
+	case isAgeCondition(jsonPathCond):
+		switch jsonPathOperator {
+		case ">", ">=", "<", "<=":
+		default:
+			return "", "", fmt.Errorf("jsonpath wait condition %q can only be used with the >, >=, < and <= operators", jsonPathCond)
+		}
+		if _, err := parseAge(jsonPathCond); err != nil {
+			return "", "", err
+		}
+	case isNumericOperator(jsonPathOperator):
+		if _, ok := parseNumber(jsonPathCond); !ok {
+			return "", "", fmt.Errorf("jsonpath wait condition %q must be a number when used with the %q operator", jsonPathCond, jsonPathOperator)
+		}
+	case jsonPathOperator == "~=":
+		// regular expressions are compiled by the caller
+	case jsonPathOperator == "covers":
+		for _, value := range strings.Split(jsonPathCond, ",") {
+			if len(strings.TrimSpace(value)) == 0 {
+				return "", "", fmt.Errorf("jsonpath covers condition %q must list the values to cover separated by commas, none of them empty", jsonPathCond)
+			}
+		}
+	case len(expectedValues(jsonPathCond)) == 0:
+		return "", "", errors.New("jsonpath wait condition must contain at least one non-empty value")
+	}
+
+	return relaxedJSONPathExp, jsonPathCond, nil
+}
+
+// readJSONPathCondition reads a jsonpath wait condition given as @FILE from the file, without
+// its trailing newline, as if it had been given on the command line
+func readJSONPathCondition(filename string) (string, error) {
+	if len(filename) == 0 {
+		return "", errors.New("jsonpath wait condition @ must be followed by the name of a file to read the value from")
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the jsonpath wait condition from %s: %v", filename, err)
+	}
+	jsonPathCond := strings.TrimRight(string(content), "\r\n")
+	if len(jsonPathCond) == 0 {
+		return "", fmt.Errorf("jsonpath wait condition read from %s cannot be empty", filename)
+	}
+	return jsonPathCond, nil
+}
+
+// isAgeCondition reports whether a jsonpath wait condition is of the form "age:30s", to
+// compare the age of a timestamp with a duration
+func isAgeCondition(jsonPathCond string) bool {
+	return strings.HasPrefix(jsonPathCond, "age:")
+}
+
+// parseAge returns the duration of an age condition
+func parseAge(jsonPathCond string) (time.Duration, error) {
+	age, err := time.ParseDuration(strings.TrimSpace(jsonPathCond[len("age:"):]))
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("jsonpath wait condition %q must be age: followed by a non-negative duration, for instance age:30s", jsonPathCond)
+	}
+	return age, nil
+}
+
+// isJSONPathExpression reports whether a jsonpath wait condition is itself a
+// JSONPath expression such as "{.metadata.generation}" rather than a literal value
+func isJSONPathExpression(jsonPathCond string) bool {
+	return strings.HasPrefix(jsonPathCond, "{") && strings.HasSuffix(jsonPathCond, "}")
+}
+
+// isExistenceOperator returns true if the jsonpath operator only checks whether the
+// expression resolves, without comparing against a value
+func isExistenceOperator(operator string) bool {
+	return operator == "exists" || operator == "absent"
+}
+
+// isStringOperator returns true if the jsonpath operator matches part of the string form
+// of the value: "*=" for a substring, "^=" for a prefix and "$=" for a suffix
+func isStringOperator(operator string) bool {
+	switch operator {
+	case "*=", "^=", "$=":
+		return true
+	}
+	return false
+}
+
+// isNumericOperator returns true if the jsonpath operator compares values as numbers
+func isNumericOperator(operator string) bool {
+	switch operator {
+	case ">", ">=", "<", "<=":
+		return true
+	}
+	return false
+}
+
+// isLengthOperator returns true if the operator compares the number of values a JSONPath
+// expression resolves to, rather than the values themselves
+func isLengthOperator(operator string) bool {
+	return strings.HasPrefix(operator, "#")
+}
+
+// isMultiValueExpression returns true if the JSONPath expression may select several values,
+// in which case their number is its length
+func isMultiValueExpression(jsonPathExpression string) bool {
+	for _, selector := range []string{"[*]", "..", "[?(", ",", ":"} {
+		if strings.Contains(jsonPathExpression, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceLocation holds the location of a resource
+type ResourceLocation struct {
+	GroupResource schema.GroupResource
+	Namespace     string
+	Name          string
+}
+
+// resourceLocationFor returns the location of the resource
+func resourceLocationFor(info *resource.Info) ResourceLocation {
+	return ResourceLocation{GroupResource: info.Mapping.Resource.GroupResource(), Namespace: info.Namespace, Name: info.Name}
+}
+
+// UIDMap maps ResourceLocation with UID
+type UIDMap map[ResourceLocation]types.UID
+
+// WaitOptions is a set of options that allows you to wait.  This is the object reflects the runtime needs of a wait
+// command, making the logic itself easy to unit test with our existing mocks.
+type WaitOptions struct {
+	ResourceFinder genericclioptions.ResourceFinder
+	// UIDMap maps a resource location to a UID.  It is optional, but ConditionFuncs may choose to use it to make the result
+	// more reliable.  For instance, delete can look for UID consistency during delegated calls.
+	UIDMap        UIDMap
+	DynamicClient dynamic.Interface
+	// IgnoreErrorFns is optional. Errors returned by the ResourceFinder which match any of
+	// these are ignored. Waits for deletion always ignore NotFound errors, since a resource
+	// which is already gone has been deleted.
+	IgnoreErrorFns []resource.ErrMatchFunc
+	// Timeout is how long to wait for each resource. Zero means there is no deadline, and the
+	// wait only ends once the condition is met or the context is done.
+	Timeout time.Duration
+	// Until is optional. When set, it is the wall-clock time by which the wait on every
+	// resource gives up, whichever of it and the Timeout comes first. A wait starting once
+	// Until has passed checks the condition once, as with CheckNow, and fails with a
+	// DeadlinePassedError if it is not met.
+	Until        time.Time
+	ForCondition string
+
+	Printer printers.ResourcePrinter
+	// PrinterFor is optional. When set, it is used in place of Printer to print every resource
+	// that meets the condition, with the outcome of the wait on it, for instance to report how
+	// long it took.
+	PrinterFor  func(status ResourceStatus) printers.ResourcePrinter
+	ConditionFn ConditionFunc
+	// ConditionFnFor is optional. When set, it is called with the mapping of every resource
+	// before the resource is waited on, and returns the ConditionFunc for that kind of
+	// resource, or nil to use ConditionFn. An error, such as for a condition which does not
+	// apply to the kind, fails the wait on that resource with a ConditionUnmetError without
+	// checking it, so that one invocation can wait on several kinds at once.
+	ConditionFnFor func(mapping *meta.RESTMapping) (ConditionFunc, error)
+	// IOStreams are where the outcome of the wait is written: the resources that meet the
+	// condition are printed to Out, and the errors and warnings seen while waiting on them are
+	// written to ErrOut, a line each, so that Out only holds the result.
+	genericclioptions.IOStreams
+
+	// ProgressWriter is optional. When set, a ProgressEvent is written to it as a line of
+	// JSON every time a condition is checked.
+	ProgressWriter io.Writer
+
+	// PollInterval is optional. Resources are watched for changes by default: each one is
+	// listed, then watched from the list's resourceVersion, and listed again whenever the
+	// watch is closed or expires so no change is missed. When positive, resources are
+	// instead fetched again at this interval until the condition is met.
+	PollInterval time.Duration
+	// BackoffInitial is optional. When positive, resources are polled as with PollInterval,
+	// starting at this interval and doubling it after every poll up to BackoffMax.
+	BackoffInitial time.Duration
+	// BackoffMax caps the interval between polls when BackoffInitial is set. Zero means no cap.
+	BackoffMax time.Duration
+	// BackoffJitter randomly lengthens each interval between polls by up to this fraction of
+	// it when BackoffInitial is set, so many concurrent waits do not poll in lockstep.
+	BackoffJitter float64
+	// PollJitter is optional. When positive, the first check is delayed, and every interval
+	// between polls lengthened, by a random duration of up to PollJitter, whether or not
+	// BackoffInitial is set, so that waits started at the same time by many processes do not
+	// hit the API server in lockstep. The delay counts towards the Timeout. It is not used
+	// with CheckNow.
+	PollJitter time.Duration
+	// StableFor is optional. When positive, a condition is only met once it has held, with
+	// the same observed value, for this long. The window is measured within Timeout, so a
+	// Timeout shorter than StableFor can never be satisfied. It does not apply to IsDeleted.
+	StableFor time.Duration
+	// Settle is optional. When positive, a condition is only met once it has held for this
+	// long without a single check on which it was not met, whatever values were observed
+	// meanwhile, so that a condition flapping back briefly does not count as met. It is
+	// measured within Timeout like StableFor, and is not used when StableFor is set.
+	Settle time.Duration
+	// Concurrency is optional. When greater than 1, up to this many resources are waited on
+	// at once. Every resource still has to meet the condition, and an error on one does not
+	// stop the wait on the others. It is ignored in WaitModeAny.
+	Concurrency int
+	// Subresource is optional. When set, such as to "status" or "scale", the condition is
+	// checked against this subresource of every resource rather than the resource itself, for
+	// instance when the status subresource of a custom resource is ahead of the object the
+	// ResourceFinder returns. Subresources cannot be listed or watched, so they are fetched
+	// again every PollInterval, or every second by default. It is not used by IsDeleted.
+	Subresource string
+	// MaxTransientRetries is optional. When set, listing or watching a resource is retried, with
+	// a growing interval, after an error which is likely to go away, such as a timeout, a
+	// connection reset or being throttled. The wait fails once this many of them happen in a
+	// row. Other errors always fail the wait right away.
+	MaxTransientRetries int
+	// OnCheck is optional. It is called with the outcome of ConditionFn for every resource,
+	// for instance to record metrics. Calls are serialized, also when resources are waited
+	// on concurrently, so it does not need to be safe for concurrent use.
+	OnCheck func(info *resource.Info, obj runtime.Object, done bool, err error)
+	// Metrics is optional. When set, it is told when the wait on every resource starts and
+	// how it ended, with the kind of the condition, for instance to count timeouts.
+	Metrics Metrics
+	// CompletionHook is optional. When set, it is called once when the wait is over, with the
+	// Result and the error Wait returns, for instance to notify a dashboard. An error it
+	// returns is written to ErrOut as a warning and does not change the outcome of the wait.
+	CompletionHook func(result Result, err error) error
+	// InitialDelay is optional. When set, the first check waits this long, for instance for
+	// controllers to update a status that is stale but already satisfies the condition. The
+	// delay counts towards the Timeout.
+	InitialDelay time.Duration
+	// Mode is optional and defaults to WaitModeAll.
+	Mode WaitMode
+	// Exclude is optional. The resources found which it lists are left out of the wait as if
+	// the ResourceFinder had not found them, so they are neither waited on nor counted in
+	// Result.Matched. A ResourceLocation without a Namespace excludes the resource in any
+	// namespace. Resources it lists which are not found are ignored.
+	Exclude []ResourceLocation
+	// Count is optional. When set, the wait is for the number of resources found to compare
+	// with it, rather than for a condition on each of them, and ConditionFn is not used. The
+	// resources are looked up again every PollInterval, or every second when watching.
+	Count *CountWait
+	// Aggregate is optional. When set, the wait is for the resources found to meet it all
+	// together, rather than for a condition on each of them, as with Count, which it takes the
+	// place of. ConditionFn is not used.
+	Aggregate AggregateCondition
+	// WaitForResources is optional. When set and the ResourceFinder finds no resources, it is
+	// asked again every PollInterval, or every second when watching, until resources appear or
+	// the Timeout is reached. The time spent looking counts towards the Timeout. It is ignored
+	// when waiting for deletion, for which no resources means they are deleted.
+	WaitForResources bool
+	// RequireTransition is optional and defaults to TransitionModeNone. Otherwise, a condition
+	// only counts as met once it has been seen unmet, or the resource missing, while waiting,
+	// so that a condition its controller has not driven yet is not taken for one it has. With
+	// several conditions, each of them has to be seen unmet first. It does not apply to
+	// IsDeleted.
+	RequireTransition TransitionMode
+	// CheckNow is optional. When set, the condition is checked once against every resource as
+	// it is now, rather than waited on, and a resource which does not meet it fails with a
+	// NotMetError. Timeout, PollInterval and WaitForResources are not used then, and neither
+	// are StableFor and Settle, since a single check cannot tell whether the condition has held.
+	CheckNow bool
+	// Quiet is optional. When set, the resources that meet the condition are not printed, so
+	// that a successful wait writes nothing to Out. Errors are still returned, and warnings are
+	// still written to ErrOut.
+	Quiet bool
+	// ResourceVersion is optional. When set, every resource is watched from this
+	// resourceVersion at first rather than listed, so that only the changes made since are
+	// checked, for instance to resume a wait without counting a change it already saw again.
+	// The resource is listed as usual if the resourceVersion is too old. It only applies when
+	// watching, not when polling or with CheckNow, and is not used by IsCreated.
+	ResourceVersion string
+	// PrintResourceVersion is optional. When set, the resourceVersion last observed on every
+	// resource, as recorded in ResourceStatus, is written to ErrOut once the command is done.
+	PrintResourceVersion bool
+	// PrintSummary is optional. When set, the value last observed on every resource is recorded
+	// in ResourceStatus, and a table of it, the condition expected and the outcome of the wait
+	// on every resource is written to Out once the command is done, as with -o wide.
+	PrintSummary bool
+	// ErrorFormat is optional and defaults to ErrorFormatText. It only says how the command
+	// reports a failed wait: Wait returns the same errors whatever it is.
+	ErrorFormat ErrorFormat
+	// MaxPolls is optional. When positive, the wait on a resource fails with a
+	// MaxPollsExceededError once its condition has been checked this many times without being
+	// met, counting every poll and every change seen on a watch, or once the resources have been
+	// counted this many times with Count. Timeout still applies, and whichever is reached first
+	// ends the wait, so that a test can bound a wait without depending on how fast it runs.
+	MaxPolls int
+	// Clock is optional and defaults to the real clock. It is used to measure the timeout
+	// and to wait between polls.
+	Clock clockwork.Clock
+
+	// checks counts the times a condition was checked against a resource, for Result.Polls.
+	// It is shared by the copies of the options made while waiting.
+	checks *int64
+	// observed holds the value last observed on the resource a ConditionFunc is called for,
+	// for ResourceStatus.Observed. It is only set with PrintSummary, so that the values are
+	// not computed on every change seen on a watch otherwise.
+	observed *atomic.Value
+	// conditionKinds are the kinds of the conditions given to ToOptions, as given to Metrics
+	conditionKinds string
+	// deadlineAt is when the wait on the resource a ConditionFunc is called for gives up, set by
+	// Wait for Deadline. It is the zero time if there is no deadline.
+	deadlineAt time.Time
+}
+
+// clock returns the Clock, defaulting to the real clock
+func (o *WaitOptions) clock() clockwork.Clock {
+	if o.Clock == nil {
+		return clockwork.NewRealClock()
+	}
+	return o.Clock
+}
+
+// deadline returns the time at which a wait started at startTime gives up, or the zero
+// time if Timeout is zero and the wait has no deadline
+func (o *WaitOptions) deadline(startTime time.Time) time.Time {
+	var deadline time.Time
+	if o.Timeout != 0 {
+		deadline = startTime.Add(o.Timeout)
+	}
+	if !o.Until.IsZero() && (deadline.IsZero() || o.Until.Before(deadline)) {
+		deadline = o.Until
+	}
+	return deadline
+}
+
+// checkedOnce returns a copy of the options which checks the condition once, for a wait
+// starting at startTime after Until has passed, or nil if Until is unset or still ahead
+func (o *WaitOptions) checkedOnce(startTime time.Time) *WaitOptions {
+	if o.Until.IsZero() || o.Until.After(startTime) {
+		return nil
+	}
+	once := *o
+	once.CheckNow = true
+	once.WaitForResources = false
+	once.StableFor, once.Settle, once.InitialDelay = 0, 0, 0
+	once.RequireTransition = TransitionModeNone
+	return &once
+}
+
+// excluded returns true if the resource is one of those to Exclude, and logs it at -v=2
+func (o *WaitOptions) excluded(info *resource.Info) bool {
+	for _, excluded := range o.Exclude {
+		if excluded.GroupResource != info.Mapping.Resource.GroupResource() || excluded.Name != info.Name {
+			continue
+		}
+		if len(excluded.Namespace) > 0 && excluded.Namespace != info.Namespace {
+			continue
+		}
+		klog.V(2).Infof("Not waiting on %s/%s, which is excluded", info.Mapping.Resource.Resource, info.Name)
+		return true
+	}
+	return false
+}
+
+// timeLeft returns the time left before endTime, or zero if endTime is the zero time and
+// there is no deadline. It returns false once endTime has been reached.
+func (o *WaitOptions) timeLeft(endTime time.Time) (time.Duration, bool) {
+	if endTime.IsZero() {
+		return 0, true
+	}
+	remaining := endTime.Sub(o.clock().Now())
+	return remaining, remaining > 0
+}
+
+// Deadline returns the time at which the wait on the resource a ConditionFunc is called for
+// gives up, for conditions which adapt to the time left, or false if Timeout is zero and there
+// is no deadline. Outside of a ConditionFunc, it is the deadline of a wait starting now.
+func (o *WaitOptions) Deadline() (time.Time, bool) {
+	deadline := o.deadlineAt
+	if deadline.IsZero() {
+		deadline = o.deadline(o.clock().Now())
+	}
+	return deadline, !deadline.IsZero()
+}
+
+// Remaining returns the time left before the Deadline, which is negative once it has passed,
+// or false if there is no deadline.
+func (o *WaitOptions) Remaining() (time.Duration, bool) {
+	deadline, ok := o.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return deadline.Sub(o.clock().Now()), true
+}
+
+// conditionFnFor returns the ConditionFunc to wait on the resource with
+func (o *WaitOptions) conditionFnFor(info *resource.Info) (ConditionFunc, error) {
+	if o.ConditionFnFor == nil {
+		return o.ConditionFn, nil
+	}
+	conditionFn, err := o.ConditionFnFor(info.Mapping)
+	if err != nil {
+		return nil, newConditionUnmetError(info, "%v", err)
+	}
+	if conditionFn == nil {
+		return o.ConditionFn, nil
+	}
+	return conditionFn, nil
+}
+
+// pollsExhausted returns true if the condition has been checked MaxPolls times on the resource
+// being waited on
+func (o *WaitOptions) pollsExhausted() bool {
+	return o.MaxPolls > 0 && o.checks != nil && atomic.LoadInt64(o.checks) >= int64(o.MaxPolls)
+}
+
+// polling returns true if resources are polled rather than watched
+func (o *WaitOptions) polling() bool {
+	return o.PollInterval > 0 || o.BackoffInitial > 0 || len(o.Subresource) > 0
+}
+
+// pollInterval returns the interval to wait after the given number of polls
+func (o *WaitOptions) pollInterval(polls int) time.Duration {
+	if o.BackoffInitial <= 0 && o.PollInterval <= 0 {
+		// only a Subresource is polled without an interval
+		return resourcesPollInterval + o.pollJitter()
+	}
+	if o.BackoffInitial <= 0 {
+		return o.PollInterval + o.pollJitter()
+	}
+	interval := o.BackoffInitial
+	for i := 1; i < polls; i++ {
+		if (o.BackoffMax > 0 && interval >= o.BackoffMax) || interval > math.MaxInt64/2 {
+			break
+		}
+		interval *= 2
+	}
+	if o.BackoffMax > 0 && interval > o.BackoffMax {
+		interval = o.BackoffMax
+	}
+	if o.BackoffJitter > 0 {
+		interval = wait.Jitter(interval, o.BackoffJitter)
+	}
+	return interval + o.pollJitter()
+}
+
+// jitterRand picks the PollJitter offsets. It is seeded once per process, so that processes
+// started at the same time pick different offsets.
+var jitterRand = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// pollJitter returns a random duration of less than PollJitter, or zero if it is not set
+func (o *WaitOptions) pollJitter() time.Duration {
+	if o.PollJitter <= 0 {
+		return 0
+	}
+	jitterRand.Lock()
+	defer jitterRand.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(o.PollJitter)))
+}
+
+// firstCheckDelay returns how long to wait before the first check: the InitialDelay, along with
+// a random offset of up to PollJitter unless the condition is only checked once
+func (o *WaitOptions) firstCheckDelay() time.Duration {
+	if o.CheckNow {
+		return o.InitialDelay
+	}
+	return o.InitialDelay + o.pollJitter()
+}
+
+// waitForNextPoll blocks until the next poll is due after the given number of polls. It
+// returns wait.ErrWaitTimeout without waiting if endTime has been reached, or the context's
+// error if ctx is done first. A zero endTime never times out.
+func (o *WaitOptions) waitForNextPoll(ctx context.Context, endTime time.Time, polls int) error {
+	remaining, ok := o.timeLeft(endTime)
+	if !ok {
+		return wait.ErrWaitTimeout
+	}
+	interval := o.pollInterval(polls)
+	if remaining > 0 && interval > remaining {
+		interval = remaining
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-o.clock().After(interval):
+		return nil
+	}
+}
+
+// ConditionFunc is the interface for providing condition checks. Implementations should stop
+// waiting and return the context's error once ctx is done.
+//
+// A ConditionFunc which finds that the resources being waited on are stale can return
+// ErrRelist. Once the resources waited on at the same time are done, the ResourceFinder is
+// asked for the resources again right away, and every resource found which has not met the
+// condition yet, including the one that returned ErrRelist, is waited on anew with what is
+// left of the Timeout. When watching, the watch on the resource is stopped when ErrRelist is
+// returned, and the resource is listed again before being watched anew, so no change is missed
+// in between. Returning ErrRelist past the Timeout fails the wait on the resource with a
+// TimeoutError, and with CheckNow, which does not wait, with a NotMetError.
+//
+// The Deadline and Remaining methods of o tell when the wait on the resource gives up, with what
+// is left of the Timeout of the whole wait.
+type ConditionFunc func(ctx context.Context, info *resource.Info, o *WaitOptions) (finalObject runtime.Object, done bool, err error)
+
+// RunWait runs the waiting logic
+func (o *WaitOptions) RunWait() error {
+	return o.RunWaitContext(context.Background())
+}
+
+// RunWaitContext runs the waiting logic until the condition is met, the timeout is reached
+// or ctx is done, whichever happens first
+func (o *WaitOptions) RunWaitContext(ctx context.Context) error {
+	_, err := o.Wait(ctx)
+	return err
+}
+
+// Result describes the outcome of a wait
+type Result struct {
+	// Matched is the number of resources the ResourceFinder visited
+	Matched int
+	// Satisfied holds the final state of every resource that met the condition, in the order
+	// they met it
+	Satisfied []runtime.Object
+	// Elapsed is the total time spent waiting
+	Elapsed time.Duration
+	// Polls is the number of times the condition was checked against a resource, whether
+	// listed, polled or seen on a watch. For a count condition it is the number of times the
+	// resources were looked up.
+	Polls int
+	// Resources holds the outcome of the wait on every resource, in the order they finished.
+	// It is empty for a count condition.
+	Resources []ResourceStatus
+}
+
+// ResourceStatus is the outcome of the wait on one resource
+type ResourceStatus struct {
+	// Resource is the resource type, e.g. "pods"
+	Resource string
+	// Namespace is the namespace of the resource, if it is namespaced
+	Namespace string
+	// Name is the name of the resource
+	Name string
+	// Met is true if the resource met the condition
+	Met bool
+	// Err is why the resource did not meet the condition, if it did not
+	Err error
+	// Polls is the number of times the condition was checked against the resource
+	Polls int
+	// Elapsed is how long the resource was waited on
+	Elapsed time.Duration
+	// ResourceVersion is the resourceVersion of the resource as last observed, if it was
+	// observed, which WaitOptions.ResourceVersion can resume a wait from
+	ResourceVersion string
+	// Observed is the value last observed for the condition on the resource, if the condition
+	// reports one. It is only recorded with WaitOptions.PrintSummary.
+	Observed string
+}
+
+// ErrorReport is written as JSON to stderr in place of the error message when a wait fails
+// with --error-format=json
+type ErrorReport struct {
+	// Outcome is the kind of failure of the wait as a whole
+	Outcome WaitOutcome `json:"outcome"`
+	// Error is the error message the wait failed with
+	Error string `json:"error"`
+	// Expected is the condition waited for, as given to --for
+	Expected string `json:"expected,omitempty"`
+	// Matched is the number of resources found
+	Matched int `json:"matched"`
+	// ElapsedSeconds is the total time spent waiting
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+	// Resources holds the failure of every resource which did not meet the condition
+	Resources []ResourceErrorReport `json:"resources,omitempty"`
+}
+
+// ResourceErrorReport is the failure of the wait on one resource, in an ErrorReport
+type ResourceErrorReport struct {
+	// Resource is the resource type and name, e.g. "pods/foo"
+	Resource string `json:"resource"`
+	// Namespace is the namespace of the resource, if it is namespaced
+	Namespace string `json:"namespace,omitempty"`
+	// Outcome is the kind of failure of the wait on the resource
+	Outcome WaitOutcome `json:"outcome"`
+	// Error is the error message the wait on the resource failed with
+	Error string `json:"error"`
+	// Observed is the last value observed for the condition, if the condition reports one
+	Observed string `json:"observed,omitempty"`
+	// ElapsedSeconds is how long the resource was waited on
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+	// ResourceVersion is the resourceVersion of the resource as last observed, if it was
+	// observed, to resume the wait from with --resource-version
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// errorReportFor returns the ErrorReport for a wait for expected which returned result and err
+func errorReportFor(result Result, err error, expected string) ErrorReport {
+	report := ErrorReport{
+		Outcome:        waitOutcomeFor(false, err),
+		Error:          err.Error(),
+		Expected:       expected,
+		Matched:        result.Matched,
+		ElapsedSeconds: result.Elapsed.Seconds(),
+	}
+	for _, status := range result.Resources {
+		if status.Met || status.Err == nil {
+			continue
+		}
+		report.Resources = append(report.Resources, ResourceErrorReport{
+			Resource:       status.Resource + "/" + status.Name,
+			Namespace:      status.Namespace,
+			Outcome:        waitOutcomeFor(false, status.Err),
+			Error:          status.Err.Error(),
+			Observed:       observedFor(status.Err),
+			ElapsedSeconds: status.Elapsed.Seconds(),
+
+			ResourceVersion: status.ResourceVersion,
+		})
+	}
+	return report
+}
+
+// reportFailure returns the error the command fails with for a wait which returned result and
+// err, carrying the exit code for it. With ErrorFormatJSON the ErrorReport is written to ErrOut
+// right away, and the error returned has no message of its own.
+func (o *WaitOptions) reportFailure(result Result, err error) error {
+	if err == nil || o.ErrorFormat != ErrorFormatJSON {
+		return exitErrorFor(err)
+	}
+	if encodeErr := json.NewEncoder(o.ErrOut).Encode(errorReportFor(result, err, o.ForCondition)); encodeErr != nil {
+		return exitErrorFor(err)
+	}
+	code := exitCodeFor(err)
+	if code == 0 {
+		code = cmdutil.DefaultErrorExitCode
+	}
+	return utilexec.CodeExitError{Err: errors.New(""), Code: code}
+}
+
+// Wait runs the waiting logic against an already populated WaitOptions until the condition
+// is met, the timeout is reached or ctx is done, whichever happens first. It does not depend on
+// cobra and can be used by other programs. Every resource that meets the condition is passed to
+// the Printer, if one is set, as soon as it is satisfied. The returned Result is populated even
+// when an error is returned.
+func (o *WaitOptions) Wait(ctx context.Context) (result Result, err error) {
+	startTime := o.clock().Now()
+	if o.CompletionHook != nil {
+		defer func() {
+			if hookErr := o.CompletionHook(result, err); hookErr != nil {
+				fmt.Fprintf(o.ErrOut, "warning: %v\n", hookErr)
+			}
+		}()
+	}
+	if once := o.checkedOnce(startTime); once != nil {
+		until := o.Until
+		defer func() {
+			if exitCodeFor(err) == ExitCodeNotMet {
+				err = &DeadlinePassedError{Deadline: until, Err: err}
+			}
+		}()
+		o = once
+	}
+
+	isForDelete := strings.ToLower(o.ForCondition) == "delete"
+	ignoreErrorFns := o.IgnoreErrorFns
+	if isForDelete {
+		ignoreErrorFns = append([]resource.ErrMatchFunc{apierrors.IsNotFound}, ignoreErrorFns...)
+	}
+
+	firstCheckDelay := o.firstCheckDelay()
+	if firstCheckDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-o.clock().After(firstCheckDelay):
+		}
+	}
+
+	if aggregate := o.aggregate(); aggregate != nil {
+		return o.waitForAggregate(ctx, startTime, ignoreErrorFns, aggregate)
+	}
+
+	var checks int64
+	counted := *o
+	counted.checks = &checks
+	o = &counted
+	defer func() { result.Polls = int(atomic.LoadInt64(&checks)) }()
+
+	anyMode := o.Mode == WaitModeAny
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	baseOptions := o
+	parallel := o.Concurrency > 1 || anyMode
+	if parallel {
+		// resources are waited on concurrently, so writes to the streams are serialized
+		parallelOptions := *o
+		parallelOptions.ErrOut = &syncWriter{w: o.ErrOut}
+		if o.ProgressWriter != nil {
+			parallelOptions.ProgressWriter = &syncWriter{w: o.ProgressWriter}
+		}
+		baseOptions = &parallelOptions
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		workers chan struct{}
+		// seen and met are the resources found so far and those that met the condition, so
+		// the resources looked up again after ErrRelist are counted once and not waited on twice
+		seen = map[ResourceLocation]bool{}
+		met  = map[ResourceLocation]bool{}
+		// relisting are the resources whose ConditionFunc returned ErrRelist
+		relisting []*resource.Info
+	)
+	if o.Concurrency > 1 && !anyMode {
+		// in any mode every resource is waited on at once, since any of them may be the one
+		workers = make(chan struct{}, o.Concurrency)
+	}
+	// waitForResource waits on one resource and records its outcome. A resource failing does
+	// not stop the wait on the others, so that every failure can be reported.
+	waitForResource := func(info *resource.Info, options *WaitOptions) {
+		// the checks of each resource are counted on their own for its ResourceStatus
+		var resourceChecks int64
+		resourceOptions := *options
+		resourceOptions.checks = &resourceChecks
+		if o.PrintSummary {
+			resourceOptions.observed = &atomic.Value{}
+		}
+		started := o.clock().Now()
+		resourceOptions.deadlineAt = options.deadline(started)
+		if !o.Until.IsZero() && !o.CheckNow {
+			// the wait on every resource is over by Until, however long the Timeout
+			resourceOptions.Timeout = resourceOptions.deadlineAt.Sub(started)
+			if resourceOptions.Timeout <= 0 {
+				resourceOptions.Timeout = time.Nanosecond
+			}
+		}
+		if o.Metrics != nil {
+			o.Metrics.WaitStarted(o.metricsCondition())
+		}
+		finalObject, success := info.Object, false
+		conditionFn, err := o.conditionFnFor(info)
+		if err == nil {
+			finalObject, success, err = conditionFn(ctx, info, &resourceOptions)
+		}
+		elapsed := o.clock().Since(started)
+		atomic.AddInt64(&checks, atomic.LoadInt64(&resourceChecks))
+		mu.Lock()
+		defer mu.Unlock()
+		if o.OnCheck != nil {
+			o.OnCheck(info, finalObject, success, err)
+		}
+		klog.V(4).Infof("Finished waiting for %s on %s/%s: met: %t, err: %v", o.ForCondition, info.Mapping.Resource.Resource, info.Name, success, err)
+		if !success && errors.Is(err, ErrRelist) {
+			if !o.CheckNow {
+				relisting = append(relisting, info)
+				if o.Metrics != nil {
+					o.Metrics.WaitFinished(o.metricsCondition(), WaitOutcomeRelist, int(atomic.LoadInt64(&resourceChecks)), elapsed)
+				}
+				return
+			}
+			err = &NotMetError{Resource: info.Mapping.Resource.Resource, Name: info.Name, Detail: "the resources have to be looked up again"}
+		}
+		if !success && err == nil {
+			err = newConditionUnmetError(info, "")
+		}
+		if success {
+			err = nil
+		}
+		if o.Metrics != nil {
+			o.Metrics.WaitFinished(o.metricsCondition(), waitOutcomeFor(success, err), int(atomic.LoadInt64(&resourceChecks)), elapsed)
+		}
+		status := ResourceStatus{
+			Resource:  info.Mapping.Resource.Resource,
+			Namespace: info.Namespace,
+			Name:      info.Name,
+			Met:       success,
+			Err:       err,
+			Polls:     int(atomic.LoadInt64(&resourceChecks)),
+			Elapsed:   elapsed,
+
+			ResourceVersion: resourceVersionOf(finalObject),
+		}
+		if resourceOptions.observed != nil {
+			status.Observed, _ = resourceOptions.observed.Load().(string)
+		}
+		result.Resources = append(result.Resources, status)
+		if !success {
+			errs = append(errs, err)
+			return
+		}
+		if anyMode && len(result.Satisfied) > 0 {
+			// another resource got there first
+			return
+		}
+		if finalObject == nil {
+			// conditions which do not return the object met it as it was found
+			finalObject = info.Object
+		}
+		met[resourceLocationFor(info)] = true
+		result.Satisfied = append(result.Satisfied, finalObject)
+		o.printSatisfied(finalObject, &status)
+		if anyMode {
+			// one is enough, stop waiting on the others
+			cancel()
+		}
+	}
+
+	endTime := o.deadline(startTime)
+	// optionsLeft returns the options for resources checked after the wait started, which
+	// have what is left of the timeout. Once it is reached they are still checked once,
+	// since a zero Timeout would not time out.
+	optionsLeft := func() *WaitOptions {
+		if endTime.IsZero() {
+			return baseOptions
+		}
+		remainingOptions := *baseOptions
+		remainingOptions.Timeout = endTime.Sub(o.clock().Now())
+		if remainingOptions.Timeout <= 0 {
+			remainingOptions.Timeout = time.Nanosecond
+		}
+		return &remainingOptions
+	}
+	conditionOptions := baseOptions
+	if firstCheckDelay > 0 {
+		conditionOptions = optionsLeft()
+	}
+	visitFunc := func(info *resource.Info, err error) error {
+		if err != nil {
+			if isIgnoredError(err, ignoreErrorFns) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if o.excluded(info) {
+			return nil
+		}
+
+		location := resourceLocationFor(info)
+		mu.Lock()
+		if met[location] {
+			mu.Unlock()
+			return nil
+		}
+		if !seen[location] {
+			seen[location] = true
+			result.Matched++
+		}
+		mu.Unlock()
+		klog.V(4).Infof("Waiting for %s on %s/%s with a timeout of %v", o.ForCondition, info.Mapping.Resource.Resource, info.Name, conditionOptions.Timeout)
+		if !parallel {
+			waitForResource(info, conditionOptions)
+			return nil
+		}
+
+		if workers != nil {
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		options := conditionOptions
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if workers != nil {
+				defer func() { <-workers }()
+			}
+			waitForResource(info, options)
+		}()
+		return nil
+	}
+	for {
+		visitor := o.ResourceFinder.Do()
+		if visitor, ok := visitor.(*resource.Result); ok && len(ignoreErrorFns) > 0 {
+			visitor.IgnoreErrors(ignoreErrorFns...)
+		}
+
+		err := visitor.Visit(visitFunc)
+		wg.Wait()
+		switch {
+		case anyMode && len(result.Satisfied) > 0:
+			// the others were stopped once one resource met the condition
+			err = nil
+		case len(errs) > 0 && (err == nil || ctx.Err() != nil):
+			// an interrupted wait is already reported by the resources it interrupted
+			err = aggregateErrors(errs)
+		case len(errs) > 0:
+			err = aggregateErrors(append(errs, err))
+		}
+		result.Elapsed = o.clock().Since(startTime)
+		if err != nil {
+			return result, err
+		}
+		if len(relisting) > 0 && !(anyMode && len(result.Satisfied) > 0) {
+			if _, ok := o.timeLeft(endTime); !ok {
+				for _, info := range relisting {
+					err := extendErrWaitTimeout(wait.ErrWaitTimeout, info)
+					result.Resources = append(result.Resources, ResourceStatus{
+						Resource:  info.Mapping.Resource.Resource,
+						Namespace: info.Namespace,
+						Name:      info.Name,
+						Err:       err,
+					})
+					errs = append(errs, err)
+				}
+				return result, aggregateErrors(errs)
+			}
+			klog.V(4).Infof("Looking up the resources again, as asked by the condition on %s/%s", relisting[0].Mapping.Resource.Resource, relisting[0].Name)
+			relisting = nil
+			conditionOptions = optionsLeft()
+			continue
+		}
+		if result.Matched > 0 || isForDelete {
+			return result, nil
+		}
+		if !o.WaitForResources || o.CheckNow {
+			return result, errNoMatchingResources
+		}
+
+		interval := o.PollInterval
+		if interval <= 0 {
+			interval = resourcesPollInterval
+		}
+		remaining, ok := o.timeLeft(endTime)
+		if !ok {
+			return result, fmt.Errorf("%w: %v", errNoMatchingResources, wait.ErrWaitTimeout)
+		}
+		if remaining > 0 && interval > remaining {
+			interval = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-o.clock().After(interval):
+		}
+		conditionOptions = optionsLeft()
+	}
+}
+
+// aggregateErrors returns the error of the only resource that failed, or an aggregate
+// of the errors of all of them if several did
+func aggregateErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// printSatisfied prints a resource which met the condition to Out, with PrinterFor if it is
+// set and the status is known, and otherwise with the Printer, if any. Nothing is printed when
+// Quiet is set. Since the resource did meet the condition, failing to print it is only a
+// warning.
+func (o *WaitOptions) printSatisfied(obj runtime.Object, status *ResourceStatus) {
+	var err error
+	switch {
+	case o.Quiet || obj == nil:
+	case o.PrinterFor != nil && status != nil:
+		err = o.PrinterFor(*status).PrintObj(obj, o.Out)
+	case o.Printer != nil:
+		err = o.Printer.PrintObj(obj, o.Out)
+	}
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "warning: unable to print a resource which met the condition: %v\n", err)
+	}
+}
+
+// printResourceVersions writes the resourceVersion last observed on every resource of the
+// result to ErrOut, as in "resourceVersion of pods/foo: 1234", leaving out the resources which
+// were never observed
+func (o *WaitOptions) printResourceVersions(result Result) {
+	for _, status := range result.Resources {
+		if len(status.ResourceVersion) > 0 {
+			fmt.Fprintf(o.ErrOut, "resourceVersion of %s/%s: %s\n", status.Resource, status.Name, status.ResourceVersion)
+		}
+	}
+}
+
+// printSummary writes a table of the value last observed on every resource of the result, the
+// condition it was expected to meet and the outcome of the wait on it to Out
+func (o *WaitOptions) printSummary(result Result) {
+	w := printers.GetNewTabWriter(o.Out)
+	defer w.Flush()
+	fmt.Fprintln(w, "NAME\tOBSERVED\tEXPECTED\tRESULT")
+	for _, status := range result.Resources {
+		observed := status.Observed
+		if len(observed) == 0 {
+			observed = observedFor(status.Err)
+		}
+		if len(observed) == 0 {
+			observed = "<none>"
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\n", status.Resource, status.Name, observed, o.ForCondition, waitOutcomeFor(status.Met, status.Err))
+	}
+}
+
+// AggregateCondition is checked against all the resources found at once rather than against each of
+// them, for conditions which no resource can meet on its own, such as a number of resources or
+// values spread across them. See WaitOptions.Aggregate.
+type AggregateCondition interface {
+	// IsMet returns true if the resources found meet the condition, along with what was found on
+	// them, such as "found 2 resources", for reporting
+	IsMet(found []runtime.Object) (bool, string, error)
+	// String formats the condition the way it is given to --for
+	String() string
+}
+
+// aggregate returns the AggregateCondition to wait for, or nil if the wait is for a condition on
+// each resource
+func (o *WaitOptions) aggregate() AggregateCondition {
+	switch {
+	case o.Aggregate != nil:
+		return o.Aggregate
+	case o.Count != nil:
+		return o.Count
+	}
+	return nil
+}
+
+// waitForAggregate looks up the resources until they meet aggregate
+func (o *WaitOptions) waitForAggregate(ctx context.Context, startTime time.Time, ignoreErrorFns []resource.ErrMatchFunc, aggregate AggregateCondition) (result Result, err error) {
+	if o.Metrics != nil {
+		o.Metrics.WaitStarted(o.metricsCondition())
+		defer func() {
+			o.Metrics.WaitFinished(o.metricsCondition(), waitOutcomeFor(err == nil, err), result.Polls, result.Elapsed)
+		}()
+	}
+	counting := *o
+	if counting.PollInterval <= 0 && counting.BackoffInitial <= 0 {
+		counting.PollInterval = resourcesPollInterval
+	}
+	endTime := o.deadline(startTime)
+	for polls := 0; ; polls++ {
+		var found []runtime.Object
+		visitor := o.ResourceFinder.Do()
+		if visitor, ok := visitor.(*resource.Result); ok && len(ignoreErrorFns) > 0 {
+			visitor.IgnoreErrors(ignoreErrorFns...)
+		}
+		err := visitor.Visit(func(info *resource.Info, err error) error {
+			if err != nil {
+				if isIgnoredError(err, ignoreErrorFns) {
+					return nil
+				}
+				return err
+			}
+			if !o.excluded(info) {
+				found = append(found, info.Object)
+			}
+			return nil
+		})
+		result.Elapsed = o.clock().Since(startTime)
+		result.Polls = polls + 1
+		if err != nil {
+			return result, err
+		}
+		result.Matched = len(found)
+		met, observed, err := aggregate.IsMet(found)
+		if err != nil {
+			return result, err
+		}
+		if met {
+			result.Satisfied = found
+			for _, obj := range found {
+				o.printSatisfied(obj, nil)
+			}
+			return result, nil
+		}
+		if o.CheckNow {
+			return result, &NotMetError{Detail: fmt.Sprintf("%s, expected %s", observed, aggregate)}
+		}
+		if o.MaxPolls > 0 && result.Polls >= o.MaxPolls {
+			return result, &MaxPollsExceededError{Polls: result.Polls, Detail: fmt.Sprintf("%s, expected %s", observed, aggregate)}
+		}
+
+		if err := counting.waitForNextPoll(ctx, endTime, polls+1); err != nil {
+			if errors.Is(err, wait.ErrWaitTimeout) {
+				return result, fmt.Errorf("%w: %s, expected %s", err, observed, aggregate)
+			}
+			return result, err
+		}
+	}
+}
+
+// CountWait waits for the number of resources found to compare with a count
+type CountWait struct {
+	operator string
+	count    int
+}
+
+func (w *CountWait) isMet(found int) bool {
+	met, _ := compareNumbers(strconv.Itoa(found), w.operator, strconv.Itoa(w.count))
+	return met
+}
+
+// IsMet returns true if the number of resources found compares with the count
+func (w *CountWait) IsMet(found []runtime.Object) (bool, string, error) {
+	return w.isMet(len(found)), fmt.Sprintf("found %d resources", len(found)), nil
+}
+
+func (w *CountWait) String() string {
+	return fmt.Sprintf("count%s%d", w.operator, w.count)
+}
+
+// coversWaitFor parses a jsonpath covers condition such as
+// jsonpath={.metadata.labels.shard}covers=0,1,2
+func coversWaitFor(condition string, ignoreCase bool) (*CoversWait, error) {
+	spec, err := parseCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+	jsonPathExp, jsonPathCond, err := processJSONPathInput(spec.jsonPathExpression, spec.jsonPathOperator, spec.jsonPathCondition)
+	if err != nil {
+		return nil, err
+	}
+	j, err := newJSONPathParser(jsonPathExp)
+	if err != nil {
+		return nil, err
+	}
+	w := &CoversWait{
+		condition:          spec.String(),
+		jsonPathExpression: jsonPathExp,
+		jsonPathParser:     j,
+		ignoreCase:         ignoreCase,
+	}
+	for _, value := range strings.Split(jsonPathCond, ",") {
+		w.expectedValues = append(w.expectedValues, strings.TrimSpace(value))
+	}
+	return w, nil
+}
+
+// CoversWait waits for the values a JSONPath expression resolves to on the resources found, all
+// taken together, to include every one of a set of values, whichever resources they are found
+// on. Values found beyond those do not prevent the condition from being met.
+type CoversWait struct {
+	// condition is the condition as given to --for, for reporting
+	condition string
+	// jsonPathExpression is the expression parsed by jsonPathParser
+	jsonPathExpression string
+	jsonPathParser     *jsonpath.JSONPath
+	expectedValues     []string
+	// ignoreCase compares values with strings.EqualFold
+	ignoreCase bool
+}
+
+// IsMet returns true if every expected value is among those the JSONPath expression resolves to
+// on the resources found
+func (w *CoversWait) IsMet(found []runtime.Object) (bool, string, error) {
+	observed := sets.NewString()
+	for _, obj := range found {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return false, "", err
+		}
+		parseResults, err := findResults(w.jsonPathParser, content)
+		if err != nil {
+			return false, "", err
+		}
+		for _, result := range parseResults {
+			for _, r := range result {
+				if r.IsValid() && r.CanInterface() {
+					observed.Insert(fmt.Sprintf("%v", r.Interface()))
+				}
+			}
+		}
+	}
+	description := fmt.Sprintf("observed %s on %d resources", strings.Join(observed.List(), ","), len(found))
+	if observed.Len() == 0 {
+		description = fmt.Sprintf("observed no values on %d resources", len(found))
+	}
+	for _, expected := range w.expectedValues {
+		if !w.observed(observed, expected) {
+			return false, description, nil
+		}
+	}
+	return true, description, nil
+}
+
+// observed returns true if expected is one of the observed values
+func (w *CoversWait) observed(observed sets.String, expected string) bool {
+	if !w.ignoreCase {
+		return observed.Has(expected)
+	}
+	for value := range observed {
+		if strings.EqualFold(value, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *CoversWait) String() string {
+	return w.condition
+}
+
+// WaitMode says how many of the matched resources have to meet the condition
+type WaitMode string
+
+const (
+	// WaitModeAll waits for every matched resource to meet the condition
+	WaitModeAll WaitMode = "all"
+	// WaitModeAny waits on every matched resource at once and succeeds as soon as one meets
+	// the condition, which is then the only entry in Result.Satisfied
+	WaitModeAny WaitMode = "any"
+)
+
+// ErrorFormat is how the command reports a failed wait on stderr
+type ErrorFormat string
+
+const (
+	// ErrorFormatText reports the error message, as other commands do
+	ErrorFormatText ErrorFormat = "text"
+	// ErrorFormatJSON reports an ErrorReport as a single JSON object, for automation
+	ErrorFormatJSON ErrorFormat = "json"
+)
+
+// TransitionMode says whether a condition has to be seen unmet before it counts as met
+type TransitionMode string
+
+const (
+	// TransitionModeNone counts a condition as met whenever it is met, also when it already is
+	// at the first check
+	TransitionModeNone TransitionMode = "none"
+	// TransitionModeWait only counts a condition as met once it has been seen unmet first,
+	// and keeps waiting if it is already met at the first check
+	TransitionModeWait TransitionMode = "wait"
+	// TransitionModeFail only counts a condition as met once it has been seen unmet first,
+	// and fails with a ConditionUnmetError if it is already met at the first check
+	TransitionModeFail TransitionMode = "fail"
+)
+
+// transientRetryInterval is the interval before the first retry after a transient error. It
+// doubles with every consecutive error, up to maxTransientRetryInterval.
+const (
+	transientRetryInterval    = time.Second
+	maxTransientRetryInterval = 30 * time.Second
+)
+
+// isTransientError returns true if err is likely to go away when the request is retried
+func isTransientError(err error) bool {
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsProbableEOF(err) ||
+		utilnet.IsTimeout(err)
+}
+
+// retryTransient returns true if err is transient and there are retries left, once it has
+// waited for the retry to be due. failures counts the consecutive transient errors, and is
+// reset by the caller when a request succeeds.
+func (o *WaitOptions) retryTransient(ctx context.Context, endTime time.Time, failures *int, err error) bool {
+	if o.MaxTransientRetries <= 0 || *failures >= o.MaxTransientRetries || !isTransientError(err) {
+		return false
+	}
+	remaining, ok := o.timeLeft(endTime)
+	if !ok {
+		return false
+	}
+	*failures++
+	interval := transientRetryInterval
+	for i := 1; i < *failures && interval < maxTransientRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxTransientRetryInterval {
+		interval = maxTransientRetryInterval
+	}
+	if remaining > 0 && interval > remaining {
+		interval = remaining
+	}
+	klog.V(2).Infof("Retrying in %v after a transient error (%d/%d): %v", interval, *failures, o.MaxTransientRetries, err)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-o.clock().After(interval):
+		return true
+	}
+}
+
+// syncWriter serializes writes to w from resources waited on concurrently
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// isIgnoredError returns true if err matches any of the ignoreErrorFns
+func isIgnoredError(err error, ignoreErrorFns []resource.ErrMatchFunc) bool {
+	for _, fn := range ignoreErrorFns {
+		if fn(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// initialResourceVersion returns the resourceVersion to start watching a resource from rather
+// than listing it, if any
+func (o *WaitOptions) initialResourceVersion() string {
+	if o.CheckNow || o.polling() {
+		return ""
+	}
+	return o.ResourceVersion
+}
+
+// resourceVersionOf returns the resourceVersion of obj, or an empty string if it has none.
+// Conditions may return a nil object of a pointer type, such as a nil *unstructured.Unstructured.
+func resourceVersionOf(obj runtime.Object) string {
+	if obj == nil {
+		return ""
+	}
+	if value := reflect.ValueOf(obj); value.Kind() == reflect.Ptr && value.IsNil() {
+		return ""
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetResourceVersion()
+}
+
+// IsDeleted is a condition func for waiting for something to be deleted
+func IsDeleted(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	startTime := o.clock().Now()
+	endTime := o.deadline(startTime)
+	polls := 0
+	transientFailures := 0
+	// resumeVersion is the resourceVersion to watch from again when the server closed the
+	// last watch, instead of listing the resource
+	resumeVersion := o.initialResourceVersion()
+	var gottenObj *unstructured.Unstructured
+	for {
+		if len(info.Name) == 0 {
+			return info.Object, false, fmt.Errorf("resource name must be provided")
+		}
+
+		nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+
+		resourceVersion, resumed := resumeVersion, len(resumeVersion) > 0
+		resumeVersion = ""
+		if !resumed {
+			// List with a name field selector to get the current resourceVersion to watch from (not the object's resourceVersion)
+			gottenObjList, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).List(ctx, metav1.ListOptions{FieldSelector: nameSelector})
+			if apierrors.IsNotFound(err) {
+				o.recordProgress(info, startTime, "", true)
+				return info.Object, true, nil
+			}
+			if err != nil && o.retryTransient(ctx, endTime, &transientFailures, err) {
+				continue
+			}
+			if err != nil {
+				// TODO this could do something slightly fancier if we wish
+				return info.Object, false, err
+			}
+			if len(gottenObjList.Items) != 1 {
+				o.recordProgress(info, startTime, "", true)
+				return info.Object, true, nil
+			}
+			gottenObj = &gottenObjList.Items[0]
+			resourceLocation := ResourceLocation{
+				GroupResource: info.Mapping.Resource.GroupResource(),
+				Namespace:     gottenObj.GetNamespace(),
+				Name:          gottenObj.GetName(),
+			}
+			if uid, ok := o.UIDMap[resourceLocation]; ok {
+				if gottenObj.GetUID() != uid {
+					o.recordProgress(info, startTime, "", true)
+					return gottenObj, true, nil
+				}
+			}
+			o.recordProgress(info, startTime, observedDeletion(gottenObj), false)
+
+			if o.CheckNow {
+				return gottenObj, false, notMetErrorFor(info, gottenObj, observedDeletion, describeDeletion)
+			}
+			if o.pollsExhausted() {
+				return gottenObj, false, o.maxPollsErrorFor(info, gottenObj, observedDeletion, describeDeletion)
+			}
+			if o.polling() {
+				polls++
+				if err := o.waitForNextPoll(ctx, endTime, polls); err == wait.ErrWaitTimeout {
+					return gottenObj, false, timeoutErrorFor(info, gottenObj, observedDeletion, describeDeletion)
+				} else if err != nil {
+					return gottenObj, false, cancelledErrorFor(err, info, gottenObj, observedDeletion, describeDeletion)
+				}
+				continue
+			}
+			resourceVersion = gottenObjList.GetResourceVersion()
+		}
+
+		watchOptions := metav1.ListOptions{}
+		watchOptions.FieldSelector = nameSelector
+		watchOptions.ResourceVersion = resourceVersion
+		watchOptions.AllowWatchBookmarks = true
+		objWatch, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Watch(ctx, watchOptions)
+		if apierrors.IsNotFound(err) {
+			// the resource type or namespace has been deleted as well
+			o.recordProgress(info, startTime, "", true)
+			return objectOrInfo(info, gottenObj), true, nil
+		}
+		if err != nil && resumed && isWatchExpired(err) {
+			// the resourceVersion the last watch ended at is too old by now, list again
+			continue
+		}
+		if err != nil && o.retryTransient(ctx, endTime, &transientFailures, err) {
+			continue
+		}
+		if err != nil {
+			return objectOrInfo(info, gottenObj), false, err
+		}
+		transientFailures = 0
+
+		timeout, ok := o.timeLeft(endTime)
+		if !ok {
+			// we're out of time
+			return objectOrInfo(info, gottenObj), false, timeoutErrorFor(info, gottenObj, observedDeletion, describeDeletion)
+		}
+
+		versions := &resourceVersionTracker{}
+		watchCtx, cancel := watchtools.ContextWithOptionalTimeout(ctx, timeout)
+		watchEvent, err := watchtools.UntilWithoutRetry(watchCtx, objWatch, watchtools.ConditionFunc(versions.watching(o.recordingProgress(info, startTime, nil, Wait{errOut: o.ErrOut}.IsDeleted))))
+		cancel()
+		switch {
+		case err == nil:
+			return watchEvent.Object, true, nil
+		case err == watchtools.ErrWatchClosed:
+			// carry on from the last event seen, or list again if there was none
+			resumeVersion = versions.version
+			continue
+		case err == errWatchExpired:
+			continue
+		case err == errMaxPollsExceeded:
+			lastObj, _ := watchEvent.Object.(*unstructured.Unstructured)
+			return watchEvent.Object, false, o.maxPollsErrorFor(info, lastObj, observedDeletion, describeDeletion)
+		case err == wait.ErrWaitTimeout && ctx.Err() != nil:
+			if watchEvent != nil {
+				lastObj, _ := watchEvent.Object.(*unstructured.Unstructured)
+				return watchEvent.Object, false, cancelledErrorFor(ctx.Err(), info, lastObj, observedDeletion, describeDeletion)
+			}
+			return objectOrInfo(info, gottenObj), false, cancelledErrorFor(ctx.Err(), info, gottenObj, observedDeletion, describeDeletion)
+		case err == wait.ErrWaitTimeout:
+			if watchEvent != nil {
+				lastObj, _ := watchEvent.Object.(*unstructured.Unstructured)
+				return watchEvent.Object, false, timeoutErrorFor(info, lastObj, observedDeletion, describeDeletion)
+			}
+			return objectOrInfo(info, gottenObj), false, timeoutErrorFor(info, gottenObj, observedDeletion, describeDeletion)
+		default:
+			return objectOrInfo(info, gottenObj), false, err
+		}
+	}
+}
+
+// objectOrInfo returns obj, or the object of info when nothing was fetched yet, as when the wait
+// resumed from a resourceVersion, so that a nil obj is never returned as a non-nil runtime.Object
+func objectOrInfo(info *resource.Info, obj *unstructured.Unstructured) runtime.Object {
+	if obj == nil {
+		return info.Object
+	}
+	return obj
+}
+
+// observedDeletion returns whether the object is still present or is being deleted
+func observedDeletion(obj *unstructured.Unstructured) string {
+	if obj.GetDeletionTimestamp() == nil {
+		return "present"
+	}
+	if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+		return fmt.Sprintf("terminating with finalizers %s", strings.Join(finalizers, ","))
+	}
+	return "terminating"
+}
+
+// describeDeletion explains that the object is waited on to be deleted
+func describeDeletion(observed string) string {
+	return fmt.Sprintf("deletion (last observed: %s)", observed)
+}
+
+// PersistsWait checks that a resource stays present for the whole Timeout, the inverse of
+// IsDeleted: the resource surviving until the timeout meets the condition, and its deletion
+// fails the wait right away. The resource is polled, every PollInterval or every second, and
+// its uid is compared across polls, so that it being deleted and recreated in between is
+// caught as well.
+type PersistsWait struct {
+	// companion is optional. When set, it is checked once on every poll, as with CheckNow, and
+	// a poll on which it is not met fails the wait.
+	companion ConditionFunc
+	// companionCondition is the condition companion checks, for reporting
+	companionCondition string
+}
+
+// IsPersisting is a conditionfunc for checking that a resource stays present, and keeps meeting
+// the companion condition if there is one, until the timeout. It returns a ConditionUnmetError
+// as soon as the resource is found deleted, being deleted or recreated, or not meeting the
+// companion condition.
+func (w PersistsWait) IsPersisting(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if len(info.Name) == 0 {
+		return info.Object, false, fmt.Errorf("resource name must be provided")
+	}
+	startTime := o.clock().Now()
+	endTime := o.deadline(startTime)
+	if endTime.IsZero() {
+		return info.Object, false, fmt.Errorf("persists requires a timeout, the window the resource has to stay present for")
+	}
+	nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+	polls := 0
+	transientFailures := 0
+	var gottenObj *unstructured.Unstructured
+	for {
+		gottenObjList, err := o.listObject(ctx, info, nameSelector)
+		if err != nil && o.retryTransient(ctx, endTime, &transientFailures, err) {
+			continue
+		}
+		if err != nil {
+			return info.Object, false, err
+		}
+		transientFailures = 0
+		elapsed := o.clock().Since(startTime).Round(time.Millisecond)
+		if len(gottenObjList.Items) != 1 {
+			o.recordProgress(info, startTime, "deleted", false)
+			return info.Object, false, newConditionUnmetError(info, "the resource was deleted %v into the window of %v", elapsed, endTime.Sub(startTime))
+		}
+		obj := &gottenObjList.Items[0]
+		if gottenObj != nil && obj.GetUID() != gottenObj.GetUID() {
+			o.recordProgress(info, startTime, "recreated", false)
+			return obj, false, newConditionUnmetError(info, "the resource was deleted and recreated with uid %s %v into the window of %v", obj.GetUID(), elapsed, endTime.Sub(startTime))
+		}
+		gottenObj = obj
+		if obj.GetDeletionTimestamp() != nil {
+			o.recordProgress(info, startTime, observedDeletion(obj), false)
+			return obj, false, newConditionUnmetError(info, "the resource is being deleted %v into the window of %v", elapsed, endTime.Sub(startTime))
+		}
+		if w.companion != nil {
+			companionOptions := *o
+			companionOptions.CheckNow = true
+			// the companion is checked as part of the poll, which counts once
+			companionOptions.checks = nil
+			if _, met, err := w.companion(ctx, info, &companionOptions); !met {
+				if err == nil {
+					err = newConditionUnmetError(info, "")
+				}
+				o.recordProgress(info, startTime, observedDeletion(obj), false)
+				return obj, false, newConditionUnmetError(info, "%s was not met %v into the window of %v: %v", w.companionCondition, elapsed, endTime.Sub(startTime), err)
+			}
+		}
+		o.recordProgress(info, startTime, observedDeletion(obj), false)
+		polls++
+		if err := o.waitForNextPoll(ctx, endTime, polls); err == wait.ErrWaitTimeout {
+			o.recordProgress(info, startTime, observedDeletion(obj), true)
+			return obj, true, nil
+		} else if err != nil {
+			return obj, false, cancelledErrorFor(err, info, obj, observedDeletion, w.describe)
+		}
+	}
+}
+
+// describe explains that the object is checked to stay present
+func (w PersistsWait) describe(observed string) string {
+	if w.companion != nil {
+		return fmt.Sprintf("resource (last observed: %s) to stay present and meet %s until the timeout", observed, w.companionCondition)
+	}
+	return fmt.Sprintf("resource (last observed: %s) to stay present until the timeout", observed)
+}
+
+// persistsFuncFor returns the ConditionFunc of a persists condition, whose companion is every
+// other condition
+func persistsFuncFor(conditions []string, ignoreCase bool) (ConditionFunc, error) {
+	var companions []string
+	for _, condition := range conditions {
+		switch conditionKind(strings.ToLower(condition)) {
+		case conditionKindPersists:
+			continue
+		case conditionKindDelete, conditionKindCreate:
+			return nil, fmt.Errorf("a persists condition cannot be combined with a %s condition", strings.ToLower(condition))
+		}
+		companions = append(companions, condition)
+	}
+	w := PersistsWait{companionCondition: strings.Join(companions, ",")}
+	var err error
+	switch len(companions) {
+	case 0:
+	case 1:
+		w.companion, err = conditionFuncFor(companions[0], ignoreCase)
+	default:
+		w.companion, err = allConditionsFuncFor(companions, ignoreCase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w.IsPersisting, nil
+}
+
+// NewNamespaceDeletionWaiter returns a ConditionFunc which waits for a namespace to be deleted,
+// as IsDeleted does. When the namespace is still there once the wait is over, the error also
+// says what its termination is blocked on: the finalizers left in its spec, and the conditions
+// the namespace controller reports on the resources it could not delete yet.
+func NewNamespaceDeletionWaiter() ConditionFunc {
+	return func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+		obj, done, err := IsDeleted(ctx, info, o)
+		if done || err == nil {
+			return obj, done, err
+		}
+		if code := exitCodeFor(err); code != ExitCodeTimeout && code != ExitCodeNotMet {
+			return obj, done, err
+		}
+		namespace, ok := obj.(*unstructured.Unstructured)
+		if !ok || namespace == nil {
+			return obj, done, err
+		}
+		if blockers := namespaceTerminationBlockers(namespace); len(blockers) > 0 {
+			err = fmt.Errorf("%w; termination is blocked by %s", err, strings.Join(blockers, "; "))
+		}
+		return obj, done, err
+	}
+}
+
+// namespaceTerminationBlockers returns what keeps a terminating namespace from being deleted,
+// or nothing if it is not terminating
+func namespaceTerminationBlockers(namespace *unstructured.Unstructured) []string {
+	if namespace.GetDeletionTimestamp() == nil {
+		return nil
+	}
+	var blockers []string
+	if finalizers, _, _ := unstructured.NestedStringSlice(namespace.Object, "spec", "finalizers"); len(finalizers) > 0 {
+		blockers = append(blockers, fmt.Sprintf("spec.finalizers %s", strings.Join(finalizers, ",")))
+	}
+	conditions, _, _ := unstructured.NestedSlice(namespace.Object, "status", "conditions")
+	for _, conditionUncast := range conditions {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status, _, _ := unstructured.NestedString(condition, "status"); status != "True" {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		blockers = append(blockers, fmt.Sprintf("%s: %s", conditionType, message))
+	}
+	return blockers
+}
+
+// Wait has helper methods for handling watches, including error handling.
+type Wait struct {
+	errOut io.Writer
+}
+
+// IsDeleted returns true if the object is deleted. It prints any errors it encounters.
+func (w Wait) IsDeleted(event watch.Event) (bool, error) {
+	switch event.Type {
+	case watch.Error:
+		// keep waiting in the event we see an error - we expect the watch to be closed by
+		// the server if the error is unrecoverable.
+		err := apierrors.FromObject(event.Object)
+		fmt.Fprintf(w.errOut, "error: An error occurred while waiting for the object to be deleted: %v\n", err)
+		return false, nil
 	case watch.Deleted:
 		return true, nil
 	default:
@@ -404,138 +3148,1095 @@ func (w Wait) IsDeleted(event watch.Event) (bool, error) {
 	}
 }
 
-type isCondMetFunc func(event watch.Event) (bool, error)
-type checkCondFunc func(obj *unstructured.Unstructured) (bool, error)
+// IsCreated is a condition func for waiting for something to be created
+func IsCreated(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	created := func(obj *unstructured.Unstructured) (bool, error) {
+		return true, nil
+	}
+	condMet := eventCondition(o.ErrOut, "the object to be created", false, created)
+	return getObjAndCheckCondition(ctx, info, o, condMet, created, nil, nil)
+}
+
+type isCondMetFunc func(event watch.Event) (bool, error)
+type checkCondFunc func(obj *unstructured.Unstructured) (bool, error)
+
+// eventCondition returns the isCondMetFunc checking every object seen on the watch with check.
+// An error event is written to errOut, as an error which occurred while waiting for what, and a
+// Deleted event only meets the condition when onDeleted is set.
+func eventCondition(errOut io.Writer, what string, onDeleted bool, check checkCondFunc) isCondMetFunc {
+	return func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Error:
+			// keep waiting in the event we see an error - we expect the watch to be closed by
+			// the server
+			err := apierrors.FromObject(event.Object)
+			fmt.Fprintf(errOut, "error: An error occurred while waiting for %s: %v\n", what, err)
+			return false, nil
+		case watch.Deleted:
+			// this will chain back out, result in another get and an return false back up the chain
+			return onDeleted, nil
+		}
+		return check(event.Object.(*unstructured.Unstructured))
+	}
+}
+
+// observeFunc returns the value of an object a condition is checked against, for reporting
+type observeFunc func(obj *unstructured.Unstructured) string
+
+// describeFunc explains what a condition is waiting for, given the last value observed on
+// the object, so that it can be reported when the wait times out
+type describeFunc func(observed string) string
+
+// timeoutErrorFor returns a TimeoutError for the resource which describes the last object
+// seen, if any, when describe is set
+func timeoutErrorFor(info *resource.Info, lastObj *unstructured.Unstructured, observe observeFunc, describe describeFunc) error {
+	return &TimeoutError{Resource: info.Mapping.Resource.Resource, Name: info.Name, Detail: describeObserved(lastObj, observe, describe), Observed: observedOn(lastObj, observe)}
+}
+
+// maxPollsErrorFor returns a MaxPollsExceededError for the resource which describes the last
+// object seen, if any, when describe is set
+func (o *WaitOptions) maxPollsErrorFor(info *resource.Info, lastObj *unstructured.Unstructured, observe observeFunc, describe describeFunc) error {
+	return &MaxPollsExceededError{Resource: info.Mapping.Resource.Resource, Name: info.Name, Polls: o.MaxPolls, Detail: describeObserved(lastObj, observe, describe), Observed: observedOn(lastObj, observe)}
+}
+
+// cancelledErrorFor returns err, the error of the context the wait on the resource was stopped
+// with, along with a description of the last object seen, if any, when describe is set, so that
+// an interrupted wait reports where every resource got to
+func cancelledErrorFor(err error, info *resource.Info, lastObj *unstructured.Unstructured, observe observeFunc, describe describeFunc) error {
+	if detail := describeObserved(lastObj, observe, describe); len(detail) > 0 {
+		return fmt.Errorf("%w on %s/%s: %s", err, info.Mapping.Resource.Resource, info.Name, detail)
+	}
+	return extendErrWaitTimeout(err, info)
+}
+
+// notMetErrorFor returns a NotMetError for the resource, checked once with WaitOptions.CheckNow,
+// which describes the object seen, if any, when describe is set
+func notMetErrorFor(info *resource.Info, obj *unstructured.Unstructured, observe observeFunc, describe describeFunc) error {
+	err := &NotMetError{Resource: info.Mapping.Resource.Resource, Name: info.Name, Observed: observedOn(obj, observe)}
+	if detail := describeObserved(obj, observe, describe); len(detail) > 0 {
+		err.Detail = "expected " + detail
+	}
+	return err
+}
+
+// describeObserved describes the condition and the value observed for it on obj, or returns
+// "" if describe is not set
+func describeObserved(obj *unstructured.Unstructured, observe observeFunc, describe describeFunc) string {
+	if describe == nil {
+		return ""
+	}
+	observed := observedOn(obj, observe)
+	if len(observed) == 0 {
+		observed = "<none>"
+	}
+	return describe(observed)
+}
+
+// observedOn returns the value observed for a condition on obj, or "" if there is none
+func observedOn(obj *unstructured.Unstructured, observe observeFunc) string {
+	if obj == nil || observe == nil {
+		return ""
+	}
+	return observe(obj)
+}
+
+// errMaxPollsExceeded ends a watch once the condition has been checked MaxPolls times
+var errMaxPollsExceeded = errors.New("max polls exceeded")
+
+// recordingProgress wraps condMet so that every object seen on the watch is recorded as a check
+// of the condition, and ends the watch with errMaxPollsExceeded once there have been MaxPolls
+func (o *WaitOptions) recordingProgress(info *resource.Info, start time.Time, observe observeFunc, condMet isCondMetFunc) isCondMetFunc {
+	logging := klog.V(4).Enabled()
+	if o.ProgressWriter == nil && o.checks == nil && !logging {
+		return condMet
+	}
+	return func(event watch.Event) (bool, error) {
+		done, err := condMet(event)
+		if event.Type != watch.Error {
+			observed := ""
+			if obj, ok := event.Object.(*unstructured.Unstructured); ok && observe != nil && (o.ProgressWriter != nil || o.observed != nil || logging) {
+				observed = observe(obj)
+			}
+			o.recordProgress(info, start, observed, done)
+			if !done && err == nil && o.pollsExhausted() {
+				return false, errMaxPollsExceeded
+			}
+		}
+		return done, err
+	}
+}
+
+// listObject lists the resource with nameSelector, or gets its Subresource if one is set, which
+// cannot be listed. A missing subresource is returned as an empty list, as a missing resource is.
+func (o *WaitOptions) listObject(ctx context.Context, info *resource.Info, nameSelector string) (*unstructured.UnstructuredList, error) {
+	client := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace)
+	if len(o.Subresource) == 0 {
+		return client.List(ctx, metav1.ListOptions{FieldSelector: nameSelector})
+	}
+	obj, err := client.Get(ctx, info.Name, metav1.GetOptions{}, o.Subresource)
+	if apierrors.IsNotFound(err) {
+		return &unstructured.UnstructuredList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*obj}}, nil
+}
+
+// supportedSubresources are the subresources --subresource accepts
+var supportedSubresources = sets.NewString("status", "scale")
+
+// subresourceCheckFor wraps conditionFnFor so that the wait on a resource whose kind has no
+// such subresource fails right away, rather than wait for a subresource that never appears
+func subresourceCheckFor(discoveryClient discovery.DiscoveryInterface, subresource string, conditionFnFor func(*meta.RESTMapping) (ConditionFunc, error)) func(*meta.RESTMapping) (ConditionFunc, error) {
+	return func(mapping *meta.RESTMapping) (ConditionFunc, error) {
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(mapping.Resource.GroupVersion().String())
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, apiResource := range resources.APIResources {
+			if apiResource.Name == mapping.Resource.Resource+"/"+subresource {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s has no %s subresource", mapping.Resource.GroupResource(), subresource)
+		}
+		if conditionFnFor == nil {
+			return nil, nil
+		}
+		return conditionFnFor(mapping)
+	}
+}
+
+// errWatchExpired ends a watch when the server reports that the resourceVersion it started
+// from is too old, so that the resource is listed again for a current one
+var errWatchExpired = errors.New("watch expired")
+
+// isWatchExpired returns true if err reports that a resourceVersion is too old to watch from
+func isWatchExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// resourceVersionTracker follows the resourceVersion of the events of a watch, bookmarks
+// included, so that a watch closed by the server can be resumed from where it ended
+type resourceVersionTracker struct {
+	// version is the resourceVersion of the last event seen, if any
+	version string
+}
+
+// watching wraps condMet so that bookmarks only update the resourceVersion, and an expired
+// watch ends with errWatchExpired rather than being reported as an error
+func (t *resourceVersionTracker) watching(condMet isCondMetFunc) isCondMetFunc {
+	return func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			if isWatchExpired(apierrors.FromObject(event.Object)) {
+				return false, errWatchExpired
+			}
+			return condMet(event)
+		}
+		if obj, err := meta.Accessor(event.Object); err == nil && len(obj.GetResourceVersion()) > 0 {
+			t.version = obj.GetResourceVersion()
+		}
+		if event.Type == watch.Bookmark {
+			return false, nil
+		}
+		return condMet(event)
+	}
+}
+
+// errResourceRecreated ends a watch when the resource is seen with a new UID, so that it is
+// listed again
+var errResourceRecreated = errors.New("resource was recreated")
+
+// uidTracker follows the UID of a resource while waiting on it, so that a resource which is
+// deleted and created again under the same name is not mistaken for the original one.
+type uidTracker struct {
+	uid types.UID
+	// pinned is true if uid comes from the UIDMap, in which case only that object may
+	// satisfy the condition
+	pinned bool
+}
+
+// newUIDTracker returns a uidTracker for the resource, seeded from uidMap if it has an entry for it
+func newUIDTracker(info *resource.Info, uidMap UIDMap) *uidTracker {
+	resourceLocation := ResourceLocation{
+		GroupResource: info.Mapping.Resource.GroupResource(),
+		Namespace:     info.Namespace,
+		Name:          info.Name,
+	}
+	if uid, ok := uidMap[resourceLocation]; ok {
+		return &uidTracker{uid: uid, pinned: true}
+	}
+	return &uidTracker{}
+}
+
+// changed returns true if obj has a different UID than the one seen so far
+func (t *uidTracker) changed(obj *unstructured.Unstructured) bool {
+	return len(t.uid) > 0 && len(obj.GetUID()) > 0 && obj.GetUID() != t.uid
+}
+
+// watching wraps condMet so that the watch ends with errResourceRecreated when an event for
+// an object with a different UID is seen, instead of checking the condition against it
+func (t *uidTracker) watching(condMet isCondMetFunc) isCondMetFunc {
+	return func(event watch.Event) (bool, error) {
+		if obj, ok := event.Object.(*unstructured.Unstructured); ok && event.Type != watch.Error && t.changed(obj) {
+			return false, errResourceRecreated
+		}
+		return condMet(event)
+	}
+}
+
+// errStabilityChanged ends a watch when a condition starts or stops being met while
+// stabilizing, so that the watch can be started again with a timeout matching the window
+var errStabilityChanged = errors.New("condition stability changed")
+
+// stabilityTracker tracks how long a condition has been met with the same observed value,
+// for WaitOptions.StableFor, or met at all when anyValue is set, for WaitOptions.Settle
+type stabilityTracker struct {
+	window   time.Duration
+	anyValue bool
+	clock    clockwork.Clock
+
+	// since is when the condition was first met with the value, or the zero time if it is not met
+	since time.Time
+	value string
+}
+
+// update records whether the condition is met and the value it was checked against, and
+// returns true once it has been met with the same value for the whole window
+func (t *stabilityTracker) update(met bool, value string) bool {
+	if !met {
+		t.reset()
+		return false
+	}
+	if t.window <= 0 {
+		return true
+	}
+	if t.since.IsZero() || (value != t.value && !t.anyValue) {
+		t.since, t.value = t.clock.Now(), value
+	}
+	return t.clock.Since(t.since) >= t.window
+}
+
+// newStabilityTracker returns the stabilityTracker for the StableFor or the Settle window
+// of the options
+func newStabilityTracker(o *WaitOptions) *stabilityTracker {
+	if o.StableFor > 0 || o.Settle <= 0 {
+		return &stabilityTracker{window: o.StableFor, clock: o.clock()}
+	}
+	return &stabilityTracker{window: o.Settle, anyValue: true, clock: o.clock()}
+}
+
+// reset forgets that the condition was met
+func (t *stabilityTracker) reset() {
+	t.since, t.value = time.Time{}, ""
+}
+
+// timeLeft returns how long the condition still has to hold, and false if it is not met
+func (t *stabilityTracker) timeLeft() (time.Duration, bool) {
+	if t.window <= 0 || t.since.IsZero() {
+		return 0, false
+	}
+	return t.window - t.clock.Since(t.since), true
+}
+
+// watching wraps condMet so that the watch ends with errStabilityChanged whenever the
+// condition starts or stops being met, or is met with a different value, while a window is set
+func (t *stabilityTracker) watching(observe observeFunc, condMet isCondMetFunc) isCondMetFunc {
+	if t.window <= 0 {
+		return condMet
+	}
+	return func(event watch.Event) (bool, error) {
+		met, err := condMet(event)
+		if err != nil {
+			return met, err
+		}
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok || event.Type == watch.Error {
+			return false, nil
+		}
+		value := ""
+		if observe != nil {
+			value = observe(obj)
+		}
+		since := t.since
+		if t.update(met, value) {
+			return true, nil
+		}
+		if !t.since.Equal(since) {
+			return false, errStabilityChanged
+		}
+		return false, nil
+	}
+}
+
+// transitionTracker tracks whether a condition has been seen unmet, for
+// WaitOptions.RequireTransition
+type transitionTracker struct {
+	info *resource.Info
+	mode TransitionMode
+
+	checked   bool
+	seenUnmet bool
+}
+
+// update records whether the condition is met, and returns whether it counts as met. It
+// returns a ConditionUnmetError in TransitionModeFail if the first check finds it met.
+func (t *transitionTracker) update(met bool) (bool, error) {
+	if t.mode == "" || t.mode == TransitionModeNone {
+		return met, nil
+	}
+	first := !t.checked
+	t.checked = true
+	switch {
+	case !met:
+		t.seenUnmet = true
+		return false, nil
+	case t.seenUnmet:
+		return true, nil
+	case first && t.mode == TransitionModeFail:
+		return false, newConditionUnmetError(t.info, "the condition was already met when the wait started, so no transition was observed")
+	}
+	return false, nil
+}
+
+// watching wraps condMet so that a condition met on the watch only counts once it has been
+// seen unmet, when a transition is required
+func (t *transitionTracker) watching(condMet isCondMetFunc) isCondMetFunc {
+	if t.mode == "" || t.mode == TransitionModeNone {
+		return condMet
+	}
+	return func(event watch.Event) (bool, error) {
+		met, err := condMet(event)
+		if err != nil || event.Type == watch.Error {
+			return met, err
+		}
+		return t.update(met)
+	}
+}
 
 // getObjAndCheckCondition will make a List query to the API server to get the object and check if the condition is met using check function.
-// If the condition is not met, it will make a Watch query to the server and pass in the condMet function
-func getObjAndCheckCondition(info *resource.Info, o *WaitOptions, condMet isCondMetFunc, check checkCondFunc) (runtime.Object, bool, error) {
-	endTime := time.Now().Add(o.Timeout)
+// If the condition is not met, it will make a Watch query to the server and pass in the condMet function.
+// observe is optional and reports the value the condition was checked against, which describe,
+// if set, explains in the error returned on timeout.
+func getObjAndCheckCondition(ctx context.Context, info *resource.Info, o *WaitOptions, condMet isCondMetFunc, check checkCondFunc, observe observeFunc, describe describeFunc) (runtime.Object, bool, error) {
+	startTime := o.clock().Now()
+	endTime := o.deadline(startTime)
+	polls := 0
+	uids := newUIDTracker(info, o.UIDMap)
+	stable := newStabilityTracker(o)
+	transitions := &transitionTracker{info: info, mode: o.RequireTransition}
+	transientFailures := 0
+	// resumeVersion is the resourceVersion to watch from again when the server closed the
+	// last watch, instead of listing the resource
+	resumeVersion := o.initialResourceVersion()
+	var gottenObj *unstructured.Unstructured
 	for {
 		if len(info.Name) == 0 {
 			return info.Object, false, fmt.Errorf("resource name must be provided")
 		}
 
-		nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+		nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+
+		resourceVersion, resumed := resumeVersion, len(resumeVersion) > 0
+		resumeVersion = ""
+		if !resumed {
+			gottenObj = nil
+			// List with a name field selector to get the current resourceVersion to watch from (not the object's resourceVersion)
+			gottenObjList, err := o.listObject(ctx, info, nameSelector)
+
+			switch {
+			case err != nil && o.retryTransient(ctx, endTime, &transientFailures, err):
+				continue
+			case err != nil:
+				return info.Object, false, err
+			case len(gottenObjList.Items) != 1:
+				stable.reset()
+				transitions.update(false)
+				resourceVersion = gottenObjList.GetResourceVersion()
+			default:
+				gottenObj = &gottenObjList.Items[0]
+				if uids.changed(gottenObj) {
+					if uids.pinned {
+						return gottenObj, false, newConditionUnmetError(info, "resource was recreated with uid %s, expected uid %s", gottenObj.GetUID(), uids.uid)
+					}
+					fmt.Fprintf(o.ErrOut, "warning: %s/%s was recreated with uid %s, waiting on the new object\n", info.Mapping.Resource.Resource, info.Name, gottenObj.GetUID())
+					stable.reset()
+				}
+				uids.uid = gottenObj.GetUID()
+				conditionMet, err := check(gottenObj)
+				if err == nil {
+					conditionMet, err = transitions.update(conditionMet)
+				}
+				observed := ""
+				if observe != nil {
+					observed = observe(gottenObj)
+				}
+				o.recordProgress(info, startTime, observed, conditionMet)
+				if stable.update(conditionMet, observed) {
+					return gottenObj, true, nil
+				}
+				if err != nil {
+					return gottenObj, false, err
+				}
+				resourceVersion = gottenObjList.GetResourceVersion()
+			}
+
+			if o.CheckNow {
+				if gottenObj == nil {
+					return info.Object, false, notMetErrorFor(info, nil, observe, describe)
+				}
+				return gottenObj, false, notMetErrorFor(info, gottenObj, observe, describe)
+			}
+			if o.pollsExhausted() {
+				return gottenObj, false, o.maxPollsErrorFor(info, gottenObj, observe, describe)
+			}
+			if o.polling() {
+				polls++
+				if err := o.waitForNextPoll(ctx, endTime, polls); err == wait.ErrWaitTimeout {
+					return gottenObj, false, timeoutErrorFor(info, gottenObj, observe, describe)
+				} else if err != nil {
+					return gottenObj, false, cancelledErrorFor(err, info, gottenObj, observe, describe)
+				}
+				continue
+			}
+		}
+
+		timeout, ok := o.timeLeft(endTime)
+		if !ok {
+			// we're out of time
+			return objectOrInfo(info, gottenObj), false, timeoutErrorFor(info, gottenObj, observe, describe)
+		}
+		stabilizing := false
+		if window, ok := stable.timeLeft(); ok && (timeout == 0 || window < timeout) {
+			if window <= 0 {
+				// the window has just ended, list the object again
+				continue
+			}
+			// stop watching when the window ends so that the object is listed again
+			timeout, stabilizing = window, true
+		}
+
+		watchOptions := metav1.ListOptions{}
+		watchOptions.FieldSelector = nameSelector
+		watchOptions.ResourceVersion = resourceVersion
+		watchOptions.AllowWatchBookmarks = true
+		objWatch, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Watch(ctx, watchOptions)
+		if err != nil && resumed && isWatchExpired(err) {
+			// the resourceVersion the last watch ended at is too old by now, list again
+			continue
+		} else if err != nil && o.retryTransient(ctx, endTime, &transientFailures, err) {
+			continue
+		} else if err != nil {
+			return objectOrInfo(info, gottenObj), false, err
+		}
+		transientFailures = 0
+
+		versions := &resourceVersionTracker{}
+		watchCtx, cancel := watchtools.ContextWithOptionalTimeout(ctx, timeout)
+		watchEvent, err := watchtools.UntilWithoutRetry(watchCtx, objWatch, watchtools.ConditionFunc(versions.watching(o.recordingProgress(info, startTime, observe, uids.watching(stable.watching(observe, transitions.watching(condMet)))))))
+		cancel()
+		switch {
+		case err == nil:
+			return watchEvent.Object, true, nil
+		case err == watchtools.ErrWatchClosed:
+			// carry on from the last event seen, or list again if there was none
+			resumeVersion = versions.version
+			continue
+		case err == errWatchExpired, err == errResourceRecreated, err == errStabilityChanged:
+			continue
+		case err == errMaxPollsExceeded:
+			lastObj, _ := watchEvent.Object.(*unstructured.Unstructured)
+			return watchEvent.Object, false, o.maxPollsErrorFor(info, lastObj, observe, describe)
+		case err == wait.ErrWaitTimeout && ctx.Err() != nil:
+			if watchEvent != nil {
+				lastObj, _ := watchEvent.Object.(*unstructured.Unstructured)
+				return watchEvent.Object, false, cancelledErrorFor(ctx.Err(), info, lastObj, observe, describe)
+			}
+			return objectOrInfo(info, gottenObj), false, cancelledErrorFor(ctx.Err(), info, gottenObj, observe, describe)
+		case err == wait.ErrWaitTimeout && stabilizing:
+			continue
+		case err == wait.ErrWaitTimeout:
+			if watchEvent != nil {
+				lastObj, _ := watchEvent.Object.(*unstructured.Unstructured)
+				return watchEvent.Object, false, timeoutErrorFor(info, lastObj, observe, describe)
+			}
+			return objectOrInfo(info, gottenObj), false, timeoutErrorFor(info, gottenObj, observe, describe)
+		default:
+			return objectOrInfo(info, gottenObj), false, err
+		}
+	}
+}
+
+// ConditionalWait hold information to check an API status condition
+type ConditionalWait struct {
+	conditionName string
+	// conditionStatus is the status the condition has to have, or several of them separated by
+	// commas, any of which meets it.
+	conditionStatus string
+	// conditionReason is optional. When set, the reason of the condition must contain it.
+	conditionReason string
+	// negated is optional. When set, the condition has to be present with any status other
+	// than conditionStatus, such as Unknown.
+	negated bool
+	// errOut is written to if an error occurs. It defaults to the ErrOut of the WaitOptions.
+	errOut io.Writer
+}
+
+// IsConditionMet is a conditionfunc for waiting on an API condition to be met
+func (w ConditionalWait) IsConditionMet(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if w.errOut == nil {
+		w.errOut = o.ErrOut
+	}
+	condMet := eventCondition(w.errOut, "the condition to be satisfied", false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedStatus, w.describe)
+}
+
+// observedStatus returns the status of the condition on the object, followed by its reason if
+// a reason is waited on, or "" if it is not present
+func (w ConditionalWait) observedStatus(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, conditionUncast := range conditions {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(condition, "type")
+		if !strings.EqualFold(name, w.conditionName) {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		if len(w.conditionReason) > 0 {
+			reason, _, _ := unstructured.NestedString(condition, "reason")
+			return fmt.Sprintf("%s, reason %s", status, reason)
+		}
+		return status
+	}
+	return ""
+}
+
+// describe explains which status of the condition is waited on
+func (w ConditionalWait) describe(observed string) string {
+	if w.negated {
+		return fmt.Sprintf("condition %s (last observed: %s) to be present and not %s", w.conditionName, observed, w.conditionStatus)
+	}
+	if len(w.conditionReason) > 0 {
+		return fmt.Sprintf("condition %s (last observed: %s) to be %s with a reason containing %s", w.conditionName, observed, w.statuses(), w.conditionReason)
+	}
+	return fmt.Sprintf("condition %s (last observed: %s) to be %s", w.conditionName, observed, w.statuses())
+}
+
+// statuses returns the statuses which meet the condition, as in "True or Unknown"
+func (w ConditionalWait) statuses() string {
+	return strings.Join(strings.Split(w.conditionStatus, ","), " or ")
+}
+
+// hasStatus returns true if status is one of the statuses which meet the condition
+func (w ConditionalWait) hasStatus(status string) bool {
+	for _, expected := range strings.Split(w.conditionStatus, ",") {
+		if strings.EqualFold(status, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w ConditionalWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+	for _, conditionUncast := range conditions {
+		condition := conditionUncast.(map[string]interface{})
+		name, found, err := unstructured.NestedString(condition, "type")
+		if !found || err != nil || !strings.EqualFold(name, w.conditionName) {
+			continue
+		}
+		status, found, err := unstructured.NestedString(condition, "status")
+		if !found || err != nil {
+			continue
+		}
+		generation, found, _ := unstructured.NestedInt64(obj.Object, "metadata", "generation")
+		if found {
+			observedGeneration, found := getObservedGeneration(obj, condition)
+			if found && observedGeneration < generation {
+				return false, nil
+			}
+		}
+		if len(w.conditionReason) > 0 {
+			reason, _, _ := unstructured.NestedString(condition, "reason")
+			if !strings.Contains(reason, w.conditionReason) {
+				return false, nil
+			}
+		}
+		return w.hasStatus(status) != w.negated, nil
+	}
+
+	return false, nil
+}
+
+// GenerationWait waits for the controller of a resource to observe its latest generation
+type GenerationWait struct{}
+
+// IsGenerationObserved is a conditionfunc for waiting on .status.observedGeneration to catch up
+// with .metadata.generation on an object which is not being deleted
+func (w GenerationWait) IsGenerationObserved(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	condMet := eventCondition(o.ErrOut, "the generation to be observed", false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedGeneration, w.describe)
+}
+
+// observedGeneration returns the observed and latest generations of the object
+func (w GenerationWait) observedGeneration(obj *unstructured.Unstructured) string {
+	observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if !found {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", observedGeneration, obj.GetGeneration())
+}
+
+// describe explains that the observed generation is waited on to match the latest generation
+func (w GenerationWait) describe(observed string) string {
+	return fmt.Sprintf("observedGeneration/generation (last observed: %s) to match", observed)
+}
+
+func (w GenerationWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil || !found {
+		// the controller does not report an observed generation (yet), keep waiting
+		return false, nil
+	}
+	return observedGeneration == obj.GetGeneration(), nil
+}
+
+// TemplateWait waits for a Go template, as used by -o go-template, to render "true" against a
+// resource. Missing keys render as empty values, and a template which fails to execute, for
+// instance because it compares fields which are not set yet, is not met.
+type TemplateWait struct {
+	template string
+	printer  *printers.GoTemplatePrinter
+}
+
+// newTemplateWait parses the template up front, so that an invalid one fails before waiting
+func newTemplateWait(template string) (TemplateWait, error) {
+	if len(strings.TrimSpace(template)) == 0 {
+		return TemplateWait{}, errors.New("template wait condition cannot be empty")
+	}
+	printer, err := printers.NewGoTemplatePrinter([]byte(template))
+	if err != nil {
+		return TemplateWait{}, fmt.Errorf("template wait condition %q is not a valid template: %v", template, err)
+	}
+	printer.AllowMissingKeys(true)
+	return TemplateWait{template: template, printer: printer}, nil
+}
+
+// IsTemplateTrue is a conditionfunc for waiting on a Go template to render "true"
+func (w TemplateWait) IsTemplateTrue(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	condMet := eventCondition(o.ErrOut, "the template to render true", false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedOutput, w.describe)
+}
+
+// render executes the template against the object, and returns its output without the
+// surrounding whitespace
+func (w TemplateWait) render(obj *unstructured.Unstructured) (string, error) {
+	var out strings.Builder
+	if err := w.printer.PrintObj(obj, &out); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// observedOutput returns what the template rendered, or why it could not be executed
+func (w TemplateWait) observedOutput(obj *unstructured.Unstructured) string {
+	output, err := w.render(obj)
+	if err != nil {
+		return err.Error()
+	}
+	return output
+}
+
+// describe explains that the template is waited on to render true
+func (w TemplateWait) describe(observed string) string {
+	return fmt.Sprintf("template %s (last observed: %s) to render true", w.template, observed)
+}
+
+func (w TemplateWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	output, err := w.render(obj)
+	return err == nil && output == "true", nil
+}
+
+// FinalizersWait waits for the finalizers of a resource to be removed. A resource which is
+// gone has no finalizers left.
+type FinalizersWait struct{}
+
+// IsFinalizersRemoved is a conditionfunc for waiting on .metadata.finalizers to be empty
+func (w FinalizersWait) IsFinalizersRemoved(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if len(info.Name) > 0 {
+		nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+		gottenObjList, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).List(ctx, metav1.ListOptions{FieldSelector: nameSelector})
+		if apierrors.IsNotFound(err) || (err == nil && len(gottenObjList.Items) == 0) {
+			// already deleted
+			o.recordProgress(info, o.clock().Now(), "", true)
+			return info.Object, true, nil
+		}
+	}
+	condMet := eventCondition(o.ErrOut, "the finalizers to be removed", true, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedFinalizers, w.describe)
+}
+
+// observedFinalizers returns the finalizers left on the object
+func (w FinalizersWait) observedFinalizers(obj *unstructured.Unstructured) string {
+	return strings.Join(obj.GetFinalizers(), ",")
+}
+
+// describe explains that the finalizers are waited on to be removed
+func (w FinalizersWait) describe(observed string) string {
+	return fmt.Sprintf("finalizers (last observed: %s) to be removed", observed)
+}
+
+func (w FinalizersWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	return len(obj.GetFinalizers()) == 0, nil
+}
+
+// KeyWait waits for a Secret or ConfigMap to have a key in its data. Only the names of the
+// keys are ever looked at, so that the values of a Secret are not printed.
+type KeyWait struct {
+	key string
+}
+
+// IsKeyPresent is a conditionfunc for waiting on a key of .data, .stringData or .binaryData. It
+// returns a ConditionUnmetError if the resource is neither a Secret nor a ConfigMap.
+func (w KeyWait) IsKeyPresent(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	switch info.Mapping.Resource.GroupResource() {
+	case schema.GroupResource{Resource: "secrets"}, schema.GroupResource{Resource: "configmaps"}:
+	default:
+		return info.Object, false, newConditionUnmetError(info, "has-key only applies to secrets and configmaps")
+	}
+	condMet := eventCondition(o.ErrOut, fmt.Sprintf("the key %s", w.key), false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedKeys, w.describe)
+}
+
+// keys returns the sorted names of the keys in the data of the object
+func (w KeyWait) keys(obj *unstructured.Unstructured) []string {
+	keys := sets.NewString()
+	for _, field := range []string{"data", "stringData", "binaryData"} {
+		data, _, _ := unstructured.NestedMap(obj.Object, field)
+		for key := range data {
+			keys.Insert(key)
+		}
+	}
+	return keys.List()
+}
+
+// observedKeys returns the names of the keys in the data of the object, never their values
+func (w KeyWait) observedKeys(obj *unstructured.Unstructured) string {
+	return strings.Join(w.keys(obj), ",")
+}
+
+// describe explains which key is waited on
+func (w KeyWait) describe(observed string) string {
+	return fmt.Sprintf("key %s (last observed keys: %s) to be present", w.key, observed)
+}
+
+func (w KeyWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	for _, key := range w.keys(obj) {
+		if key == w.key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// maxObservedAnnotationLength is how much of the value of an annotation is reported on a
+// timeout, since annotations such as kubectl.kubernetes.io/last-applied-configuration can
+// hold a whole object
+const maxObservedAnnotationLength = 64
+
+// MetadataWait waits for a resource to carry a label or an annotation, or one with a given
+// value, for instance to check that a mutating webhook stamped it or that a controller
+// recorded its progress
+type MetadataWait struct {
+	// annotation is set to wait on an annotation rather than on a label
+	annotation bool
+	key        string
+	value      string
+	// hasValue is unset when any value meets the condition
+	hasValue bool
+}
+
+// newLabelWait validates the key and the value of a label condition
+func newLabelWait(key, value string, hasValue bool) (MetadataWait, error) {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return MetadataWait{}, fmt.Errorf("label key %q is not valid: %s", key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(value); hasValue && len(errs) > 0 {
+		return MetadataWait{}, fmt.Errorf("label value %q is not valid: %s", value, strings.Join(errs, "; "))
+	}
+	return MetadataWait{key: key, value: value, hasValue: hasValue}, nil
+}
+
+// newAnnotationWait validates the key of an annotation condition. Any value is valid.
+func newAnnotationWait(key, value string, hasValue bool) (MetadataWait, error) {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return MetadataWait{}, fmt.Errorf("annotation key %q is not valid: %s", key, strings.Join(errs, "; "))
+	}
+	return MetadataWait{annotation: true, key: key, value: value, hasValue: hasValue}, nil
+}
+
+// IsMetadataSet is a conditionfunc for waiting on a label of .metadata.labels, or an annotation
+// of .metadata.annotations, to be present, or to have the value of the MetadataWait
+func (w MetadataWait) IsMetadataSet(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	condMet := eventCondition(o.ErrOut, fmt.Sprintf("the %s %s", w.what(), w.key), false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedMetadata, w.describe)
+}
+
+// what returns "label" or "annotation"
+func (w MetadataWait) what() string {
+	if w.annotation {
+		return "annotation"
+	}
+	return "label"
+}
+
+// metadata returns the labels or the annotations of the object
+func (w MetadataWait) metadata(obj *unstructured.Unstructured) map[string]string {
+	if w.annotation {
+		return obj.GetAnnotations()
+	}
+	return obj.GetLabels()
+}
+
+// observedMetadata returns the labels or the annotations of the object sorted by key, as in
+// "app=nginx,tier=web", or <none> if it has none. Long annotation values are cut short.
+func (w MetadataWait) observedMetadata(obj *unstructured.Unstructured) string {
+	metadata := w.metadata(obj)
+	if len(metadata) == 0 {
+		return "<none>"
+	}
+	observed := make([]string, 0, len(metadata))
+	for _, key := range sets.StringKeySet(metadata).List() {
+		value := metadata[key]
+		if runes := []rune(value); len(runes) > maxObservedAnnotationLength {
+			value = string(runes[:maxObservedAnnotationLength]) + "..."
+		}
+		observed = append(observed, key+"="+value)
+	}
+	return strings.Join(observed, ",")
+}
+
+// describe explains which label or annotation is waited on
+func (w MetadataWait) describe(observed string) string {
+	if w.hasValue {
+		return fmt.Sprintf("%s %s=%s (last observed %ss: %s) to be set", w.what(), w.key, w.value, w.what(), observed)
+	}
+	return fmt.Sprintf("%s %s (last observed %ss: %s) to be present", w.what(), w.key, w.what(), observed)
+}
 
-		var gottenObj *unstructured.Unstructured
-		// List with a name field selector to get the current resourceVersion to watch from (not the object's resourceVersion)
-		gottenObjList, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: nameSelector})
+func (w MetadataWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	value, found := w.metadata(obj)[w.key]
+	return found && (!w.hasValue || value == w.value), nil
+}
 
-		resourceVersion := ""
-		switch {
-		case err != nil:
-			return info.Object, false, err
-		case len(gottenObjList.Items) != 1:
-			resourceVersion = gottenObjList.GetResourceVersion()
-		default:
-			gottenObj = &gottenObjList.Items[0]
-			conditionMet, err := check(gottenObj)
-			if conditionMet {
-				return gottenObj, true, nil
-			}
-			if err != nil {
-				return gottenObj, false, err
-			}
-			resourceVersion = gottenObjList.GetResourceVersion()
-		}
+// OwnerWait waits for a resource to have an owner reference to an owner of a kind and name, for
+// instance to check that a controller adopted it
+type OwnerWait struct {
+	// kind and group are those of the owner. The kind is matched case-insensitively, and any
+	// group matches when group is empty.
+	kind  string
+	group string
+	name  string
+}
 
-		watchOptions := metav1.ListOptions{}
-		watchOptions.FieldSelector = nameSelector
-		watchOptions.ResourceVersion = resourceVersion
-		objWatch, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Watch(context.TODO(), watchOptions)
-		if err != nil {
-			return gottenObj, false, err
-		}
+// newOwnerWait returns an OwnerWait for the owner named name of the kind, which may be qualified
+// with a group as in replicaset.apps
+func newOwnerWait(kind, name string) OwnerWait {
+	w := OwnerWait{kind: kind, name: name}
+	if dot := strings.Index(kind, "."); dot != -1 {
+		w.kind, w.group = kind[:dot], kind[dot+1:]
+	}
+	return w
+}
 
-		timeout := endTime.Sub(time.Now())
-		errWaitTimeoutWithName := extendErrWaitTimeout(wait.ErrWaitTimeout, info)
-		if timeout < 0 {
-			// we're out of time
-			return gottenObj, false, errWaitTimeoutWithName
-		}
+// IsOwnedBy is a conditionfunc for waiting on .metadata.ownerReferences to reference the owner
+func (w OwnerWait) IsOwnedBy(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	condMet := eventCondition(o.ErrOut, fmt.Sprintf("the owner reference to %s/%s", w.kind, w.name), false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedOwners, w.describe)
+}
 
-		ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), o.Timeout)
-		watchEvent, err := watchtools.UntilWithoutRetry(ctx, objWatch, watchtools.ConditionFunc(condMet))
-		cancel()
-		switch {
-		case err == nil:
-			return watchEvent.Object, true, nil
-		case err == watchtools.ErrWatchClosed:
+// observedOwners returns the owners the object references, as kind/name
+func (w OwnerWait) observedOwners(obj *unstructured.Unstructured) string {
+	var owners []string
+	for _, ref := range obj.GetOwnerReferences() {
+		owners = append(owners, ref.Kind+"/"+ref.Name)
+	}
+	return strings.Join(owners, ",")
+}
+
+// describe explains which owner reference is waited on
+func (w OwnerWait) describe(observed string) string {
+	owner := w.kind
+	if len(w.group) > 0 {
+		owner += "." + w.group
+	}
+	return fmt.Sprintf("owner reference to %s/%s (last observed: %s) to be set", owner, w.name, observed)
+}
+
+func (w OwnerWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Name != w.name || !strings.EqualFold(ref.Kind, w.kind) {
 			continue
-		case err == wait.ErrWaitTimeout:
-			if watchEvent != nil {
-				return watchEvent.Object, false, errWaitTimeoutWithName
+		}
+		if len(w.group) > 0 {
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil || !strings.EqualFold(gv.Group, w.group) {
+				continue
 			}
-			return gottenObj, false, errWaitTimeoutWithName
-		default:
-			return gottenObj, false, err
 		}
+		return true, nil
 	}
+	return false, nil
 }
 
-// ConditionalWait hold information to check an API status condition
-type ConditionalWait struct {
-	conditionName   string
-	conditionStatus string
-	// errOut is written to if an error occurs
-	errOut io.Writer
+// PhaseWait waits for .status.phase of a resource to reach a phase, and stops waiting as soon
+// as it reaches one of the phases from which it cannot
+type PhaseWait struct {
+	phase        string
+	failedPhases []string
 }
 
-// IsConditionMet is a conditionfunc for waiting on an API condition to be met
-func (w ConditionalWait) IsConditionMet(info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
-	return getObjAndCheckCondition(info, o, w.isConditionMet, w.checkCondition)
+// IsPhaseReached is a conditionfunc for waiting on .status.phase. It returns a
+// ConditionUnmetError if the phase is one of the failed phases, or if the resource does not
+// report a phase at all.
+func (w PhaseWait) IsPhaseReached(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	check := func(obj *unstructured.Unstructured) (bool, error) {
+		return w.checkCondition(info, obj)
+	}
+	condMet := eventCondition(o.ErrOut, fmt.Sprintf("the phase to be %s", w.phase), false, check)
+	return getObjAndCheckCondition(ctx, info, o, condMet, check, w.observedPhase, w.describe)
 }
 
-func (w ConditionalWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
-	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
-	if err != nil {
-		return false, err
+// observedPhase returns the phase of the object
+func (w PhaseWait) observedPhase(obj *unstructured.Unstructured) string {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	return phase
+}
+
+// describe explains which phase is waited on
+func (w PhaseWait) describe(observed string) string {
+	return fmt.Sprintf("phase (last observed: %s) to be %s", observed, w.phase)
+}
+
+func (w PhaseWait) checkCondition(info *resource.Info, obj *unstructured.Unstructured) (bool, error) {
+	phase, found, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil || !found {
+		return false, newConditionUnmetError(info, "the resource does not report a .status.phase to wait on")
 	}
-	if !found {
-		return false, nil
+	for _, failed := range w.failedPhases {
+		if phase == failed {
+			return false, newConditionUnmetError(info, "the phase is %s, it will not become %s", phase, w.phase)
+		}
+	}
+	return phase == w.phase, nil
+}
+
+// readyConditionTypes are the condition types which resources commonly report their health
+// with, in the order --for=ready looks for them
+var readyConditionTypes = []string{"Ready", "Available", "Synced", "Healthy"}
+
+// ReadyWait waits for a resource to be healthy, whichever condition type it reports that with.
+// The first of its condition types which the resource has is the one that must be true.
+type ReadyWait struct {
+	conditionTypes []string
+}
+
+// NewReadyWait returns a ReadyWait for the given condition types, in order of precedence, or
+// for Ready, Available, Synced and Healthy if none is given
+func NewReadyWait(conditionTypes ...string) ReadyWait {
+	if len(conditionTypes) == 0 {
+		conditionTypes = readyConditionTypes
 	}
+	return ReadyWait{conditionTypes: conditionTypes}
+}
+
+// IsReady is a conditionfunc for waiting on a resource to be healthy: the first of the
+// condition types of the ReadyWait which the resource reports must have a True status. A
+// resource reporting none of them keeps being waited on, since its controller may not have
+// set its conditions yet.
+func (w ReadyWait) IsReady(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	condMet := eventCondition(o.ErrOut, "the resource to be ready", false, w.checkCondition)
+	return getObjAndCheckCondition(ctx, info, o, condMet, w.checkCondition, w.observedConditions, w.describe)
+}
+
+// readyCondition returns the type and status of the first of the condition types of the
+// ReadyWait which obj has
+func (w ReadyWait) readyCondition(obj *unstructured.Unstructured) (conditionType, status string, found bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	statuses := map[string]string{}
 	for _, conditionUncast := range conditions {
-		condition := conditionUncast.(map[string]interface{})
-		name, found, err := unstructured.NestedString(condition, "type")
-		if !found || err != nil || !strings.EqualFold(name, w.conditionName) {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
 			continue
 		}
-		status, found, err := unstructured.NestedString(condition, "status")
-		if !found || err != nil {
+		name, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		statuses[name] = status
+	}
+	for _, conditionType := range w.conditionTypes {
+		if status, ok := statuses[conditionType]; ok {
+			return conditionType, status, true
+		}
+	}
+	return "", "", false
+}
+
+// observedConditions returns the status of every condition type of the ReadyWait which obj
+// has, as in "Ready=False,Synced=True", or <none> if it has none of them
+func (w ReadyWait) observedConditions(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var observed []string
+	for _, conditionUncast := range conditions {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
 			continue
 		}
-		generation, found, _ := unstructured.NestedInt64(obj.Object, "metadata", "generation")
-		if found {
-			observedGeneration, found := getObservedGeneration(obj, condition)
-			if found && observedGeneration < generation {
-				return false, nil
+		name, _, _ := unstructured.NestedString(condition, "type")
+		for _, conditionType := range w.conditionTypes {
+			if name == conditionType {
+				status, _, _ := unstructured.NestedString(condition, "status")
+				observed = append(observed, name+"="+status)
+				break
 			}
 		}
-		return strings.EqualFold(status, w.conditionStatus), nil
 	}
+	if len(observed) == 0 {
+		return "<none>"
+	}
+	return strings.Join(observed, ",")
+}
 
-	return false, nil
+// describe explains which condition types the resource is waited on for
+func (w ReadyWait) describe(observed string) string {
+	return fmt.Sprintf("the first of the conditions %s (last observed: %s) to be true", strings.Join(w.conditionTypes, ", "), observed)
 }
 
-func (w ConditionalWait) isConditionMet(event watch.Event) (bool, error) {
-	if event.Type == watch.Error {
-		// keep waiting in the event we see an error - we expect the watch to be closed by
-		// the server
-		err := apierrors.FromObject(event.Object)
-		fmt.Fprintf(w.errOut, "error: An error occurred while waiting for the condition to be satisfied: %v", err)
-		return false, nil
-	}
-	if event.Type == watch.Deleted {
-		// this will chain back out, result in another get and an return false back up the chain
-		return false, nil
-	}
-	obj := event.Object.(*unstructured.Unstructured)
-	return w.checkCondition(obj)
+func (w ReadyWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
+	_, status, found := w.readyCondition(obj)
+	return found && strings.EqualFold(status, "True"), nil
 }
 
 func extendErrWaitTimeout(err error, info *resource.Info) error {
-	return fmt.Errorf("%s on %s/%s", err.Error(), info.Mapping.Resource.Resource, info.Name)
+	if err == wait.ErrWaitTimeout {
+		return &TimeoutError{Resource: info.Mapping.Resource.Resource, Name: info.Name}
+	}
+	return fmt.Errorf("%w on %s/%s", err, info.Mapping.Resource.Resource, info.Name)
 }
 
 func getObservedGeneration(obj *unstructured.Unstructured, condition map[string]interface{}) (int64, bool) {
@@ -547,56 +4248,469 @@ func getObservedGeneration(obj *unstructured.Unstructured, condition map[string]
 	return statusObservedGeneration, found
 }
 
+// AllConditionsWait holds several conditions which must all be met at once
+type AllConditionsWait struct {
+	conditions   []string
+	conditionFns []ConditionFunc
+}
+
+// IsConditionMet is a conditionfunc for waiting on every one of several conditions to be met.
+// The conditions are checked in turn until they are all met by the same version of the object,
+// so a condition that stops being met while waiting on a later one is waited on again, after
+// waiting for the next poll so that a resource changing often is not listed again right away.
+func (w AllConditionsWait) IsConditionMet(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	endTime := o.deadline(o.clock().Now())
+	for rounds := 1; ; rounds++ {
+		var finalObject runtime.Object
+		resourceVersions := sets.NewString()
+		for i, conditionFn := range w.conditionFns {
+			timeout, ok := o.timeLeft(endTime)
+			if !ok {
+				return finalObject, false, extendErrWaitTimeout(wait.ErrWaitTimeout, info)
+			}
+			conditionOptions := *o
+			conditionOptions.Timeout = timeout
+			obj, done, err := conditionFn(ctx, info, &conditionOptions)
+			if !done {
+				if err == nil {
+					err = newConditionUnmetError(info, "")
+				}
+				return obj, false, fmt.Errorf("%w (unsatisfied condition: %s)", err, w.conditions[i])
+			}
+			if accessor, err := meta.Accessor(obj); err == nil && len(accessor.GetResourceVersion()) > 0 {
+				resourceVersions.Insert(accessor.GetResourceVersion())
+			}
+			finalObject = obj
+		}
+		if resourceVersions.Len() <= 1 || o.CheckNow {
+			// when checked once, each condition was met as the object was at its own check
+			return finalObject, true, nil
+		}
+		if err := o.waitForNextPoll(ctx, endTime, rounds); err == wait.ErrWaitTimeout {
+			return finalObject, false, extendErrWaitTimeout(err, info)
+		} else if err != nil {
+			return finalObject, false, err
+		}
+	}
+}
+
+// AnyConditionsWait holds several conditions of which any one must be met
+type AnyConditionsWait struct {
+	conditions   []string
+	conditionFns []ConditionFunc
+}
+
+// anyConditionResult is the outcome of the wait on one of the conditions of an AnyConditionsWait
+type anyConditionResult struct {
+	index int
+	obj   runtime.Object
+	done  bool
+	err   error
+}
+
+// IsConditionMet is a conditionfunc for waiting on any one of several conditions to be met.
+// The conditions are waited on at once, and the others are stopped as soon as one is met. It
+// fails with the error of the first condition once none of them can be met anymore.
+func (w AnyConditionsWait) IsConditionMet(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the conditions are waited on concurrently, so writes to the streams are serialized
+	parallelOptions := *o
+	parallelOptions.ErrOut = &syncWriter{w: o.ErrOut}
+	if o.ProgressWriter != nil {
+		parallelOptions.ProgressWriter = &syncWriter{w: o.ProgressWriter}
+	}
+	results := make(chan anyConditionResult, len(w.conditionFns))
+	for i, conditionFn := range w.conditionFns {
+		go func(i int, conditionFn ConditionFunc) {
+			conditionOptions := parallelOptions
+			obj, done, err := conditionFn(ctx, info, &conditionOptions)
+			results <- anyConditionResult{index: i, obj: obj, done: done, err: err}
+		}(i, conditionFn)
+	}
+
+	// the conditions still waited on once one is met are stopped, and waited for so that none of
+	// them reports progress after the wait is over
+	var met *anyConditionResult
+	unmet := make([]anyConditionResult, len(w.conditionFns))
+	for range w.conditionFns {
+		result := <-results
+		unmet[result.index] = result
+		if result.done && met == nil {
+			met = &result
+			cancel()
+		}
+	}
+	if met != nil {
+		return met.obj, true, nil
+	}
+	first := unmet[0]
+	err := first.err
+	if err == nil {
+		err = newConditionUnmetError(info, "")
+	}
+	return first.obj, false, fmt.Errorf("%w (unsatisfied conditions: %s)", err, strings.Join(w.conditions, ", "))
+}
+
 // JSONPathWait holds a JSONPath Parser which has the ability
 // to check for the JSONPath condition and compare with the API server provided JSON output.
 type JSONPathWait struct {
-	jsonPathCondition string
-	jsonPathParser    *jsonpath.JSONPath
-	// errOut is written to if an error occurs
+	// jsonPathExpression is the expression parsed by jsonPathParser, for reporting
+	jsonPathExpression string
+	jsonPathCondition  string
+	// jsonPathOperator is one of "=", "!=", ">", ">=", "<", "<=" or "~=", or
+	// "exists", "absent" or "changed" which ignore jsonPathCondition. An empty operator is
+	// treated as "=". The operators comparing numbers may be prefixed with "#" to
+	// compare the length of the result instead.
+	jsonPathOperator string
+	// changes records the value first observed on every object when jsonPathOperator is
+	// "changed". It is shared by the copies of the JSONPathWait.
+	changes *changeTracker
+	// jsonPathRegexp is the compiled jsonPathCondition when jsonPathOperator is "~="
+	jsonPathRegexp *regexp.Regexp
+	jsonPathParser *jsonpath.JSONPath
+	// jsonPathValueParser, if set, reads the expected value from the object
+	// instead of using jsonPathCondition
+	jsonPathValueParser *jsonpath.JSONPath
+	// numeric is set for jsonpath-cmp conditions, which compare the value with the one read by
+	// jsonPathValueParser as numbers, whatever the operator, and fail if either is not a number
+	numeric bool
+	// expectedValues is jsonPathCondition split into the values it may match. When
+	// unset, it is split on every check.
+	expectedValues []string
+	// multiValue is set when jsonPathExpression selects several values
+	multiValue bool
+	// quantifier is conditionKindJSONPathAll or conditionKindJSONPathAny to compare every value
+	// the expression resolves to, which must then all or any of them meet the condition. When
+	// unset, the expression must resolve to a single value.
+	quantifier conditionKind
+	// compareAge is set when jsonPathCondition is of the form "age:30s". The value of the
+	// expression is then an RFC3339 timestamp, whose age is compared with age.
+	compareAge bool
+	age        time.Duration
+	// clock is used to measure the age of timestamps, and defaults to the real clock
+	clock clockwork.Clock
+	// ignoreCase compares values with strings.EqualFold
+	ignoreCase bool
+	// errOut is written to if an error occurs. It defaults to the ErrOut of the WaitOptions.
 	errOut io.Writer
 }
 
 // IsJSONPathConditionMet fulfills the requirements of the interface ConditionFunc which provides condition check
-func (j JSONPathWait) IsJSONPathConditionMet(info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
-	return getObjAndCheckCondition(info, o, j.isJSONPathConditionMet, j.checkCondition)
+func (j JSONPathWait) IsJSONPathConditionMet(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+	if j.errOut == nil {
+		j.errOut = o.ErrOut
+	}
+	j.clock = o.clock()
+	condMet := eventCondition(j.errOut, "the condition to be satisfied", false, j.checkCondition)
+	obj, done, err := getObjAndCheckCondition(ctx, info, o, condMet, j.checkCondition, j.observedValue, j.describe)
+	if err != nil && j.ignoreCase {
+		err = fmt.Errorf("%w (case-insensitive match)", err)
+	}
+	return obj, done, err
 }
 
-// isJSONPathConditionMet is a helper function of IsJSONPathConditionMet
-// which check the watch event and check if a JSONPathWait condition is met
-func (j JSONPathWait) isJSONPathConditionMet(event watch.Event) (bool, error) {
-	if event.Type == watch.Error {
-		// keep waiting in the event we see an error - we expect the watch to be closed by
-		// the server
-		err := apierrors.FromObject(event.Object)
-		fmt.Fprintf(j.errOut, "error: An error occurred while waiting for the condition to be satisfied: %v", err)
-		return false, nil
+// observedValue returns the values the JSONPath expression resolves to on the object
+func (j JSONPathWait) observedValue(obj *unstructured.Unstructured) string {
+	parseResults, err := j.jsonPathParser.FindResults(obj.UnstructuredContent())
+	if err != nil {
+		return ""
 	}
-	if event.Type == watch.Deleted {
-		// this will chain back out, result in another get and an return false back up the chain
-		return false, nil
+	if isLengthOperator(j.jsonPathOperator) {
+		length, err := resultsLength(parseResults, isMultiValueExpression(j.jsonPathExpression))
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("length %d", length)
+	}
+	var values []string
+	for _, result := range parseResults {
+		for _, r := range result {
+			if r.IsValid() && r.CanInterface() {
+				values = append(values, fmt.Sprintf("%v", r.Interface()))
+			}
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+// describe explains how the value of the JSONPath expression is waited on to compare
+func (j JSONPathWait) describe(observed string) string {
+	expectation := ""
+	switch j.jsonPathOperator {
+	case "exists":
+		expectation = "to exist"
+	case "absent":
+		expectation = "to be absent"
+	case "changed":
+		expectation = "to change"
+	case "~=":
+		expectation = fmt.Sprintf("to match %s", j.jsonPathCondition)
+	case "", "=":
+		expectation = fmt.Sprintf("to be %s", j.jsonPathCondition)
+	case "*=":
+		expectation = fmt.Sprintf("to contain %s", j.jsonPathCondition)
+	case "^=":
+		expectation = fmt.Sprintf("to start with %s", j.jsonPathCondition)
+	case "$=":
+		expectation = fmt.Sprintf("to end with %s", j.jsonPathCondition)
+	case "#=":
+		expectation = fmt.Sprintf("to have length %s", j.jsonPathCondition)
+	case "#!=", "#>", "#>=", "#<", "#<=":
+		expectation = fmt.Sprintf("to have length %s %s", j.jsonPathOperator[1:], j.jsonPathCondition)
+	case ">", ">=", "<", "<=":
+		if j.compareAge {
+			expectation = fmt.Sprintf("to have an age %s %v", j.jsonPathOperator, j.age)
+			break
+		}
+		expectation = fmt.Sprintf("to be %s %s", j.jsonPathOperator, j.jsonPathCondition)
+	default:
+		expectation = fmt.Sprintf("to be %s %s", j.jsonPathOperator, j.jsonPathCondition)
+	}
+	switch j.quantifier {
+	case conditionKindJSONPathAll:
+		return fmt.Sprintf("every value of %s (last observed: %s) %s", j.jsonPathExpression, observed, expectation)
+	case conditionKindJSONPathAny:
+		return fmt.Sprintf("any value of %s (last observed: %s) %s", j.jsonPathExpression, observed, expectation)
 	}
-	// event runtime Object can be safely asserted to Unstructed
-	// because we are working with dynamic client
-	obj := event.Object.(*unstructured.Unstructured)
-	return j.checkCondition(obj)
+	return fmt.Sprintf("%s (last observed: %s) %s", j.jsonPathExpression, observed, expectation)
 }
 
 // checkCondition uses JSONPath parser to parse the JSON received from the API server
 // and check if it matches the desired condition
 func (j JSONPathWait) checkCondition(obj *unstructured.Unstructured) (bool, error) {
 	queryObj := obj.UnstructuredContent()
-	parseResults, err := j.jsonPathParser.FindResults(queryObj)
+	parseResults, err := findResults(j.jsonPathParser, queryObj)
 	if err != nil {
 		return false, err
 	}
-	if err := verifyParsedJSONPath(parseResults); err != nil {
-		return false, err
+	if isExistenceOperator(j.jsonPathOperator) {
+		return hasNonEmptyResult(parseResults) == (j.jsonPathOperator == "exists"), nil
+	}
+	if j.changes != nil {
+		return j.changes.changed(obj, j.observedValue(obj)), nil
+	}
+	if isLengthOperator(j.jsonPathOperator) {
+		length, err := resultsLength(parseResults, j.multiValue)
+		if err != nil {
+			return false, err
+		}
+		expected := strings.TrimSpace(j.jsonPathCondition)
+		if len(j.expectedValues) > 0 {
+			expected = j.expectedValues[0]
+		}
+		return compareNumbers(strconv.Itoa(length), j.jsonPathOperator[1:], expected)
+	}
+	if !isResolved(parseResults) {
+		// the expression does not resolve yet, keep waiting. In particular, jsonpath-all is
+		// not met by an expression which resolves to no value at all.
+		return false, nil
+	}
+	var results []reflect.Value
+	if len(j.quantifier) == 0 {
+		if err := verifyParsedJSONPath(parseResults); err != nil {
+			return false, err
+		}
+		results = parseResults[0]
+	} else {
+		for _, result := range parseResults {
+			results = append(results, result...)
+		}
+	}
+	expectedVal := ""
+	if j.jsonPathValueParser != nil {
+		valueResults, err := findResults(j.jsonPathValueParser, queryObj)
+		if err != nil {
+			return false, err
+		}
+		if !isResolved(valueResults) {
+			// the expected value does not resolve yet, keep waiting
+			return false, nil
+		}
+		if err := verifyParsedJSONPath(valueResults); err != nil {
+			return false, err
+		}
+		if expectedVal, err = resultString(valueResults[0][0]); err != nil {
+			return false, err
+		}
+	}
+	// a single value is met as with jsonpath-all
+	anyMet := j.quantifier == conditionKindJSONPathAny
+	for _, r := range results {
+		isConditionMet, err := j.compareResult(r, expectedVal)
+		if err != nil {
+			return false, err
+		}
+		if isConditionMet == anyMet {
+			return anyMet, nil
+		}
+	}
+	return !anyMet, nil
+}
+
+// compareResult compares one value the expression resolved to with the condition. expectedVal
+// is the value of jsonPathValueParser, when it is set.
+func (j JSONPathWait) compareResult(r reflect.Value, expectedVal string) (bool, error) {
+	switch {
+	case j.jsonPathRegexp != nil:
+		return matchResults(r, j.jsonPathRegexp)
+	case j.compareAge:
+		return j.compareTimestampAge(r)
+	case j.jsonPathValueParser != nil:
+		s, err := resultString(r)
+		if err != nil {
+			return false, err
+		}
+		if j.numeric {
+			return j.compareNumbers(s, expectedVal)
+		}
+		return compareValues(s, j.jsonPathOperator, []string{expectedVal}, j.ignoreCase)
+	}
+	expectedVals := j.expectedValues
+	if expectedVals == nil {
+		expectedVals = expectedValues(j.jsonPathCondition)
+	}
+	return compareResults(r, j.jsonPathOperator, expectedVals, j.ignoreCase)
+}
+
+// compareNumbers compares the value of the JSONPath expression with the one of the expression it
+// is compared with, for a jsonpath-cmp condition
+func (j JSONPathWait) compareNumbers(observedVal, expectedVal string) (bool, error) {
+	if _, ok := parseNumber(observedVal); !ok {
+		return false, fmt.Errorf("jsonpath value %q of %s is not a number", observedVal, j.jsonPathExpression)
+	}
+	if _, ok := parseNumber(expectedVal); !ok {
+		return false, fmt.Errorf("jsonpath value %q of %s is not a number", expectedVal, j.jsonPathCondition)
+	}
+	return compareNumbers(observedVal, j.jsonPathOperator, expectedVal)
+}
+
+// changeTracker records the value a JSONPath expression resolved to the first time every object
+// was checked, so that the "changed" operator is met once a later check sees another value
+type changeTracker struct {
+	mu      sync.Mutex
+	initial map[string]string
+}
+
+// changed records value as the initial value on obj the first time obj is checked, which never
+// meets the condition, and afterwards returns true if value differs from it. Objects are told
+// apart by kind, namespace and name, so that a recreated object is compared with the one it
+// replaced.
+func (t *changeTracker) changed(obj *unstructured.Unstructured, value string) bool {
+	key := fmt.Sprintf("%s/%s/%s", obj.GroupVersionKind().GroupKind(), obj.GetNamespace(), obj.GetName())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	initial, ok := t.initial[key]
+	if !ok {
+		t.initial[key] = value
+		return false
 	}
-	isConditionMet, err := compareResults(parseResults[0][0], j.jsonPathCondition)
+	return value != initial
+}
+
+// compareTimestampAge compares the age of the RFC3339 timestamp from the result parsed by the
+// JSONPath parser with j.age
+func (j JSONPathWait) compareTimestampAge(r reflect.Value) (bool, error) {
+	s, err := resultString(r)
 	if err != nil {
 		return false, err
 	}
-	return isConditionMet, nil
+	timestamp, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return false, fmt.Errorf("jsonpath value %q is not an RFC3339 timestamp, so its age cannot be compared with %s", s, j.jsonPathCondition)
+	}
+	clock := j.clock
+	if clock == nil {
+		clock = clockwork.NewRealClock()
+	}
+	age := clock.Since(timestamp)
+	switch j.jsonPathOperator {
+	case ">":
+		return age > j.age, nil
+	case ">=":
+		return age >= j.age, nil
+	case "<":
+		return age < j.age, nil
+	case "<=":
+		return age <= j.age, nil
+	}
+	return false, fmt.Errorf("unsupported jsonpath operator %q for an age", j.jsonPathOperator)
+}
+
+// findResults runs the JSONPath parser against the object. Missing keys are
+// already tolerated by the parser, but indexing past the end of a list that has
+// not been populated yet is reported as an error, so it is treated as no result.
+func findResults(j *jsonpath.JSONPath, queryObj map[string]interface{}) ([][]reflect.Value, error) {
+	parseResults, err := j.FindResults(queryObj)
+	if err != nil {
+		if !strings.Contains(err.Error(), "array index out of bounds") {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return parseResults, nil
+}
+
+// resultsLength returns the number of values in the results parsed by the JSONPath parser.
+// Unless the expression selects several values, it must resolve to a single list, whose
+// length is returned. An expression which does not resolve has a length of 0.
+func resultsLength(results [][]reflect.Value, multiValue bool) (int, error) {
+	length := 0
+	for _, result := range results {
+		length += len(result)
+	}
+	if multiValue || length == 0 {
+		return length, nil
+	}
+	if length > 1 {
+		return 0, errors.New("given jsonpath expression matches more than one value, use [*] to count the values it selects")
+	}
+	r := results[0][0]
+	if !r.IsValid() || r.Interface() == nil {
+		return 0, nil
+	}
+	switch v := r.Interface().(type) {
+	case []interface{}:
+		return len(v), nil
+	case map[string]interface{}:
+		return 0, errors.New("jsonpath length operators can only be used with a list, but the expression leads to an object")
+	}
+	return 0, fmt.Errorf("jsonpath length operators can only be used with a list, but the expression leads to the value %v", r.Interface())
+}
+
+// isResolved reports whether the results parsed by the JSONPath parser contain
+// at least one value
+func isResolved(results [][]reflect.Value) bool {
+	return len(results) > 1 || (len(results) == 1 && len(results[0]) > 0)
+}
+
+// hasNonEmptyResult returns true if any of the results parsed by the JSONPath
+// parser is a non-empty value
+func hasNonEmptyResult(results [][]reflect.Value) bool {
+	for _, result := range results {
+		for _, r := range result {
+			if !r.IsValid() || r.Interface() == nil {
+				continue
+			}
+			switch v := r.Interface().(type) {
+			case string:
+				if len(v) > 0 {
+					return true
+				}
+			case map[string]interface{}:
+				if len(v) > 0 {
+					return true
+				}
+			case []interface{}:
+				if len(v) > 0 {
+					return true
+				}
+			default:
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // verifyParsedJSONPath verifies the JSON received from the API server is valid.
@@ -615,17 +4729,195 @@ func verifyParsedJSONPath(results [][]reflect.Value) error {
 }
 
 // compareResults will compare the reflect.Value from the result parsed by the
-// JSONPath parser with the expected value given by the value
+// JSONPath parser with the expected values, using operator
 //
 // Since this is coming from an unstructured this can only ever be a primitive,
 // map[string]interface{}, or []interface{}.
 // We do not support the last two and rely on fmt to handle conversion to string
-// and compare the result with user input
-func compareResults(r reflect.Value, expectedVal string) (bool, error) {
+// and compare the result with user input. Booleans are compared as booleans by
+// "=" and "!=", so that =True matches true.
+func compareResults(r reflect.Value, operator string, expectedVals []string, ignoreCase bool) (bool, error) {
+	if observed, ok := r.Interface().(bool); ok && (operator == "=" || operator == "!=") {
+		return compareBools(observed, operator, expectedVals), nil
+	}
+	s, err := resultString(r)
+	if err != nil {
+		return false, err
+	}
+	return compareValues(s, operator, expectedVals, ignoreCase)
+}
+
+// compareValues compares the observed value with the expected ones using operator.
+// Numeric operators compare against the first expected value, while "=" and "!="
+// check whether the observed value is any of them, ignoring case if ignoreCase is set.
+func compareValues(observedVal, operator string, expectedVals []string, ignoreCase bool) (bool, error) {
+	if isNumericOperator(operator) {
+		if len(expectedVals) == 0 {
+			return false, errors.New("jsonpath wait condition cannot be empty")
+		}
+		return compareNumbers(observedVal, operator, expectedVals[0])
+	}
+	if isStringOperator(operator) {
+		return matchesPart(observedVal, operator, expectedVals, ignoreCase), nil
+	}
+	matched := false
+	for _, v := range expectedVals {
+		if observedVal == v || (ignoreCase && strings.EqualFold(observedVal, v)) {
+			matched = true
+			break
+		}
+	}
+	if operator == "!=" {
+		return !matched, nil
+	}
+	return matched, nil
+}
+
+// compareBools compares a boolean value with the expected ones, which are parsed with
+// strconv.ParseBool so that True and 1 match true. Expected values which are not booleans
+// never match.
+func compareBools(observedVal bool, operator string, expectedVals []string) bool {
+	matched := false
+	for _, v := range expectedVals {
+		if expected, err := strconv.ParseBool(v); err == nil && expected == observedVal {
+			matched = true
+			break
+		}
+	}
+	if operator == "!=" {
+		return !matched
+	}
+	return matched
+}
+
+// matchesPart reports whether the observed value contains, starts with or ends with any of
+// the expected values, depending on the string operator
+func matchesPart(observedVal, operator string, expectedVals []string, ignoreCase bool) bool {
+	if ignoreCase {
+		observedVal = strings.ToLower(observedVal)
+	}
+	for _, v := range expectedVals {
+		if ignoreCase {
+			v = strings.ToLower(v)
+		}
+		switch {
+		case operator == "*=" && strings.Contains(observedVal, v):
+			return true
+		case operator == "^=" && strings.HasPrefix(observedVal, v):
+			return true
+		case operator == "$=" && strings.HasSuffix(observedVal, v):
+			return true
+		}
+	}
+	return false
+}
+
+// matchResults reports whether the string form of the reflect.Value from the
+// result parsed by the JSONPath parser matches the regular expression
+func matchResults(r reflect.Value, re *regexp.Regexp) (bool, error) {
+	s, err := resultString(r)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+// resultString converts a primitive reflect.Value from the result parsed by the
+// JSONPath parser to the string it is compared as
+func resultString(r reflect.Value) (string, error) {
 	switch r.Interface().(type) {
 	case map[string]interface{}, []interface{}:
-		return false, errors.New("jsonpath leads to a nested object or list which is not supported")
+		return "", errors.New("jsonpath leads to a nested object or list which is not supported")
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", r.Interface())), nil
+}
+
+// expectedValues splits a jsonpath wait condition of the form "a|b|c" or "a,b,c" into
+// the set of values any of which satisfies the condition. Empty members are ignored,
+// so "Running||Succeeded" and "Running|" are equivalent to "Running|Succeeded"
+// and "Running".
+func expectedValues(expectedVal string) []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(expectedVal, isValueSeparator) {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// isValueSeparator returns true for the runes separating the values of a jsonpath wait condition
+func isValueSeparator(r rune) bool {
+	return r == '|' || r == ','
+}
+
+// compareNumbers parses the observed and expected values as numbers and compares them using
+// one of the numeric operators. Integers are compared as integers, so that large ones keep
+// their precision, and any other numbers, such as 0.75 or 1e3, as floating-point numbers,
+// whether they were reported as JSON numbers or as strings.
+func compareNumbers(observedVal, operator, expectedVal string) (bool, error) {
+	observed, err := strconv.ParseInt(strings.TrimSpace(observedVal), 10, 64)
+	expected, expectedErr := strconv.ParseInt(strings.TrimSpace(expectedVal), 10, 64)
+	if err == nil && expectedErr == nil {
+		return compareOrdered(compareInts(observed, expected), operator)
+	}
+	observedFloat, ok := parseNumber(observedVal)
+	if !ok {
+		return false, fmt.Errorf("jsonpath value %q is not a number and cannot be compared with %q", observedVal, operator)
+	}
+	expectedFloat, ok := parseNumber(expectedVal)
+	if !ok {
+		return false, fmt.Errorf("jsonpath wait condition %q is not a number", expectedVal)
+	}
+	return compareOrdered(compareFloats(observedFloat, expectedFloat), operator)
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// parseNumber parses a finite number, either an integer or a floating-point number
+func parseNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, false
+	}
+	return n, true
+}
+
+// compareOrdered tells whether an observed value which compares with the expected one as cmp
+// does, -1 if it is lower, 0 if it is equal or 1 if it is greater, satisfies one of the numeric
+// operators
+func compareOrdered(cmp int, operator string) (bool, error) {
+	switch operator {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
 	}
-	s := fmt.Sprintf("%v", r.Interface())
-	return strings.TrimSpace(s) == strings.TrimSpace(expectedVal), nil
+	return false, fmt.Errorf("unsupported jsonpath operator %q", operator)
 }
@@ -17,14 +17,34 @@ limitations under the License.
 package wait
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/jonboulle/clockwork"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -36,8 +56,15 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	restclient "k8s.io/client-go/rest"
 	clienttesting "k8s.io/client-go/testing"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	utilexec "k8s.io/utils/exec"
+	"k8s.io/utils/pointer"
 )
 
 const (
@@ -224,9 +251,20 @@ func addConditionWithObservedGeneration(in *unstructured.Unstructured, name, sta
 	return in
 }
 
+func addConditionWithReason(in *unstructured.Unstructured, name, status, reason string) *unstructured.Unstructured {
+	conditions, _, _ := unstructured.NestedSlice(in.Object, "status", "conditions")
+	conditions = append(conditions, map[string]interface{}{
+		"type":   name,
+		"status": status,
+		"reason": reason,
+	})
+	unstructured.SetNestedSlice(in.Object, conditions, "status", "conditions")
+	return in
+}
+
 // createUnstructured parses the yaml string into a map[string]interface{}.  Verifies that the string does not have
 // any tab characters.
-func createUnstructured(t *testing.T, config string) *unstructured.Unstructured {
+func createUnstructured(t testing.TB, config string) *unstructured.Unstructured {
 	t.Helper()
 	result := map[string]interface{}{}
 
@@ -787,6 +825,68 @@ func TestWaitForCondition(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "handles expired watch by listing again",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "name-foo",
+					Namespace: "ns-foo",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				listCount := 0
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					listCount++
+					unstructuredList := newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"))
+					unstructuredList.SetResourceVersion(fmt.Sprintf("%d", 100*listCount))
+					return true, unstructuredList, nil
+				})
+				watchCount := 0
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					watchCount++
+					fakeWatch := watch.NewRaceFreeFake()
+					if watchCount == 1 {
+						fakeWatch.Error(newUnstructuredStatus(&metav1.Status{
+							Status:  "Failure",
+							Code:    410,
+							Reason:  metav1.StatusReasonExpired,
+							Message: "too old resource version",
+						}))
+						fakeWatch.Stop()
+						return true, fakeWatch, nil
+					}
+					fakeWatch.Action(watch.Modified, addCondition(
+						newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+						"the-condition", "status-value",
+					))
+					return true, fakeWatch, nil
+				})
+				return fakeClient
+			},
+			timeout: 10 * time.Second,
+
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 4 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") || actions[1].(clienttesting.WatchAction).GetWatchRestrictions().ResourceVersion != "100" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[2].Matches("list", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[3].Matches("watch", "theresource") || actions[3].(clienttesting.WatchAction).GetWatchRestrictions().ResourceVersion != "200" {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
 		{
 			name: "handles watch condition change",
 			infos: []*resource.Info{
@@ -1134,189 +1234,185 @@ func TestWaitForDeletionIgnoreNotFound(t *testing.T) {
 	}
 }
 
-// TestWaitForDifferentJSONPathCondition will run tests on different types of
-// JSONPath expression to check the JSONPath can be parsed correctly from a Pod Yaml
-// and check if the comparison returns as expected.
-func TestWaitForDifferentJSONPathExpression(t *testing.T) {
+func TestWaitForAllConditions(t *testing.T) {
 	scheme := runtime.NewScheme()
 	listMapping := map[schema.GroupVersionResource]string{
 		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
 	}
-	listReactionfunc := func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-		return true, newUnstructuredList(createUnstructured(t, podYAML)), nil
-	}
 	infos := []*resource.Info{
 		{
 			Mapping: &meta.RESTMapping{
 				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
 			},
-			Name:      "foo-b6699dcfb-rnv7t",
-			Namespace: "default",
+			Name:      "name-foo",
+			Namespace: "ns-foo",
 		},
 	}
+	listReactionfunc := func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		obj := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "condition-a", "True")
+		obj.SetResourceVersion("123")
+		return true, newUnstructuredList(obj), nil
+	}
 
 	tests := []struct {
-		name         string
-		fakeClient   func() *dynamicfakeclient.FakeDynamicClient
-		jsonPathExp  string
-		jsonPathCond string
+		name       string
+		conditions []string
 
 		expectedErr string
 	}{
 		{
-			name: "JSONPath entry not exist",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.foo.bar}",
-			jsonPathCond: "baz",
-
-			expectedErr: "foo is not found",
+			name:       "all conditions met",
+			conditions: []string{"condition=condition-a", "jsonpath={.metadata.name}=name-foo"},
 		},
 		{
-			name: "compare boolean JSONPath entry",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.status.containerStatuses[0].ready}",
-			jsonPathCond: "true",
+			name:       "one condition unmet",
+			conditions: []string{"condition=condition-a", "condition=condition-b"},
 
-			expectedErr: None,
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition condition-b (last observed: <none>) to be true (unsatisfied condition: condition=condition-b)",
 		},
-		{
-			name: "compare boolean JSONPath entry wrong value",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.status.containerStatuses[0].ready}",
-			jsonPathCond: "false",
+	}
 
-			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
-		},
-		{
-			name: "compare integer JSONPath entry",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
-			jsonPathCond: "80",
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+			conditionFn, err := allConditionsFuncFor(test.conditions, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        1 * time.Second,
 
-			expectedErr: None,
-		},
-		{
-			name: "compare integer JSONPath entry wrong value",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
-			jsonPathCond: "81",
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err = o.RunWait()
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
 
-			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+func TestWaitForAllConditionsChanging(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	info := &resource.Info{
+		Mapping: &meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
 		},
-		{
-			name: "compare string JSONPath entry",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.spec.nodeName}",
-			jsonPathCond: "knode0",
+		Name:      "name-foo",
+		Namespace: "ns-foo",
+	}
+	// the resource changes on every list, so the conditions never agree on one version of it
+	var lists int32
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		obj := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "condition-a", "True")
+		obj.SetResourceVersion(fmt.Sprintf("%d", atomic.AddInt32(&lists, 1)))
+		return true, newUnstructuredList(obj), nil
+	})
+	conditionFn, err := allConditionsFuncFor([]string{"condition=condition-a", "jsonpath={.metadata.name}=name-foo"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClock := clockwork.NewFakeClock()
+	o := &WaitOptions{
+		DynamicClient: fakeClient,
+		Timeout:       time.Minute,
+		Clock:         fakeClock,
+		IOStreams:     genericclioptions.NewTestIOStreamsDiscard(),
+	}
 
-			expectedErr: None,
-		},
-		{
-			name: "compare string JSONPath entry wrong value",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.spec.nodeName}",
-			jsonPathCond: "kmaster",
+	errCh := make(chan error)
+	go func() {
+		_, _, err := conditionFn(context.Background(), info, o)
+		errCh <- err
+	}()
+	// the next round waits for the next poll rather than listing again right away
+	fakeClock.BlockUntil(1)
+	if n := atomic.LoadInt32(&lists); n != 2 {
+		t.Errorf("expected 2 lists before waiting for the next round, got %d", n)
+	}
+	fakeClock.Advance(time.Minute)
+	if err := <-errCh; err == nil || !strings.Contains(err.Error(), "timed out waiting for the condition on theresource/name-foo") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
 
-			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
-		},
+func TestWaitForAnyConditions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
 		{
-			name: "matches more than one value",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
 			},
-			jsonPathExp:  "{.status.conditions[*]}",
-			jsonPathCond: "foo",
-
-			expectedErr: "given jsonpath expression matches more than one value",
+			Name:      "name-foo",
+			Namespace: "ns-foo",
 		},
-		{
-			name: "matches more than one list",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{range .status.conditions[*]}[{.status}] {end}",
-			jsonPathCond: "foo",
+	}
+	listReactionfunc := func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		obj := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "condition-a", "True")
+		obj.SetResourceVersion("123")
+		return true, newUnstructuredList(obj), nil
+	}
 
-			expectedErr: "given jsonpath expression matches more than one list",
+	tests := []struct {
+		name       string
+		conditions []string
+
+		expectedErr string
+	}{
+		{
+			name:       "first condition met",
+			conditions: []string{"condition=condition-a", "condition=condition-b"},
 		},
 		{
-			name: "unsupported type []interface{}",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
-				return fakeClient
-			},
-			jsonPathExp:  "{.status.conditions}",
-			jsonPathCond: "True",
-
-			expectedErr: "jsonpath leads to a nested object or list which is not supported",
+			name:       "last condition met",
+			conditions: []string{"condition=condition-b", "jsonpath={.metadata.name}=name-foo"},
 		},
 		{
-			name: "unsupported type map[string]interface{}",
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, newUnstructuredList(createUnstructured(t, podYAML)), nil
-				})
-				return fakeClient
-			},
-			jsonPathExp:  "{.spec}",
-			jsonPathCond: "foo",
+			name:       "no condition met",
+			conditions: []string{"condition=condition-b", "condition=condition-c"},
 
-			expectedErr: "jsonpath leads to a nested object or list which is not supported",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition condition-b (last observed: <none>) to be true (unsatisfied conditions: condition=condition-b, condition=condition-c)",
 		},
 	}
+
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fakeClient := test.fakeClient()
-			j, _ := newJSONPathParser(test.jsonPathExp)
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+			conditionFn, err := anyConditionsFuncFor(test.conditions, false)
+			if err != nil {
+				t.Fatal(err)
+			}
 			o := &WaitOptions{
 				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
 				DynamicClient:  fakeClient,
-				Timeout:        1 * time.Millisecond,
+				Timeout:        1 * time.Second,
 
-				Printer: printers.NewDiscardingPrinter(),
-				ConditionFn: JSONPathWait{
-					jsonPathCondition: test.jsonPathCond,
-					jsonPathParser:    j,
-					errOut:            ioutil.Discard}.IsJSONPathConditionMet,
-				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
 			}
-
-			err := o.RunWait()
-
+			err = o.RunWait()
 			switch {
 			case err == nil && len(test.expectedErr) == 0:
 			case err != nil && len(test.expectedErr) == 0:
@@ -1332,54 +1428,388 @@ func TestWaitForDifferentJSONPathExpression(t *testing.T) {
 	}
 }
 
-// TestWaitForJSONPathCondition will run tests to check whether
-// the List actions and Watch actions match what we expected
-func TestWaitForJSONPathCondition(t *testing.T) {
+// TestWaitForAnyConditionsProgress checks, when run with -race, that the conditions waited on at
+// once do not write to the ProgressWriter concurrently when the ConditionFunc is called directly.
+func TestWaitForAnyConditionsProgress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	info := &resource.Info{
+		Mapping: &meta.RESTMapping{
+			Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+		},
+		Name:      "name-foo",
+		Namespace: "ns-foo",
+	}
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		obj := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "condition-a", "True")
+		obj.SetResourceVersion("123")
+		return true, newUnstructuredList(obj), nil
+	})
+	conditionFn, err := anyConditionsFuncFor([]string{"condition=condition-a", "condition=condition-a=True", "jsonpath={.metadata.name}=name-foo"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress := &bytes.Buffer{}
+	o := &WaitOptions{
+		DynamicClient:  fakeClient,
+		Timeout:        time.Minute,
+		ProgressWriter: progress,
+		IOStreams:      genericclioptions.NewTestIOStreamsDiscard(),
+	}
+
+	if _, done, err := conditionFn(context.Background(), info, o); err != nil || !done {
+		t.Fatalf("expected the condition to be met, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(progress.String()), "\n")
+	for _, line := range lines {
+		event := ProgressEvent{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Errorf("expected a progress event, got %q: %v", line, err)
+		}
+	}
+}
+
+func TestWaitPollInterval(t *testing.T) {
 	scheme := runtime.NewScheme()
 	listMapping := map[schema.GroupVersionResource]string{
 		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
 	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
 
 	tests := []struct {
 		name         string
-		infos        []*resource.Info
-		fakeClient   func() *dynamicfakeclient.FakeDynamicClient
+		conditionFn  ConditionFunc
 		timeout      time.Duration
-		jsonPathExp  string
-		jsonPathCond string
+		pollInterval time.Duration
+
+		expectedLists int
+	}{
+		{
+			name:          "condition polls until timeout",
+			conditionFn:   ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+			timeout:       30 * time.Second,
+			pollInterval:  5 * time.Second,
+			expectedLists: 7,
+		},
+		{
+			name:          "deletion polls until timeout",
+			conditionFn:   IsDeleted,
+			timeout:       30 * time.Second,
+			pollInterval:  10 * time.Second,
+			expectedLists: 4,
+		},
+		{
+			name:          "last poll is at the timeout",
+			conditionFn:   ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+			timeout:       25 * time.Second,
+			pollInterval:  10 * time.Second,
+			expectedLists: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")), nil
+			})
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+				PollInterval:   test.pollInterval,
+				Clock:          fakeClock,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: test.conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			errCh := make(chan error)
+			go func() {
+				errCh <- o.RunWait()
+			}()
+			var err error
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case err = <-errCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(test.pollInterval)
+				}
+			}
+
+			if err == nil || !strings.Contains(err.Error(), "timed out waiting for the condition on theresource/name-foo") {
+				t.Fatalf("expected timeout, got %v", err)
+			}
+			for _, action := range fakeClient.Actions() {
+				if action.Matches("watch", "theresource") {
+					t.Errorf("unexpected watch when polling: %s", spew.Sdump(action))
+				}
+			}
+			if len(fakeClient.Actions()) != test.expectedLists {
+				t.Errorf("expected %d lists, got %d", test.expectedLists, len(fakeClient.Actions()))
+			}
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *WaitOptions
+
+		expectedIntervals []time.Duration
+	}{
+		{
+			name:              "constant interval",
+			options:           &WaitOptions{PollInterval: 2 * time.Second},
+			expectedIntervals: []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:              "backoff takes precedence over the constant interval",
+			options:           &WaitOptions{PollInterval: 2 * time.Second, BackoffInitial: time.Second},
+			expectedIntervals: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:              "backoff is capped",
+			options:           &WaitOptions{BackoffInitial: time.Second, BackoffMax: 5 * time.Second},
+			expectedIntervals: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, expected := range test.expectedIntervals {
+				if actual := test.options.pollInterval(i + 1); actual != expected {
+					t.Errorf("poll %d: expected %v, got %v", i+1, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestPollIntervalDoesNotOverflow(t *testing.T) {
+	o := &WaitOptions{BackoffInitial: time.Second}
+	for polls := 1; polls < 100; polls++ {
+		if actual := o.pollInterval(polls); actual < o.BackoffInitial {
+			t.Fatalf("poll %d: unexpected interval %v", polls, actual)
+		}
+	}
+}
+
+func TestPollJitter(t *testing.T) {
+	const samples = 1000
+	jitter := 400 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		options  WaitOptions
+		delay    func(o *WaitOptions) time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "poll interval",
+			options:  WaitOptions{PollInterval: time.Second, PollJitter: jitter},
+			delay:    func(o *WaitOptions) time.Duration { return o.pollInterval(3) },
+			expected: time.Second,
+		},
+		{
+			name:     "backoff",
+			options:  WaitOptions{BackoffInitial: time.Second, BackoffMax: 2 * time.Second, PollJitter: jitter},
+			delay:    func(o *WaitOptions) time.Duration { return o.pollInterval(5) },
+			expected: 2 * time.Second,
+		},
+		{
+			name:     "first check",
+			options:  WaitOptions{PollInterval: time.Second, InitialDelay: time.Second, PollJitter: jitter},
+			delay:    func(o *WaitOptions) time.Duration { return o.firstCheckDelay() },
+			expected: time.Second,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := test.options
+			lowest, highest := time.Duration(math.MaxInt64), time.Duration(0)
+			for i := 0; i < samples; i++ {
+				delay := test.delay(&o)
+				if delay < test.expected || delay >= test.expected+jitter {
+					t.Fatalf("expected between %v and %v, got %v", test.expected, test.expected+jitter, delay)
+				}
+				if delay < lowest {
+					lowest = delay
+				}
+				if delay > highest {
+					highest = delay
+				}
+			}
+			// the offsets are spread over the whole bound rather than bunched together
+			if lowest >= test.expected+jitter/4 || highest < test.expected+jitter*3/4 {
+				t.Errorf("expected the delays to spread between %v and %v, got %v to %v", test.expected, test.expected+jitter, lowest, highest)
+			}
+		})
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		o := &WaitOptions{PollInterval: time.Second, InitialDelay: time.Second}
+		if delay := o.pollInterval(1); delay != time.Second {
+			t.Errorf("expected an interval of 1s, got %v", delay)
+		}
+		if delay := o.firstCheckDelay(); delay != time.Second {
+			t.Errorf("expected a first check after 1s, got %v", delay)
+		}
+	})
+
+	t.Run("check now", func(t *testing.T) {
+		o := &WaitOptions{PollJitter: jitter, CheckNow: true}
+		if delay := o.firstCheckDelay(); delay != 0 {
+			t.Errorf("expected the check not to be delayed, got %v", delay)
+		}
+	})
+}
+
+func TestPollIntervalJitter(t *testing.T) {
+	o := &WaitOptions{BackoffInitial: time.Second, BackoffMax: 4 * time.Second, BackoffJitter: 0.5}
+	for polls := 1; polls < 100; polls++ {
+		base := o.BackoffInitial << uint(polls-1)
+		if polls > 3 {
+			base = o.BackoffMax
+		}
+		if actual := o.pollInterval(polls); actual < base || actual > base+base/2 {
+			t.Errorf("poll %d: expected between %v and %v, got %v", polls, base, base+base/2, actual)
+		}
+	}
+}
+
+func TestWaitContextCancellation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		conditionFn  ConditionFunc
+		pollInterval time.Duration
+	}{
+		{
+			name:        "condition watch",
+			conditionFn: ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+		},
+		{
+			name:         "condition poll",
+			conditionFn:  ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+			pollInterval: time.Second,
+		},
+		{
+			name:        "deletion watch",
+			conditionFn: IsDeleted,
+		},
+		{
+			name:         "deletion poll",
+			conditionFn:  IsDeleted,
+			pollInterval: time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")), nil
+			})
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        time.Minute,
+				PollInterval:   test.pollInterval,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: test.conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			err := o.RunWaitContext(ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("wait was not cancelled promptly, took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestWaitForCreation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		infos      []*resource.Info
+		fakeClient func() *dynamicfakeclient.FakeDynamicClient
+		timeout    time.Duration
 
 		expectedErr     string
 		validateActions func(t *testing.T, actions []clienttesting.Action)
 	}{
 		{
-			name: "present on get",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
-					},
-					Name:      "foo-b6699dcfb-rnv7t",
-					Namespace: "default",
-				},
-			},
+			name:  "present on get",
+			infos: infos,
 			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
 				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
 				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, newUnstructuredList(
-						createUnstructured(t, podYAML)), nil
+					return true, newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")), nil
 				})
 				return fakeClient
 			},
-			timeout:      3 * time.Second,
-			jsonPathExp:  "{.metadata.name}",
-			jsonPathCond: "foo-b6699dcfb-rnv7t",
+			timeout: 10 * time.Second,
 
-			expectedErr: None,
 			validateActions: func(t *testing.T, actions []clienttesting.Action) {
 				if len(actions) != 1 {
 					t.Fatal(spew.Sdump(actions))
 				}
-				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=name-foo" {
 					t.Error(spew.Sdump(actions))
 				}
 			},
@@ -1400,53 +1830,45 @@ func TestWaitForJSONPathCondition(t *testing.T) {
 			},
 		},
 		{
-			name: "handles empty object name",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
-					},
-					Namespace: "default",
-				},
-			},
+			name:  "times out",
+			infos: infos,
 			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
 				return dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
 			},
-			timeout: 10 * time.Second,
+			timeout: 1 * time.Second,
 
-			expectedErr: "resource name must be provided",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
 			validateActions: func(t *testing.T, actions []clienttesting.Action) {
-				if len(actions) != 0 {
+				if len(actions) != 2 {
 					t.Fatal(spew.Sdump(actions))
 				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=name-foo" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
 			},
 		},
 		{
-			name: "times out",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
-					},
-					Name:      "foo-b6699dcfb-rnv7t",
-					Namespace: "default",
-				},
-			},
+			name:  "handles watch created",
+			infos: infos,
 			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
 				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, createUnstructured(t, podYAML), nil
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Action(watch.Added, newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"))
+					return true, fakeWatch, nil
 				})
 				return fakeClient
 			},
-			timeout: 1 * time.Second,
+			timeout: 10 * time.Second,
 
-			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
 			validateActions: func(t *testing.T, actions []clienttesting.Action) {
 				if len(actions) != 2 {
 					t.Fatal(spew.Sdump(actions))
 				}
-				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=name-foo" {
 					t.Error(spew.Sdump(actions))
 				}
 				if !actions[1].Matches("watch", "theresource") {
@@ -1454,30 +1876,578 @@ func TestWaitForJSONPathCondition(t *testing.T) {
 				}
 			},
 		},
-		{
-			name: "handles watch close out",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
-					},
-					Name:      "foo-b6699dcfb-rnv7t",
-					Namespace: "default",
-				},
-			},
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-					unstructuredObj := createUnstructured(t, podYAML)
-					unstructuredObj.SetResourceVersion("123")
-					unstructuredList := newUnstructuredList(unstructuredObj)
-					unstructuredList.SetResourceVersion("234")
-					return true, unstructuredList, nil
-				})
-				count := 0
-				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
-					if count == 0 {
-						count++
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := test.fakeClient()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+				ForCondition:   "create",
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: IsCreated,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err := o.RunWait()
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+
+			test.validateActions(t, fakeClient.Actions())
+		})
+	}
+}
+
+// TestWaitForDifferentJSONPathCondition will run tests on different types of
+// JSONPath expression to check the JSONPath can be parsed correctly from a Pod Yaml
+// and check if the comparison returns as expected.
+func TestWaitForDifferentJSONPathExpression(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	listReactionfunc := func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, newUnstructuredList(createUnstructured(t, podYAML)), nil
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "foo-b6699dcfb-rnv7t",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		fakeClient   func() *dynamicfakeclient.FakeDynamicClient
+		jsonPathExp  string
+		jsonPathOp   string
+		jsonPathCond string
+
+		expectedErr string
+	}{
+		{
+			name: "JSONPath entry not exist",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.foo.bar}",
+			jsonPathCond: "baz",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "JSONPath entry exists",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp: "{.status.podIPs[0].ip}",
+			jsonPathOp:  "exists",
+
+			expectedErr: None,
+		},
+		{
+			name: "JSONPath entry exists but entry not exist",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp: "{.status.podIPs[1].ip}",
+			jsonPathOp:  "exists",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "JSONPath entry absent",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp: "{.metadata.finalizers}",
+			jsonPathOp:  "absent",
+
+			expectedErr: None,
+		},
+		{
+			name: "JSONPath entry absent but entry exists",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp: "{.metadata.ownerReferences}",
+			jsonPathOp:  "absent",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare boolean JSONPath entry",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.containerStatuses[0].ready}",
+			jsonPathCond: "true",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare boolean JSONPath entry wrong value",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.containerStatuses[0].ready}",
+			jsonPathCond: "false",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare integer JSONPath entry",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
+			jsonPathCond: "80",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare integer JSONPath entry wrong value",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
+			jsonPathCond: "81",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare string JSONPath entry",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.nodeName}",
+			jsonPathCond: "knode0",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare string JSONPath entry wrong value",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.nodeName}",
+			jsonPathCond: "kmaster",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare integer JSONPath entry with greater than or equal",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
+			jsonPathOp:   ">=",
+			jsonPathCond: "80",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare integer JSONPath entry with greater than",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
+			jsonPathOp:   ">",
+			jsonPathCond: "80",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare integer JSONPath entry with less than",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.containers[0].ports[0].containerPort}",
+			jsonPathOp:   "<",
+			jsonPathCond: "443",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare string JSONPath entry with not equal",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.nodeName}",
+			jsonPathOp:   "!=",
+			jsonPathCond: "kmaster",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare string JSONPath entry with any of several values",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.phase}",
+			jsonPathCond: "Succeeded||Running",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare string JSONPath entry with any of several comma-separated values",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.phase}",
+			jsonPathCond: "Succeeded,Running",
+
+			expectedErr: None,
+		},
+		{
+			name: "compare string JSONPath entry with none of several values",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.phase}",
+			jsonPathCond: "Succeeded|Failed",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare string JSONPath entry not equal to any of several values",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.phase}",
+			jsonPathOp:   "!=",
+			jsonPathCond: "Pending|Running",
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "match string JSONPath entry with regular expression",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.nodeName}",
+			jsonPathOp:   "~=",
+			jsonPathCond: `^knode\d+$`,
+
+			expectedErr: None,
+		},
+		{
+			name: "match string JSONPath entry with regular expression wrong value",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.nodeName}",
+			jsonPathOp:   "~=",
+			jsonPathCond: `^kmaster`,
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+		},
+		{
+			name: "compare string JSONPath entry with numeric operator",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec.nodeName}",
+			jsonPathOp:   ">=",
+			jsonPathCond: "1",
+
+			expectedErr: `jsonpath value "knode0" is not a number`,
+		},
+		{
+			name: "matches more than one value",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.conditions[*]}",
+			jsonPathCond: "foo",
+
+			expectedErr: "given jsonpath expression matches more than one value",
+		},
+		{
+			name: "matches more than one list",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{range .status.conditions[*]}[{.status}] {end}",
+			jsonPathCond: "foo",
+
+			expectedErr: "given jsonpath expression matches more than one list",
+		},
+		{
+			name: "unsupported type []interface{}",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", listReactionfunc)
+				return fakeClient
+			},
+			jsonPathExp:  "{.status.conditions}",
+			jsonPathCond: "True",
+
+			expectedErr: "jsonpath leads to a nested object or list which is not supported",
+		},
+		{
+			name: "unsupported type map[string]interface{}",
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, newUnstructuredList(createUnstructured(t, podYAML)), nil
+				})
+				return fakeClient
+			},
+			jsonPathExp:  "{.spec}",
+			jsonPathCond: "foo",
+
+			expectedErr: "jsonpath leads to a nested object or list which is not supported",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := test.fakeClient()
+			j, _ := newJSONPathParser(test.jsonPathExp)
+			var re *regexp.Regexp
+			if test.jsonPathOp == "~=" {
+				re = regexp.MustCompile(test.jsonPathCond)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        1 * time.Millisecond,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: JSONPathWait{
+					jsonPathCondition: test.jsonPathCond,
+					jsonPathOperator:  test.jsonPathOp,
+					jsonPathRegexp:    re,
+					jsonPathParser:    j,
+					errOut:            ioutil.Discard}.IsJSONPathConditionMet,
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+// TestWaitForJSONPathCondition will run tests to check whether
+// the List actions and Watch actions match what we expected
+func TestWaitForJSONPathCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+
+	tests := []struct {
+		name         string
+		infos        []*resource.Info
+		fakeClient   func() *dynamicfakeclient.FakeDynamicClient
+		timeout      time.Duration
+		jsonPathExp  string
+		jsonPathCond string
+
+		expectedErr     string
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name: "present on get",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "foo-b6699dcfb-rnv7t",
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, newUnstructuredList(
+						createUnstructured(t, podYAML)), nil
+				})
+				return fakeClient
+			},
+			timeout:      3 * time.Second,
+			jsonPathExp:  "{.metadata.name}",
+			jsonPathCond: "foo-b6699dcfb-rnv7t",
+
+			expectedErr: None,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 1 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name:  "handles no infos",
+			infos: []*resource.Info{},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				return dynamicfakeclient.NewSimpleDynamicClient(scheme)
+			},
+			timeout:     10 * time.Second,
+			expectedErr: errNoMatchingResources.Error(),
+
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 0 {
+					t.Fatal(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name: "handles empty object name",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				return dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			},
+			timeout: 10 * time.Second,
+
+			expectedErr: "resource name must be provided",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 0 {
+					t.Fatal(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name: "times out",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "foo-b6699dcfb-rnv7t",
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, createUnstructured(t, podYAML), nil
+				})
+				return fakeClient
+			},
+			timeout: 1 * time.Second,
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 2 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name: "handles watch close out",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "foo-b6699dcfb-rnv7t",
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					unstructuredObj := createUnstructured(t, podYAML)
+					unstructuredObj.SetResourceVersion("123")
+					unstructuredList := newUnstructuredList(unstructuredObj)
+					unstructuredList.SetResourceVersion("234")
+					return true, unstructuredList, nil
+				})
+				count := 0
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					if count == 0 {
+						count++
 						fakeWatch := watch.NewRaceFreeFake()
 						go func() {
 							time.Sleep(100 * time.Millisecond)
@@ -1485,189 +2455,8027 @@ func TestWaitForJSONPathCondition(t *testing.T) {
 						}()
 						return true, fakeWatch, nil
 					}
-					fakeWatch := watch.NewRaceFreeFake()
-					return true, fakeWatch, nil
-				})
-				return fakeClient
+					fakeWatch := watch.NewRaceFreeFake()
+					return true, fakeWatch, nil
+				})
+				return fakeClient
+			},
+			timeout:      3 * time.Second,
+			jsonPathExp:  "{.metadata.name}",
+			jsonPathCond: "foo", // use incorrect name so it'll keep waiting
+
+			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 4 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") || actions[1].(clienttesting.WatchAction).GetWatchRestrictions().ResourceVersion != "234" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[2].Matches("list", "theresource") || actions[2].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[3].Matches("watch", "theresource") || actions[3].(clienttesting.WatchAction).GetWatchRestrictions().ResourceVersion != "234" {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name: "handles watch condition change",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "foo-b6699dcfb-rnv7t",
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					unstructuredObj := createUnstructured(t, podYAML)
+					unstructuredObj.SetName("foo")
+					return true, newUnstructuredList(), nil
+				})
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Action(watch.Modified, createUnstructured(t, podYAML))
+					return true, fakeWatch, nil
+				})
+				return fakeClient
+			},
+			timeout:      10 * time.Second,
+			jsonPathExp:  "{.metadata.name}",
+			jsonPathCond: "foo-b6699dcfb-rnv7t",
+
+			expectedErr: None,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 2 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name: "handles watch created",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "foo-b6699dcfb-rnv7t",
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Action(watch.Added, createUnstructured(t, podYAML))
+					return true, fakeWatch, nil
+				})
+				return fakeClient
+			},
+			timeout:      10 * time.Second,
+			jsonPathExp:  "{.spec.containers[0].image}",
+			jsonPathCond: "nginx",
+
+			expectedErr: None,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 2 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
+		{
+			name: "ignores watch error",
+			infos: []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+					},
+					Name:      "foo-b6699dcfb-rnv7t",
+					Namespace: "default",
+				},
+			},
+			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
+				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					unstructuredObj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+					unstructuredObj.SetUID(createUnstructured(t, podYAML).GetUID())
+					return true, newUnstructuredList(unstructuredObj), nil
+				})
+				count := 0
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					if count == 0 {
+						fakeWatch.Error(newUnstructuredStatus(&metav1.Status{
+							TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+							Status:   "Failure",
+							Code:     500,
+							Message:  "Bad",
+						}))
+						fakeWatch.Stop()
+					} else {
+						fakeWatch.Action(watch.Modified, createUnstructured(t, podYAML))
+					}
+					count++
+					return true, fakeWatch, nil
+				})
+				return fakeClient
+			},
+			timeout:      10 * time.Second,
+			jsonPathExp:  "{.metadata.name}",
+			jsonPathCond: "foo-b6699dcfb-rnv7t",
+
+			expectedErr: None,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if len(actions) != 4 {
+					t.Fatal(spew.Sdump(actions))
+				}
+				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[1].Matches("watch", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[2].Matches("list", "theresource") || actions[2].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
+					t.Error(spew.Sdump(actions))
+				}
+				if !actions[3].Matches("watch", "theresource") {
+					t.Error(spew.Sdump(actions))
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := test.fakeClient()
+			j, _ := newJSONPathParser(test.jsonPathExp)
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: JSONPathWait{
+					jsonPathCondition: test.jsonPathCond,
+					jsonPathParser:    j, errOut: ioutil.Discard}.IsJSONPathConditionMet,
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+
+			test.validateActions(t, fakeClient.Actions())
+		})
+	}
+}
+
+func TestConditionFuncFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		condition   string
+		expectedErr string
+	}{
+		{
+			name:      "jsonpath equality",
+			condition: "jsonpath={.status.readyReplicas}=3",
+		},
+		{
+			name:      "jsonpath not equal",
+			condition: "jsonpath={.status.phase}!=Pending",
+		},
+		{
+			name:      "jsonpath greater than or equal",
+			condition: "jsonpath={.status.readyReplicas}>=3",
+		},
+		{
+			name:      "jsonpath less than or equal",
+			condition: "jsonpath={.status.readyReplicas}<=3",
+		},
+		{
+			name:      "jsonpath greater than",
+			condition: "jsonpath={.status.readyReplicas}>3",
+		},
+		{
+			name:      "jsonpath less than",
+			condition: "jsonpath={.status.readyReplicas}<3",
+		},
+		{
+			name:        "jsonpath numeric operator with non-numeric value",
+			condition:   "jsonpath={.status.readyReplicas}>=three",
+			expectedErr: `jsonpath wait condition "three" must be a number when used with the ">=" operator`,
+		},
+		{
+			name:      "jsonpath any of several values",
+			condition: "jsonpath={.status.phase}=Running|Succeeded",
+		},
+		{
+			name:      "jsonpath any of several comma-separated values",
+			condition: "jsonpath={.status.phase}=Running,Succeeded",
+		},
+		{
+			name:        "jsonpath only empty values",
+			condition:   "jsonpath={.status.phase}=|",
+			expectedErr: "jsonpath wait condition must contain at least one non-empty value",
+		},
+		{
+			name:        "jsonpath only empty comma-separated values",
+			condition:   "jsonpath={.status.phase}=,",
+			expectedErr: "jsonpath wait condition must contain at least one non-empty value",
+		},
+		{
+			name:      "jsonpath regular expression",
+			condition: `jsonpath={.status.version}~=^v1\.27\.`,
+		},
+		{
+			name:        "jsonpath invalid regular expression",
+			condition:   "jsonpath={.status.version}~=v1.(27",
+			expectedErr: `jsonpath wait condition "v1.(27" is not a valid regular expression`,
+		},
+		{
+			name:        "jsonpath missing value",
+			condition:   "jsonpath={.status.readyReplicas}>",
+			expectedErr: "jsonpath wait condition cannot be empty",
+		},
+		{
+			name:      "jsonpath exists",
+			condition: "jsonpath={.status.loadBalancer.ingress[0].ip}",
+		},
+		{
+			name:      "jsonpath absent",
+			condition: "jsonpath=!{.metadata.finalizers}",
+		},
+		{
+			name:      "jsonpath value containing '='",
+			condition: "jsonpath={.status.url}='https://host/path?a=b&c=d'",
+		},
+		{
+			name:      "jsonpath not equal value containing '='",
+			condition: "jsonpath={.status.url}!=https://host/path?a=b",
+		},
+		{
+			name:      "jsonpath compared with another expression",
+			condition: "jsonpath={.status.observedGeneration}=={.metadata.generation}",
+		},
+		{
+			name:      "jsonpath compared numerically with another expression",
+			condition: "jsonpath={.status.readyReplicas}>='{.spec.replicas}'",
+		},
+		{
+			name:      "jsonpath-cmp",
+			condition: "jsonpath-cmp={.status.readyReplicas}>='{.spec.replicas}'",
+		},
+		{
+			name:        "jsonpath-cmp with a value",
+			condition:   "jsonpath-cmp={.status.readyReplicas}>=3",
+			expectedErr: `jsonpath-cmp compares with another JSONPath expression, not "3"`,
+		},
+		{
+			name:        "jsonpath-cmp with a regular expression",
+			condition:   "jsonpath-cmp={.status.version}~={.spec.version}",
+			expectedErr: "jsonpath-cmp compares numbers with =, !=, >, >=, < or <=",
+		},
+		{
+			name:        "jsonpath-cmp without an operator",
+			condition:   "jsonpath-cmp={.status.readyReplicas}",
+			expectedErr: "jsonpath-cmp compares numbers with =, !=, >, >=, < or <=",
+		},
+		{
+			name:      "jsonpath-all",
+			condition: "jsonpath-all={.status.containerStatuses[*].ready}=true",
+		},
+		{
+			name:      "jsonpath-any",
+			condition: "jsonpath-any={.status.containerStatuses[*].restartCount}>0",
+		},
+		{
+			name:        "jsonpath-all with a length operator",
+			condition:   "jsonpath-all={.status.containerStatuses[*].ready}#=3",
+			expectedErr: "jsonpath-all compares every value of the expression with =, !=, >, >=, <, <=, ~=, *=, ^= or $=",
+		},
+		{
+			name:        "jsonpath-any without an operator",
+			condition:   "jsonpath-any={.status.containerStatuses[*].ready}",
+			expectedErr: "jsonpath-any compares every value of the expression with =, !=, >, >=, <, <=, ~=, *=, ^= or $=",
+		},
+		{
+			name:        "jsonpath compared with an invalid expression",
+			condition:   "jsonpath={.status.observedGeneration}={.metadata.generation[}",
+			expectedErr: "unterminated array",
+		},
+		{
+			name:      "jsonpath contains",
+			condition: "jsonpath={.status.message}*=ready",
+		},
+		{
+			name:      "jsonpath prefix",
+			condition: "jsonpath={.status.version}^=v1.",
+		},
+		{
+			name:      "jsonpath suffix",
+			condition: "jsonpath={.status.url}$=.example.com",
+		},
+		{
+			name:        "jsonpath length with a string operator",
+			condition:   "jsonpath={.status.conditions}#^=5",
+			expectedErr: `the "#^=" operator is not supported`,
+		},
+		{
+			name:      "jsonpath filter expression",
+			condition: `jsonpath={.status.conditions[?(@.type=="Ready")].status}=True`,
+		},
+		{
+			name:      "jsonpath age",
+			condition: `jsonpath={.status.conditions[?(@.type=="Ready")].lastTransitionTime}>age:30s`,
+		},
+		{
+			name:      "jsonpath age less than or equal",
+			condition: "jsonpath={.metadata.creationTimestamp}<=age:1h",
+		},
+		{
+			name:        "jsonpath age with an equality operator",
+			condition:   "jsonpath={.metadata.creationTimestamp}=age:30s",
+			expectedErr: `jsonpath wait condition "age:30s" can only be used with the >, >=, < and <= operators`,
+		},
+		{
+			name:        "jsonpath age with an invalid duration",
+			condition:   "jsonpath={.metadata.creationTimestamp}>age:soon",
+			expectedErr: "must be age: followed by a non-negative duration",
+		},
+		{
+			name:      "template",
+			condition: "template={{ ge .status.readyReplicas .spec.replicas }}",
+		},
+		{
+			name:        "invalid template",
+			condition:   "template={{ ge .status.readyReplicas",
+			expectedErr: `template wait condition "{{ ge .status.readyReplicas" is not a valid template`,
+		},
+		{
+			name:        "empty template",
+			condition:   "template=",
+			expectedErr: "template wait condition cannot be empty",
+		},
+		{
+			name:      "create",
+			condition: "create",
+		},
+		{
+			name:      "synced",
+			condition: "synced",
+		},
+		{
+			name:      "bound",
+			condition: "bound",
+		},
+		{
+			name:      "no-finalizers",
+			condition: "no-finalizers",
+		},
+		{
+			name:      "has-key",
+			condition: "has-key=tls.crt",
+		},
+		{
+			name:        "has-key without a key",
+			condition:   "has-key=",
+			expectedErr: "has-key requires a key, for instance has-key=tls.crt",
+		},
+		{
+			name:      "label",
+			condition: "label=environment=production",
+		},
+		{
+			name:        "label with an invalid key",
+			condition:   "label=environment production",
+			expectedErr: `label key "environment production" is not valid`,
+		},
+		{
+			name:        "label with an invalid value",
+			condition:   "label=environment=prod/eu",
+			expectedErr: `label value "prod/eu" is not valid`,
+		},
+		{
+			name:      "annotation with any value",
+			condition: "annotation=example.com/state=done, or not/yet",
+		},
+		{
+			name:        "annotation with an invalid key",
+			condition:   "annotation=example.com/state/done",
+			expectedErr: `annotation key "example.com/state/done" is not valid`,
+		},
+		{
+			name:      "owned-by",
+			condition: "owned-by=ReplicaSet/nginx-5d4f8",
+		},
+		{
+			name:      "job-complete",
+			condition: "job-complete",
+		},
+		{
+			name:      "rollout",
+			condition: "rollout",
+		},
+		{
+			name:      "containers-ready",
+			condition: "containers-ready",
+		},
+		{
+			name:      "containers-ready with a count",
+			condition: "containers-ready=2",
+		},
+		{
+			name:        "containers-ready with an invalid count",
+			condition:   "containers-ready=0",
+			expectedErr: `containers-ready count "0" must be a positive integer`,
+		},
+		{
+			name:      "jsonpath length",
+			condition: "jsonpath={.status.conditions}#=5",
+		},
+		{
+			name:      "jsonpath length greater than or equal",
+			condition: "jsonpath={.status.conditions}#>=5",
+		},
+		{
+			name:      "jsonpath length greater than",
+			condition: "jsonpath={.status.conditions[*]}#>5",
+		},
+		{
+			name:      "jsonpath length not equal",
+			condition: "jsonpath={.status.conditions}#!=0",
+		},
+		{
+			name:        "jsonpath negative length",
+			condition:   "jsonpath={.status.conditions}#=-1",
+			expectedErr: `jsonpath wait condition "-1" must be a non-negative integer when used with the "#=" operator`,
+		},
+		{
+			name:        "jsonpath length with regular expression",
+			condition:   "jsonpath={.status.conditions}#~=5",
+			expectedErr: `the "#~=" operator is not supported`,
+		},
+		{
+			name:      "condition with reason",
+			condition: "condition=Available=true,reason=MinimumReplicasAvailable",
+		},
+		{
+			name:        "condition with empty reason",
+			condition:   "condition=Available,reason=",
+			expectedErr: "condition reason cannot be empty",
+		},
+		{
+			name:        "unrecognized condition",
+			condition:   "foo",
+			expectedErr: `unrecognized condition: "foo"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := conditionFuncFor(test.condition, false)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitResult(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	mapping := &meta.RESTMapping{
+		Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+	}
+	infos := []*resource.Info{
+		{Mapping: mapping, Name: "name-foo", Namespace: "ns-foo"},
+		{Mapping: mapping, Name: "name-bar", Namespace: "ns-foo"},
+	}
+
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clienttesting.ListAction).GetListRestrictions().Fields.String()
+		name = strings.TrimPrefix(name, "metadata.name=")
+		return true, newUnstructuredList(addCondition(
+			newUnstructured("group/version", "TheKind", "ns-foo", name),
+			"the-condition", "True",
+		)), nil
+	})
+	o := &WaitOptions{
+		ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+		DynamicClient:  fakeClient,
+		Timeout:        10 * time.Second,
+		ForCondition:   "condition=the-condition",
+		Clock:          clockwork.NewFakeClock(),
+
+		ConditionFn: ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+		IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+	}
+
+	result, err := o.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Matched != 2 {
+		t.Errorf("expected 2 matched resources, got %d", result.Matched)
+	}
+	if len(result.Satisfied) != 2 {
+		t.Fatalf("expected 2 satisfied resources, got %d", len(result.Satisfied))
+	}
+	for i, name := range []string{"name-foo", "name-bar"} {
+		if got := result.Satisfied[i].(*unstructured.Unstructured).GetName(); got != name {
+			t.Errorf("expected satisfied resource %d to be %q, got %q", i, name, got)
+		}
+	}
+	if result.Elapsed != 0 {
+		t.Errorf("expected no elapsed time on a fake clock, got %v", result.Elapsed)
+	}
+	if result.Polls != 2 {
+		t.Errorf("expected 2 polls, got %d", result.Polls)
+	}
+
+	// the condition is checked once against the list and once against the watch event
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")), nil
+	})
+	fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+		fakeWatch := watch.NewRaceFreeFake()
+		fakeWatch.Modify(addCondition(
+			newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+			"the-condition", "True",
+		))
+		return true, fakeWatch, nil
+	})
+	o.ResourceFinder = genericclioptions.NewSimpleFakeResourceFinder(infos[0])
+	result, err = o.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Polls != 2 {
+		t.Errorf("expected 2 polls, got %d", result.Polls)
+	}
+
+	o.ResourceFinder = genericclioptions.NewSimpleFakeResourceFinder()
+	result, err = o.Wait(context.Background())
+	if !errors.Is(err, errNoMatchingResources) {
+		t.Errorf("expected %v, got %v", errNoMatchingResources, err)
+	}
+	if result.Matched != 0 || len(result.Satisfied) != 0 {
+		t.Errorf("expected an empty result, got %#v", result)
+	}
+}
+
+func TestWaitForJSONPathValueContainingEquals(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+
+		expectedErr string
+	}{
+		{
+			name:        "unquoted value",
+			condition:   "jsonpath={.status.url}=https://host/path?a=b&c=d",
+			expectedErr: None,
+		},
+		{
+			name:        "quoted value",
+			condition:   "jsonpath={.status.url}='https://host/path?a=b&c=d'",
+			expectedErr: None,
+		},
+		{
+			name:        "not equal value",
+			condition:   "jsonpath={.status.url}!=https://host/path?a=b",
+			expectedErr: None,
+		},
+		{
+			name:        "value ends before '='",
+			condition:   "jsonpath={.status.url}=https://host/path?a",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				unstructured.SetNestedField(obj.Object, "https://host/path?a=b&c=d", "status", "url")
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        1 * time.Second,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathExpressionComparison(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name               string
+		condition          string
+		generation         int64
+		observedGeneration int64
+
+		expectedErr string
+	}{
+		{
+			name:               "generation observed",
+			condition:          "jsonpath={.status.observedGeneration}=={.metadata.generation}",
+			generation:         2,
+			observedGeneration: 2,
+			expectedErr:        None,
+		},
+		{
+			name:               "generation observed with quotes",
+			condition:          "jsonpath={.status.observedGeneration}=='{.metadata.generation}'",
+			generation:         2,
+			observedGeneration: 2,
+			expectedErr:        None,
+		},
+		{
+			name:               "generation not observed yet",
+			condition:          "jsonpath={.status.observedGeneration}=={.metadata.generation}",
+			generation:         2,
+			observedGeneration: 1,
+			expectedErr:        "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:        "no observed generation",
+			condition:   "jsonpath={.status.observedGeneration}=={.metadata.generation}",
+			generation:  2,
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:               "generation not equal",
+			condition:          "jsonpath={.status.observedGeneration}!={.metadata.generation}",
+			generation:         2,
+			observedGeneration: 1,
+			expectedErr:        None,
+		},
+		{
+			name:               "generation compared numerically",
+			condition:          "jsonpath={.metadata.generation}>{.status.observedGeneration}",
+			generation:         10,
+			observedGeneration: 9,
+			expectedErr:        None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				obj.SetGeneration(test.generation)
+				if test.observedGeneration != 0 {
+					unstructured.SetNestedField(obj.Object, test.observedGeneration, "status", "observedGeneration")
+				}
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        1 * time.Second,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForGeneration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name               string
+		generation         int64
+		observedGeneration int64
+		terminating        bool
+
+		expectedErr string
+	}{
+		{
+			name:               "generation observed",
+			generation:         2,
+			observedGeneration: 2,
+			expectedErr:        None,
+		},
+		{
+			name:               "generation not observed yet",
+			generation:         2,
+			observedGeneration: 1,
+			expectedErr:        "condition not met after 1 checks on theresource/name-foo",
+		},
+		{
+			name:        "no observed generation",
+			generation:  2,
+			expectedErr: "condition not met after 1 checks on theresource/name-foo",
+		},
+		{
+			name:               "terminating",
+			generation:         2,
+			observedGeneration: 2,
+			terminating:        true,
+			expectedErr:        "condition not met after 1 checks on theresource/name-foo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				obj.SetGeneration(test.generation)
+				if test.observedGeneration != 0 {
+					unstructured.SetNestedField(obj.Object, test.observedGeneration, "status", "observedGeneration")
+				}
+				if test.terminating {
+					now := metav1.Now()
+					obj.SetDeletionTimestamp(&now)
+				}
+				return true, newUnstructuredList(obj), nil
+			})
+			// the object never changes, so a single check ends the wait rather than the timeout
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        time.Minute,
+				MaxPolls:       1,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: GenerationWait{}.IsGenerationObserved,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathIgnoreCase(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		condition  string
+		ignoreCase bool
+
+		expectedErr string
+	}{
+		{
+			name:        "case-sensitive by default",
+			condition:   "jsonpath={.status.phase}=Running",
+			expectedErr: "condition not met after 1 checks on theresource/name-foo",
+		},
+		{
+			name:        "case-insensitive",
+			condition:   "jsonpath={.status.phase}=Running",
+			ignoreCase:  true,
+			expectedErr: None,
+		},
+		{
+			name:        "case-insensitive any of several values",
+			condition:   "jsonpath={.status.phase}=Succeeded|RUNNING",
+			ignoreCase:  true,
+			expectedErr: None,
+		},
+		{
+			name:        "case-insensitive not equal",
+			condition:   "jsonpath={.status.phase}!=Running",
+			ignoreCase:  true,
+			expectedErr: "condition not met after 1 checks on theresource/name-foo: {.status.phase} (last observed: running) to be != Running (case-insensitive match)",
+		},
+		{
+			name:        "case-insensitive regular expression",
+			condition:   "jsonpath={.status.phase}~=^RUN",
+			ignoreCase:  true,
+			expectedErr: None,
+		},
+		{
+			name:        "case-insensitive prefix",
+			condition:   "jsonpath={.status.phase}^=RUN",
+			ignoreCase:  true,
+			expectedErr: None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				unstructured.SetNestedField(obj.Object, "running", "status", "phase")
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, test.ignoreCase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the object never changes, so a single check ends the wait rather than the timeout
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        time.Minute,
+				MaxPolls:       1,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitWithoutTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	lists := 0
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		lists++
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+		if lists == 50 {
+			obj = addCondition(obj, "the-condition", "True")
+		}
+		return true, newUnstructuredList(obj), nil
+	})
+	fakeClock := clockwork.NewFakeClock()
+	o := &WaitOptions{
+		ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+		DynamicClient:  fakeClient,
+		PollInterval:   time.Hour,
+		Clock:          fakeClock,
+
+		Printer:     printers.NewDiscardingPrinter(),
+		ConditionFn: ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+		IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- o.RunWait()
+	}()
+	var err error
+loop:
+	for {
+		sleeping := make(chan struct{})
+		go func() {
+			fakeClock.BlockUntil(1)
+			close(sleeping)
+		}()
+		select {
+		case err = <-errCh:
+			break loop
+		case <-sleeping:
+			fakeClock.Advance(time.Hour)
+		}
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lists != 50 {
+		t.Errorf("expected 50 lists, got %d", lists)
+	}
+}
+
+func TestWaitTimeoutDetail(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+	obj.SetGeneration(2)
+	obj.SetFinalizers([]string{"example.com/foo"})
+	now := metav1.Now()
+	obj.SetDeletionTimestamp(&now)
+	unstructured.SetNestedField(obj.Object, int64(1), "status", "readyReplicas")
+	unstructured.SetNestedField(obj.Object, int64(1), "status", "observedGeneration")
+	obj = addCondition(obj, "Ready", "False")
+
+	tests := []struct {
+		name         string
+		condition    string
+		pollInterval time.Duration
+
+		expectedErr string
+	}{
+		{
+			name:        "jsonpath numeric comparison",
+			condition:   "jsonpath={.status.readyReplicas}>=3",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.readyReplicas} (last observed: 1) to be >= 3",
+		},
+		{
+			name:        "jsonpath equality",
+			condition:   "jsonpath={.status.readyReplicas}=3",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.readyReplicas} (last observed: 1) to be 3",
+		},
+		{
+			name:        "jsonpath missing value",
+			condition:   "jsonpath={.status.phase}=Running",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.phase} (last observed: <none>) to be Running",
+		},
+		{
+			name:        "jsonpath exists",
+			condition:   "jsonpath={.status.phase}",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.phase} (last observed: <none>) to exist",
+		},
+		{
+			name:        "jsonpath regular expression",
+			condition:   "jsonpath={.status.readyReplicas}~=^3",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.readyReplicas} (last observed: 1) to match ^3",
+		},
+		{
+			name:         "condition while polling",
+			condition:    "condition=Ready",
+			pollInterval: time.Millisecond,
+			expectedErr:  "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: False) to be true",
+		},
+		{
+			name:        "condition",
+			condition:   "condition=Ready",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: False) to be true",
+		},
+		{
+			name:        "synced",
+			condition:   "synced",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: observedGeneration/generation (last observed: 1/2) to match",
+		},
+		{
+			name:        "delete",
+			condition:   "delete",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: deletion (last observed: terminating with finalizers example.com/foo)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(obj.DeepCopy()), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+				PollInterval:   test.pollInterval,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+			if err == nil || err.Error() != test.expectedErr {
+				t.Fatalf("expected %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestEventCondition(t *testing.T) {
+	ready := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "True")
+	notReady := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "False")
+	check := ConditionalWait{conditionName: "Ready", conditionStatus: "True"}.checkCondition
+
+	tests := []struct {
+		name      string
+		onDeleted bool
+		event     watch.Event
+
+		expectedMet    bool
+		expectedErrOut string
+	}{
+		{
+			name:        "modified and met",
+			event:       watch.Event{Type: watch.Modified, Object: ready},
+			expectedMet: true,
+		},
+		{
+			name:  "modified and not met",
+			event: watch.Event{Type: watch.Modified, Object: notReady},
+		},
+		{
+			name:  "deleted",
+			event: watch.Event{Type: watch.Deleted, Object: ready},
+		},
+		{
+			name:        "deleted meeting the condition",
+			onDeleted:   true,
+			event:       watch.Event{Type: watch.Deleted, Object: notReady},
+			expectedMet: true,
+		},
+		{
+			name:           "error",
+			event:          watch.Event{Type: watch.Error, Object: &metav1.Status{Status: metav1.StatusFailure, Message: "too old", Reason: metav1.StatusReasonGone}},
+			expectedErrOut: "error: An error occurred while waiting for the thing to be ready: too old\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errOut := &bytes.Buffer{}
+			met, err := eventCondition(errOut, "the thing to be ready", test.onDeleted, check)(test.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if met != test.expectedMet {
+				t.Errorf("expected met to be %v, got %v", test.expectedMet, met)
+			}
+			if errOut.String() != test.expectedErrOut {
+				t.Errorf("expected %q on stderr, got %q", test.expectedErrOut, errOut.String())
+			}
+		})
+	}
+}
+
+func TestWaitForConditionReason(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		status    string
+		reason    string
+
+		expectedErr string
+	}{
+		{
+			name:        "status and reason match",
+			condition:   "condition=Available,reason=MinimumReplicasAvailable",
+			status:      "True",
+			reason:      "MinimumReplicasAvailable",
+			expectedErr: None,
+		},
+		{
+			name:        "reason contains value",
+			condition:   "condition=Available,reason=Replicas",
+			status:      "True",
+			reason:      "MinimumReplicasAvailable",
+			expectedErr: None,
+		},
+		{
+			name:        "status matches but reason does not",
+			condition:   "condition=Available,reason=NewReplicaSetAvailable",
+			status:      "True",
+			reason:      "MinimumReplicasAvailable",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition Available (last observed: True, reason MinimumReplicasAvailable) to be true with a reason containing NewReplicaSetAvailable",
+		},
+		{
+			name:        "reason matches but status does not",
+			condition:   "condition=Available,reason=MinimumReplicasAvailable",
+			status:      "False",
+			reason:      "MinimumReplicasAvailable",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:        "reason with explicit status",
+			condition:   "condition=Available=false,reason=MinimumReplicasUnavailable",
+			status:      "False",
+			reason:      "MinimumReplicasUnavailable",
+			expectedErr: None,
+		},
+		{
+			name:        "reason ignored when not given",
+			condition:   "condition=Available",
+			status:      "True",
+			reason:      "Anything",
+			expectedErr: None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(addConditionWithReason(
+					newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+					"Available", test.status, test.reason,
+				)), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForConditionStatusSet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		status    string
+
+		expectedErr string
+	}{
+		{
+			name:        "first status of the set",
+			condition:   "condition=Ready=True,Unknown",
+			status:      "True",
+			expectedErr: None,
+		},
+		{
+			name:        "second status of the set",
+			condition:   "condition=Ready=True,Unknown",
+			status:      "Unknown",
+			expectedErr: None,
+		},
+		{
+			name:        "status not in the set",
+			condition:   "condition=Ready=True,Unknown",
+			status:      "False",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: False) to be True or Unknown",
+		},
+		{
+			name:        "set with a reason",
+			condition:   "condition=Ready=False,Unknown,reason=Pending",
+			status:      "Unknown",
+			expectedErr: None,
+		},
+		{
+			name:        "single status",
+			condition:   "condition=Ready=Unknown",
+			status:      "True",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: True) to be Unknown",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(addConditionWithReason(
+					newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+					"Ready", test.status, "Pending",
+				)), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForRecreatedResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	mapping := &meta.RESTMapping{
+		Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+	}
+	infos := []*resource.Info{
+		{Mapping: mapping, Name: "name-foo", Namespace: "ns-foo"},
+	}
+	newObj := func(uid types.UID, ready bool) *unstructured.Unstructured {
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+		obj.SetUID(uid)
+		if ready {
+			obj = addCondition(obj, "the-condition", "True")
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name   string
+		uidMap UIDMap
+
+		expectedErr    string
+		expectedErrOut string
+		expectedLists  int
+	}{
+		{
+			name:           "follows the new object",
+			expectedErrOut: "warning: theresource/name-foo was recreated with uid uid-b, waiting on the new object",
+			expectedLists:  2,
+		},
+		{
+			name: "fails when the uid is pinned",
+			uidMap: UIDMap{
+				ResourceLocation{GroupResource: mapping.Resource.GroupResource(), Namespace: "ns-foo", Name: "name-foo"}: "uid-a",
+			},
+			expectedErr:   "condition unsatisfied on theresource/name-foo: resource was recreated with uid uid-b, expected uid uid-a",
+			expectedLists: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			lists := 0
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				lists++
+				if lists == 1 {
+					return true, newUnstructuredList(newObj("uid-a", false)), nil
+				}
+				return true, newUnstructuredList(newObj("uid-b", true)), nil
+			})
+			fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				fakeWatch := watch.NewRaceFreeFake()
+				fakeWatch.Action(watch.Added, newObj("uid-b", true))
+				return true, fakeWatch, nil
+			})
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				UIDMap:         test.uidMap,
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Second,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+				IOStreams:   streams,
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if !strings.Contains(errOut.String(), test.expectedErrOut) {
+				t.Errorf("expected %q in the error output, got %q", test.expectedErrOut, errOut.String())
+			}
+			if lists != test.expectedLists {
+				t.Errorf("expected %d lists, got %d", test.expectedLists, lists)
+			}
+		})
+	}
+}
+
+func TestWaitStableFor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		readyReplicas []int64
+		timeout       time.Duration
+		settle        bool
+
+		expectedErr   string
+		expectedLists int
+	}{
+		{
+			name:          "stable from the start",
+			readyReplicas: []int64{3},
+			timeout:       5 * time.Minute,
+			expectedErr:   None,
+			expectedLists: 4,
+		},
+		{
+			name:          "unmet value resets the window",
+			readyReplicas: []int64{3, 3, 2, 3},
+			timeout:       5 * time.Minute,
+			expectedErr:   None,
+			expectedLists: 7,
+		},
+		{
+			name:          "changed value resets the window",
+			readyReplicas: []int64{3, 3, 4, 4},
+			timeout:       5 * time.Minute,
+			expectedErr:   None,
+			expectedLists: 6,
+		},
+		{
+			name:          "window longer than the timeout",
+			readyReplicas: []int64{3},
+			timeout:       20 * time.Second,
+			expectedErr:   "timed out waiting for the condition on theresource/name-foo",
+			expectedLists: 3,
+		},
+		{
+			name:          "changed value keeps the settle window",
+			readyReplicas: []int64{3, 3, 4, 4},
+			timeout:       5 * time.Minute,
+			settle:        true,
+			expectedErr:   None,
+			expectedLists: 4,
+		},
+		{
+			name:          "unmet value resets the settle window",
+			readyReplicas: []int64{3, 3, 2, 3},
+			timeout:       5 * time.Minute,
+			settle:        true,
+			expectedErr:   None,
+			expectedLists: 7,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			lists := 0
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				readyReplicas := test.readyReplicas[len(test.readyReplicas)-1]
+				if lists < len(test.readyReplicas) {
+					readyReplicas = test.readyReplicas[lists]
+				}
+				lists++
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				unstructured.SetNestedField(obj.Object, readyReplicas, "status", "readyReplicas")
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor("jsonpath={.status.readyReplicas}>=3", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+				PollInterval:   10 * time.Second,
+				StableFor:      30 * time.Second,
+				Clock:          fakeClock,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			if test.settle {
+				o.StableFor, o.Settle = 0, 30*time.Second
+			}
+
+			errCh := make(chan error)
+			go func() {
+				errCh <- o.RunWait()
+			}()
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case err = <-errCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(10 * time.Second)
+				}
+			}
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if lists != test.expectedLists {
+				t.Errorf("expected %d lists, got %d", test.expectedLists, lists)
+			}
+		})
+	}
+}
+
+func TestWaitSettleDelay(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		statuses []string
+
+		expectedElapsed time.Duration
+	}{
+		{
+			name:            "ready from the start",
+			statuses:        []string{"True"},
+			expectedElapsed: 30 * time.Second,
+		},
+		{
+			name:            "ready after a poll",
+			statuses:        []string{"False", "True"},
+			expectedElapsed: 40 * time.Second,
+		},
+		{
+			name:            "not ready on a poll within the window",
+			statuses:        []string{"True", "True", "False", "True"},
+			expectedElapsed: 60 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			lists := 0
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				status := test.statuses[len(test.statuses)-1]
+				if lists < len(test.statuses) {
+					status = test.statuses[lists]
+				}
+				lists++
+				return true, newUnstructuredList(addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", status)), nil
+			})
+			conditionFn, err := conditionFuncFor("condition=Ready", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        5 * time.Minute,
+				PollInterval:   10 * time.Second,
+				Settle:         30 * time.Second,
+				Clock:          fakeClock,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			type outcome struct {
+				result Result
+				err    error
+			}
+			outcomeCh := make(chan outcome)
+			go func() {
+				result, err := o.Wait(context.Background())
+				outcomeCh <- outcome{result, err}
+			}()
+			var waited outcome
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case waited = <-outcomeCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(10 * time.Second)
+				}
+			}
+
+			if waited.err != nil {
+				t.Fatal(waited.err)
+			}
+			if waited.result.Elapsed != test.expectedElapsed {
+				t.Errorf("expected the condition to be met after %v, got %v", test.expectedElapsed, waited.result.Elapsed)
+			}
+		})
+	}
+}
+
+func TestWaitStableForWatch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, newUnstructuredList(addCondition(
+			newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+			"the-condition", "True",
+		)), nil
+	})
+	o := &WaitOptions{
+		ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+		DynamicClient:  fakeClient,
+		Timeout:        10 * time.Second,
+		StableFor:      100 * time.Millisecond,
+
+		Printer:     printers.NewDiscardingPrinter(),
+		ConditionFn: ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+		IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+	}
+
+	if err := o.RunWait(); err != nil {
+		t.Fatal(err)
+	}
+	actions := fakeClient.Actions()
+	if len(actions) != 3 {
+		t.Fatal(spew.Sdump(actions))
+	}
+	if !actions[0].Matches("list", "theresource") || !actions[1].Matches("watch", "theresource") || !actions[2].Matches("list", "theresource") {
+		t.Error(spew.Sdump(actions))
+	}
+}
+
+func TestWaitForJSONPathLength(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+
+		expectedErr string
+	}{
+		{
+			name:        "list length",
+			condition:   "jsonpath={.status.conditions}#=3",
+			expectedErr: None,
+		},
+		{
+			name:        "list length with a numeric operator",
+			condition:   "jsonpath={.status.conditions}#>2",
+			expectedErr: None,
+		},
+		{
+			name:        "list length not reached",
+			condition:   "jsonpath={.status.conditions}#>=4",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.conditions} (last observed: length 3) to have length >= 4",
+		},
+		{
+			name:        "number of selected values",
+			condition:   "jsonpath={.status.conditions[*].type}#=3",
+			expectedErr: None,
+		},
+		{
+			name:        "missing list",
+			condition:   "jsonpath={.status.missing}#=0",
+			expectedErr: None,
+		},
+		{
+			name:        "scalar value",
+			condition:   "jsonpath={.metadata.name}#=1",
+			expectedErr: "jsonpath length operators can only be used with a list, but the expression leads to the value name-foo",
+		},
+		{
+			name:        "object value",
+			condition:   "jsonpath={.metadata}#=1",
+			expectedErr: "jsonpath length operators can only be used with a list, but the expression leads to an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				obj = addCondition(obj, "condition-a", "True")
+				obj = addCondition(obj, "condition-b", "True")
+				obj = addCondition(obj, "condition-c", "False")
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+// erroringResourceFinder is a ResourceFinder whose visitor passes errs to the visit function
+type erroringResourceFinder struct {
+	errs []error
+}
+
+func (f erroringResourceFinder) Do() resource.Visitor {
+	return f
+}
+
+func (f erroringResourceFinder) Visit(fn resource.VisitorFunc) error {
+	for _, err := range f.errs {
+		if err := fn(nil, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWaitIgnoreErrorFns(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "group", Resource: "theresource"}, "name-foo")
+	internalError := apierrors.NewInternalError(errors.New("etcd is unavailable"))
+
+	tests := []struct {
+		name           string
+		forCondition   string
+		ignoreErrorFns []resource.ErrMatchFunc
+		errs           []error
+
+		expectedErr string
+	}{
+		{
+			name:         "delete ignores NotFound",
+			forCondition: "delete",
+			errs:         []error{notFound},
+			expectedErr:  None,
+		},
+		{
+			name:         "delete surfaces other errors",
+			forCondition: "delete",
+			errs:         []error{notFound, internalError},
+			expectedErr:  "etcd is unavailable",
+		},
+		{
+			name:         "condition does not ignore NotFound",
+			forCondition: "condition=Ready",
+			errs:         []error{notFound},
+			expectedErr:  `theresource.group "name-foo" not found`,
+		},
+		{
+			name:           "condition with custom ignore function",
+			forCondition:   "condition=Ready",
+			ignoreErrorFns: []resource.ErrMatchFunc{apierrors.IsInternalError},
+			errs:           []error{internalError},
+			expectedErr:    "no matching resources found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := &WaitOptions{
+				ResourceFinder: erroringResourceFinder{errs: test.errs},
+				IgnoreErrorFns: test.ignoreErrorFns,
+				ForCondition:   test.forCondition,
+				Printer:        printers.NewDiscardingPrinter(),
+				ConditionFn:    IsDeleted,
+				IOStreams:      genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForDeletionWatchNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+	fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")), nil
+	})
+	fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "ns-foo")
+	})
+	o := &WaitOptions{
+		ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+		DynamicClient:  fakeClient,
+		Timeout:        10 * time.Second,
+		ForCondition:   "delete",
+
+		Printer:     printers.NewDiscardingPrinter(),
+		ConditionFn: IsDeleted,
+		IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+	}
+	if err := o.RunWait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// appearingResourceFinder is a ResourceFinder which finds no resources until it has been
+// asked more than empty times
+type appearingResourceFinder struct {
+	empty int
+	infos []*resource.Info
+	calls *int
+}
+
+func (f appearingResourceFinder) Do() resource.Visitor {
+	*f.calls++
+	if *f.calls <= f.empty {
+		return resource.InfoListVisitor{}
+	}
+	return resource.InfoListVisitor(f.infos)
+}
+
+func TestWaitForResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name             string
+		empty            int
+		waitForResources bool
+		forCondition     string
+		conditionFn      ConditionFunc
+
+		expectedErr   string
+		expectedCalls int
+	}{
+		{
+			name:             "resources appear",
+			empty:            3,
+			waitForResources: true,
+			conditionFn:      ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+			expectedErr:      None,
+			expectedCalls:    4,
+		},
+		{
+			name:             "resources never appear",
+			empty:            100,
+			waitForResources: true,
+			conditionFn:      ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+			expectedErr:      "no matching resources found: timed out waiting for the condition",
+			expectedCalls:    6,
+		},
+		{
+			name:          "fails without waiting for resources",
+			empty:         3,
+			conditionFn:   ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+			expectedErr:   "no matching resources found",
+			expectedCalls: 1,
+		},
+		{
+			name:             "ignored when waiting for deletion",
+			empty:            3,
+			waitForResources: true,
+			forCondition:     "delete",
+			conditionFn:      IsDeleted,
+			expectedErr:      None,
+			expectedCalls:    1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(addCondition(
+					newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+					"the-condition", "True",
+				)), nil
+			})
+			calls := 0
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder:   appearingResourceFinder{empty: test.empty, infos: infos, calls: &calls},
+				DynamicClient:    fakeClient,
+				Timeout:          5 * time.Second,
+				ForCondition:     test.forCondition,
+				WaitForResources: test.waitForResources,
+				Clock:            fakeClock,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: test.conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			errCh := make(chan error)
+			go func() {
+				errCh <- o.RunWait()
+			}()
+			var err error
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case err = <-errCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(time.Second)
+				}
+			}
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("expected the resources to be looked for %d times, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestWaitConcurrency(t *testing.T) {
+	var infos []*resource.Info
+	for i := 0; i < 8; i++ {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      fmt.Sprintf("name-%d", i),
+			Namespace: "ns-foo",
+		})
+	}
+
+	tests := []struct {
+		name        string
+		concurrency int
+		failing     string
+
+		expectedErr       string
+		expectedSatisfied int
+	}{
+		{
+			name:              "sequential",
+			concurrency:       1,
+			expectedErr:       None,
+			expectedSatisfied: 8,
+		},
+		{
+			name:              "bounded by concurrency",
+			concurrency:       4,
+			expectedErr:       None,
+			expectedSatisfied: 8,
+		},
+		{
+			name:              "an error does not stop the others",
+			concurrency:       8,
+			failing:           "name-3",
+			expectedErr:       "name-3 is broken",
+			expectedSatisfied: 7,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var inFlight, maxInFlight, stuck int32
+			// every check is held until as many checks as allowed are running at once, so the
+			// peak is reached without relying on how long a check takes, and the checks give up
+			// if it never is
+			barrier := make(chan struct{})
+			var release sync.Once
+			slowCheck := func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					max := atomic.LoadInt32(&maxInFlight)
+					if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+						break
+					}
+				}
+				if n >= int32(test.concurrency) {
+					release.Do(func() { close(barrier) })
+				}
+				select {
+				case <-barrier:
+				case <-time.After(10 * time.Second):
+					// release the other checks too, so the test fails rather than waiting again on each
+					atomic.StoreInt32(&stuck, 1)
+					release.Do(func() { close(barrier) })
+					return nil, false, fmt.Errorf("%s was never waited on with %d others", info.Name, test.concurrency-1)
+				}
+				if info.Name == test.failing {
+					return nil, false, fmt.Errorf("%s is broken", info.Name)
+				}
+				fmt.Fprintf(o.ErrOut, "checked %s\n", info.Name)
+				return newUnstructured("group/version", "TheKind", info.Namespace, info.Name), true, nil
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        10 * time.Second,
+				Concurrency:    test.concurrency,
+				Clock:          clockwork.NewFakeClock(),
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: slowCheck,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+			if atomic.LoadInt32(&stuck) != 0 {
+				t.Fatalf("expected %d checks at once, got at most %d", test.concurrency, atomic.LoadInt32(&maxInFlight))
+			}
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if result.Matched != len(infos) {
+				t.Errorf("expected %d matched, got %d", len(infos), result.Matched)
+			}
+			if len(result.Satisfied) != test.expectedSatisfied {
+				t.Errorf("expected %d satisfied, got %d", test.expectedSatisfied, len(result.Satisfied))
+			}
+			if maxInFlight != int32(test.concurrency) {
+				t.Errorf("expected %d checks at once, got %d", test.concurrency, maxInFlight)
+			}
+		})
+	}
+}
+
+func TestWaitModeAny(t *testing.T) {
+	var infos []*resource.Info
+	for i := 0; i < 4; i++ {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      fmt.Sprintf("name-%d", i),
+			Namespace: "ns-foo",
+		})
+	}
+
+	tests := []struct {
+		name  string
+		ready map[string]bool
+
+		expectedErr       string
+		expectedSatisfied string
+	}{
+		{
+			name:              "one meets the condition",
+			ready:             map[string]bool{"name-2": true},
+			expectedErr:       None,
+			expectedSatisfied: "name-2",
+		},
+		{
+			name:        "none meets the condition",
+			ready:       map[string]bool{},
+			expectedErr: "timed out waiting for the condition",
+		},
+		{
+			name:        "all meet the condition",
+			ready:       map[string]bool{"name-0": true, "name-1": true, "name-2": true, "name-3": true},
+			expectedErr: None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check := func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+				if test.ready[info.Name] {
+					return newUnstructured("group/version", "TheKind", info.Namespace, info.Name), true, nil
+				}
+				select {
+				case <-ctx.Done():
+					return nil, false, ctx.Err()
+				case <-time.After(o.Timeout):
+					return nil, false, &TimeoutError{Resource: "theresource.group", Name: info.Name}
+				}
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        200 * time.Millisecond,
+				Mode:           WaitModeAny,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: check,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if result.Matched != len(infos) {
+				t.Errorf("expected %d matched, got %d", len(infos), result.Matched)
+			}
+			if len(result.Satisfied) != 1 {
+				t.Fatalf("expected one satisfied resource, got %d", len(result.Satisfied))
+			}
+			if test.expectedSatisfied != "" {
+				name := result.Satisfied[0].(*unstructured.Unstructured).GetName()
+				if name != test.expectedSatisfied {
+					t.Errorf("expected %s to be satisfied, got %s", test.expectedSatisfied, name)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitExclude(t *testing.T) {
+	var infos []*resource.Info
+	for _, name := range []string{"name-foo", "name-bar"} {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+			Object:    newUnstructured("group/version", "TheKind", "ns-foo", name),
+		})
+	}
+	groupResource := schema.GroupResource{Group: "group", Resource: "theresource"}
+
+	tests := []struct {
+		name    string
+		exclude []ResourceLocation
+		count   *CountWait
+
+		expectedWaited  []string
+		expectedMatched int
+		expectedErr     string
+	}{
+		{
+			name:            "excluded by name",
+			exclude:         []ResourceLocation{{GroupResource: groupResource, Name: "name-bar"}},
+			expectedWaited:  []string{"name-foo"},
+			expectedMatched: 1,
+			expectedErr:     None,
+		},
+		{
+			name:            "excluded in its namespace",
+			exclude:         []ResourceLocation{{GroupResource: groupResource, Namespace: "ns-foo", Name: "name-bar"}},
+			expectedWaited:  []string{"name-foo"},
+			expectedMatched: 1,
+			expectedErr:     None,
+		},
+		{
+			name:            "excluded in another namespace",
+			exclude:         []ResourceLocation{{GroupResource: groupResource, Namespace: "ns-bar", Name: "name-bar"}},
+			expectedWaited:  []string{"name-foo", "name-bar"},
+			expectedMatched: 2,
+			expectedErr:     None,
+		},
+		{
+			name:            "another resource type",
+			exclude:         []ResourceLocation{{GroupResource: schema.GroupResource{Resource: "pods"}, Name: "name-bar"}},
+			expectedWaited:  []string{"name-foo", "name-bar"},
+			expectedMatched: 2,
+			expectedErr:     None,
+		},
+		{
+			name:            "not found",
+			exclude:         []ResourceLocation{{GroupResource: groupResource, Name: "name-baz"}},
+			expectedWaited:  []string{"name-foo", "name-bar"},
+			expectedMatched: 2,
+			expectedErr:     None,
+		},
+		{
+			name:        "all excluded",
+			exclude:     []ResourceLocation{{GroupResource: groupResource, Name: "name-foo"}, {GroupResource: groupResource, Name: "name-bar"}},
+			expectedErr: "no matching resources found",
+		},
+		{
+			name:            "left out of a count",
+			exclude:         []ResourceLocation{{GroupResource: groupResource, Name: "name-bar"}},
+			count:           &CountWait{operator: "=", count: 1},
+			expectedMatched: 1,
+			expectedErr:     None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var waited []string
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        time.Second,
+				Exclude:        test.exclude,
+				Count:          test.count,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					waited = append(waited, info.Name)
+					return info.Object, true, nil
+				},
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(waited, test.expectedWaited) {
+				t.Errorf("expected %v to be waited on, got %v", test.expectedWaited, waited)
+			}
+			if result.Matched != test.expectedMatched {
+				t.Errorf("expected %d matched, got %d", test.expectedMatched, result.Matched)
+			}
+		})
+	}
+}
+
+func TestCountWaitFor(t *testing.T) {
+	tests := []struct {
+		condition string
+
+		expectedCount *CountWait
+		expectedErr   string
+	}{
+		{condition: "count>=3", expectedCount: &CountWait{operator: ">=", count: 3}},
+		{condition: "count<=3", expectedCount: &CountWait{operator: "<=", count: 3}},
+		{condition: "count>0", expectedCount: &CountWait{operator: ">", count: 0}},
+		{condition: "count<2", expectedCount: &CountWait{operator: "<", count: 2}},
+		{condition: "count!=1", expectedCount: &CountWait{operator: "!=", count: 1}},
+		{condition: "count=3", expectedCount: &CountWait{operator: "=", count: 3}},
+		{condition: "count==3", expectedCount: &CountWait{operator: "=", count: 3}},
+		{condition: "count", expectedErr: `count condition "count" must be an operator followed by a non-negative integer`},
+		{condition: "count>=", expectedErr: `count condition "count>=" must be an operator followed by a non-negative integer`},
+		{condition: "count>=-1", expectedErr: `count condition "count>=-1" must be an operator followed by a non-negative integer`},
+		{condition: "count~=3", expectedErr: `count condition "count~=3" must be an operator followed by a non-negative integer`},
+	}
+	for _, test := range tests {
+		t.Run(test.condition, func(t *testing.T) {
+			count, err := countWaitFor(test.condition)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			require.Equal(t, test.expectedCount, count)
+			require.Equal(t, strings.Replace(test.condition, "==", "=", 1), count.String())
+		})
+	}
+}
+
+// growingResourceFinder finds one more resource every time it is asked
+type growingResourceFinder struct {
+	infos []*resource.Info
+	calls *int
+}
+
+func (f growingResourceFinder) Do() resource.Visitor {
+	found := *f.calls
+	*f.calls++
+	if found > len(f.infos) {
+		found = len(f.infos)
+	}
+	return resource.InfoListVisitor(f.infos[:found])
+}
+
+func TestWaitForCount(t *testing.T) {
+	var infos []*resource.Info
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("name-%d", i)
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+			Object:    newUnstructured("group/version", "TheKind", "ns-foo", name),
+		})
+	}
+
+	tests := []struct {
+		name     string
+		count    *CountWait
+		checkNow bool
+
+		expectedErr     string
+		expectedMatched int
+		expectedCalls   int
+	}{
+		{
+			name:            "count reached",
+			count:           &CountWait{operator: ">=", count: 3},
+			expectedErr:     None,
+			expectedMatched: 3,
+			expectedCalls:   4,
+		},
+		{
+			name:            "count already met",
+			count:           &CountWait{operator: "<", count: 3},
+			expectedErr:     None,
+			expectedMatched: 0,
+			expectedCalls:   1,
+		},
+		{
+			name:            "count never reached",
+			count:           &CountWait{operator: ">=", count: 10},
+			expectedErr:     "timed out waiting for the condition: found 5 resources, expected count>=10",
+			expectedMatched: 5,
+			expectedCalls:   6,
+		},
+		{
+			name:            "count checked now",
+			count:           &CountWait{operator: ">=", count: 3},
+			checkNow:        true,
+			expectedErr:     "condition not met: found 0 resources, expected count>=3",
+			expectedMatched: 0,
+			expectedCalls:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: growingResourceFinder{infos: infos, calls: &calls},
+				Timeout:        5 * time.Second,
+				Count:          test.count,
+				CheckNow:       test.checkNow,
+				Clock:          fakeClock,
+
+				Printer:   printers.NewDiscardingPrinter(),
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			type waitResult struct {
+				result Result
+				err    error
+			}
+			resultCh := make(chan waitResult)
+			go func() {
+				result, err := o.Wait(context.Background())
+				resultCh <- waitResult{result, err}
+			}()
+			var done waitResult
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case done = <-resultCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(time.Second)
+				}
+			}
+
+			err := done.err
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if done.result.Matched != test.expectedMatched {
+				t.Errorf("expected %d matched, got %d", test.expectedMatched, done.result.Matched)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("expected %d lookups, got %d", test.expectedCalls, calls)
+			}
+			if done.result.Polls != test.expectedCalls {
+				t.Errorf("expected %d polls, got %d", test.expectedCalls, done.result.Polls)
+			}
+		})
+	}
+}
+
+func TestWaitForCovers(t *testing.T) {
+	var infos []*resource.Info
+	for i, shard := range []string{"0", "0", "2", "1", "3"} {
+		name := fmt.Sprintf("name-%d", i)
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", name)
+		obj.SetLabels(map[string]string{"shard": shard})
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+			Object:    obj,
+		})
+	}
+
+	tests := []struct {
+		name       string
+		condition  string
+		ignoreCase bool
+		checkNow   bool
+
+		expectedErr     string
+		expectedMatched int
+		expectedCalls   int
+	}{
+		{
+			name:            "covered across resources",
+			condition:       "jsonpath={.metadata.labels.shard}covers=0,1,2",
+			expectedErr:     None,
+			expectedMatched: 4,
+			expectedCalls:   5,
+		},
+		{
+			name:            "never covered",
+			condition:       "jsonpath={.metadata.labels.shard}covers=0,1,2,4",
+			expectedErr:     "timed out waiting for the condition: observed 0,1,2,3 on 5 resources, expected jsonpath={.metadata.labels.shard}covers=0,1,2,4",
+			expectedMatched: 5,
+			expectedCalls:   6,
+		},
+		{
+			name:            "checked now",
+			condition:       "jsonpath={.metadata.labels.shard}covers=0",
+			checkNow:        true,
+			expectedErr:     "condition not met: observed no values on 0 resources, expected jsonpath={.metadata.labels.shard}covers=0",
+			expectedMatched: 0,
+			expectedCalls:   1,
+		},
+		{
+			name:            "case-insensitive",
+			condition:       "jsonpath={.metadata.name}covers=NAME-1",
+			ignoreCase:      true,
+			expectedErr:     None,
+			expectedMatched: 2,
+			expectedCalls:   3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregate, err := coversWaitFor(test.condition, test.ignoreCase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			calls := 0
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: growingResourceFinder{infos: infos, calls: &calls},
+				Timeout:        5 * time.Second,
+				Aggregate:      aggregate,
+				CheckNow:       test.checkNow,
+				Clock:          fakeClock,
+
+				Printer:   printers.NewDiscardingPrinter(),
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			type waitResult struct {
+				result Result
+				err    error
+			}
+			resultCh := make(chan waitResult)
+			go func() {
+				result, err := o.Wait(context.Background())
+				resultCh <- waitResult{result, err}
+			}()
+			var done waitResult
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case done = <-resultCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(time.Second)
+				}
+			}
+
+			err = done.err
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if done.result.Matched != test.expectedMatched {
+				t.Errorf("expected %d matched, got %d", test.expectedMatched, done.result.Matched)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("expected %d lookups, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+
+	for condition, expectedErr := range map[string]string{
+		"jsonpath={.metadata.labels.shard}covers=":     "jsonpath wait condition cannot be empty",
+		"jsonpath={.metadata.labels.shard}covers=0,,2": `jsonpath covers condition "0,,2" must list the values to cover`,
+		"jsonpath={.metadata.labels.shard[}covers=0,1": "is not valid",
+	} {
+		if _, err := coversWaitFor(condition, false); err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Errorf("%s: expected %q, got %v", condition, expectedErr, err)
+		}
+	}
+	if _, err := conditionFuncFor("jsonpath={.metadata.labels.shard}covers=0,1", false); err == nil || !strings.Contains(err.Error(), "is checked across all the resources") {
+		t.Errorf("expected a covers condition to be rejected on each resource, got %v", err)
+	}
+}
+
+func TestNewJSONPathWait(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		operator   string
+		condition  string
+
+		expectedValues []string
+		expectedRegexp string
+		multiValue     bool
+		valueParser    bool
+	}{
+		{
+			name:           "values are split once",
+			expression:     "{.status.phase}",
+			operator:       "=",
+			condition:      "Running|Succeeded",
+			expectedValues: []string{"Running", "Succeeded"},
+		},
+		{
+			name:           "length",
+			expression:     "{.status.conditions}",
+			operator:       "#>=",
+			condition:      " 2",
+			expectedValues: []string{"2"},
+		},
+		{
+			name:           "length of several values",
+			expression:     "{.status.conditions[*].type}",
+			operator:       "#=",
+			condition:      "2",
+			expectedValues: []string{"2"},
+			multiValue:     true,
+		},
+		{
+			name:           "regular expression",
+			expression:     "{.status.phase}",
+			operator:       "~=",
+			condition:      "^Run",
+			expectedRegexp: "^Run",
+		},
+		{
+			name:        "expression",
+			expression:  "{.status.observedGeneration}",
+			operator:    "=",
+			condition:   "{.metadata.generation}",
+			valueParser: true,
+		},
+		{
+			name:       "exists",
+			expression: "{.status.phase}",
+			operator:   "exists",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := newJSONPathWait(test.expression, test.operator, test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			require.Equal(t, test.expectedValues, w.expectedValues)
+			require.Equal(t, test.multiValue, w.multiValue)
+			require.Equal(t, test.valueParser, w.jsonPathValueParser != nil)
+			if len(test.expectedRegexp) > 0 {
+				require.NotNil(t, w.jsonPathRegexp)
+				require.Equal(t, test.expectedRegexp, w.jsonPathRegexp.String())
+			} else {
+				require.Nil(t, w.jsonPathRegexp)
+			}
+		})
+	}
+}
+
+func TestJSONPathWaitCheckDoesNotParse(t *testing.T) {
+	obj := createUnstructured(t, podYAML)
+	w, err := newJSONPathWait("{.status.phase}", "=", "Pending|Running", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the check has to use the expression compiled once, since parsing this one again fails
+	w.jsonPathExpression = "{.status.phase"
+	if _, err := newJSONPathParser(w.jsonPathExpression); err == nil {
+		t.Fatalf("expected %q not to parse", w.jsonPathExpression)
+	}
+	check := func() {
+		if met, err := w.checkCondition(obj); err != nil || !met {
+			t.Fatalf("expected the condition to be met, got %v", err)
+		}
+	}
+	check()
+
+	// every check allocates the same, as one which parsed only some of the time would not, and
+	// less than parsing the expression does
+	parseAllocs := testing.AllocsPerRun(100, func() {
+		if _, err := newJSONPathParser("{.status.phase}"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	firstAllocs := testing.AllocsPerRun(1, check)
+	checkAllocs := testing.AllocsPerRun(1000, check)
+	if firstAllocs != checkAllocs {
+		t.Errorf("expected every check to allocate the same, got %v allocations for one check and %v on average", firstAllocs, checkAllocs)
+	}
+	if checkAllocs >= parseAllocs {
+		t.Errorf("expected a check to allocate less than parsing the expression, got %v allocations for a check and %v for parsing", checkAllocs, parseAllocs)
+	}
+}
+
+func BenchmarkJSONPathWaitCheckCondition(b *testing.B) {
+	obj := createUnstructured(b, podYAML)
+	for _, condition := range []struct {
+		name       string
+		expression string
+		operator   string
+		condition  string
+	}{
+		{name: "values", expression: "{.status.phase}", operator: "=", condition: "Pending|Running"},
+		{name: "number", expression: "{.spec.priority}", operator: ">=", condition: "0"},
+		{name: "length", expression: "{.status.conditions}", operator: "#>=", condition: "2"},
+		{name: "regexp", expression: "{.status.phase}", operator: "~=", condition: "^Run"},
+	} {
+		w, err := newJSONPathWait(condition.expression, condition.operator, condition.condition, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(condition.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := w.checkCondition(obj); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitOutput(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+
+		expectedOutput string
+	}{
+		{
+			name:           "default",
+			output:         "",
+			expectedOutput: "thekind.group/name-foo condition met\n",
+		},
+		{
+			name:           "name",
+			output:         "name",
+			expectedOutput: "thekind.group/name-foo\n",
+		},
+		{
+			name:           "jsonpath",
+			output:         "jsonpath={.status.phase}",
+			expectedOutput: "Running",
+		},
+		{
+			name:           "go-template",
+			output:         "go-template={{.metadata.name}} is {{.status.phase}}",
+			expectedOutput: "name-foo is Running",
+		},
+		{
+			name:           "json",
+			output:         "json",
+			expectedOutput: `"phase": "Running"`,
+		},
+		{
+			name:           "yaml",
+			output:         "yaml",
+			expectedOutput: "phase: Running",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "the-condition", "True")
+				unstructured.SetNestedField(obj.Object, "Running", "status", "phase")
+				return true, newUnstructuredList(obj), nil
+			})
+			printFlags := genericclioptions.NewPrintFlags("condition met")
+			*printFlags.OutputFormat = test.output
+			printer, err := printFlags.ToPrinter()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Second,
+
+				Printer:     printer,
+				ConditionFn: ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet,
+				IOStreams:   ioStreams,
+			}
+			if err := o.RunWait(); err != nil {
+				t.Fatal(err)
+			}
+			if test.output == "" || test.output == "name" {
+				require.Equal(t, test.expectedOutput, out.String())
+			} else {
+				require.Contains(t, out.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestWaitOutputName(t *testing.T) {
+	var infos []*resource.Info
+	for _, name := range []string{"name-foo", "name-bar", "name-baz"} {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+			Object:    newUnstructured("group/version", "TheKind", "ns-foo", name),
+		})
+	}
+
+	tests := []struct {
+		name     string
+		mode     WaitMode
+		ready    map[string]bool
+		noObject bool
+		printErr error
+
+		expectedOut    string
+		expectedErrOut string
+		expectedErr    string
+	}{
+		{
+			name:        "all",
+			ready:       map[string]bool{"name-foo": true, "name-bar": true, "name-baz": true},
+			expectedOut: "thekind.group/name-foo\nthekind.group/name-bar\nthekind.group/name-baz\n",
+			expectedErr: None,
+		},
+		{
+			name:        "all with one unmet",
+			ready:       map[string]bool{"name-foo": true, "name-baz": true},
+			expectedOut: "thekind.group/name-foo\nthekind.group/name-baz\n",
+			expectedErr: "condition unsatisfied on theresource/name-bar",
+		},
+		{
+			name:        "any",
+			mode:        WaitModeAny,
+			ready:       map[string]bool{"name-bar": true},
+			expectedOut: "thekind.group/name-bar\n",
+			expectedErr: None,
+		},
+		{
+			name:        "without the final object",
+			ready:       map[string]bool{"name-foo": true, "name-bar": true, "name-baz": true},
+			noObject:    true,
+			expectedOut: "thekind.group/name-foo\nthekind.group/name-bar\nthekind.group/name-baz\n",
+			expectedErr: None,
+		},
+		{
+			name:           "unable to print",
+			mode:           WaitModeAny,
+			ready:          map[string]bool{"name-bar": true},
+			printErr:       errors.New("broken pipe"),
+			expectedErrOut: "warning: unable to print a resource which met the condition: broken pipe\n",
+			expectedErr:    None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			printFlags := genericclioptions.NewPrintFlags("condition met")
+			*printFlags.OutputFormat = "name"
+			printer, err := printFlags.ToPrinter()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.printErr != nil {
+				printer = printers.ResourcePrinterFunc(func(runtime.Object, io.Writer) error { return test.printErr })
+			}
+			ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        time.Second,
+				Mode:           test.mode,
+
+				Printer: printer,
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					if !test.ready[info.Name] {
+						if test.mode == WaitModeAny {
+							<-ctx.Done()
+							return nil, false, ctx.Err()
+						}
+						return nil, false, &ConditionUnmetError{Resource: info.Mapping.Resource.Resource, Name: info.Name}
+					}
+					if test.noObject {
+						return nil, true, nil
+					}
+					return info.Object, true, nil
+				},
+				IOStreams: ioStreams,
+			}
+			_, err = o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if out.String() != test.expectedOut {
+				t.Errorf("expected %q, got %q", test.expectedOut, out.String())
+			}
+			if errOut.String() != test.expectedErrOut {
+				t.Errorf("expected %q on stderr, got %q", test.expectedErrOut, errOut.String())
+			}
+		})
+	}
+}
+
+func TestWaitInitialDelay(t *testing.T) {
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		initialDelay time.Duration
+		timeout      time.Duration
+
+		expectedCheckedAfter time.Duration
+		expectedTimeout      time.Duration
+	}{
+		{
+			name:                 "no delay",
+			timeout:              10 * time.Second,
+			expectedCheckedAfter: 0,
+			expectedTimeout:      10 * time.Second,
+		},
+		{
+			name:                 "delay counts towards the timeout",
+			initialDelay:         3 * time.Second,
+			timeout:              10 * time.Second,
+			expectedCheckedAfter: 3 * time.Second,
+			expectedTimeout:      7 * time.Second,
+		},
+		{
+			name:                 "delay without a timeout",
+			initialDelay:         3 * time.Second,
+			expectedCheckedAfter: 3 * time.Second,
+			expectedTimeout:      0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClock := clockwork.NewFakeClock()
+			start := fakeClock.Now()
+			var checkedAfter, timeout time.Duration
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        test.timeout,
+				InitialDelay:   test.initialDelay,
+				Clock:          fakeClock,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					checkedAfter, timeout = fakeClock.Since(start), o.Timeout
+					return newUnstructured("group/version", "TheKind", info.Namespace, info.Name), true, nil
+				},
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			errCh := make(chan error)
+			go func() {
+				errCh <- o.RunWait()
+			}()
+			if test.initialDelay > 0 {
+				fakeClock.BlockUntil(1)
+				fakeClock.Advance(test.initialDelay)
+			}
+			if err := <-errCh; err != nil {
+				t.Fatal(err)
+			}
+			if checkedAfter != test.expectedCheckedAfter {
+				t.Errorf("expected the first check after %v, got %v", test.expectedCheckedAfter, checkedAfter)
+			}
+			if timeout != test.expectedTimeout {
+				t.Errorf("expected a timeout of %v for the check, got %v", test.expectedTimeout, timeout)
+			}
+		})
+	}
+
+	t.Run("cancelled during the delay", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		o := &WaitOptions{
+			ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+			Timeout:        10 * time.Second,
+			InitialDelay:   time.Hour,
+			Clock:          clockwork.NewFakeClock(),
+
+			Printer: printers.NewDiscardingPrinter(),
+			ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+				t.Fatal("the condition should not be checked")
+				return nil, false, nil
+			},
+			IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+		}
+		if err := o.RunWaitContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestWaitOnCheck(t *testing.T) {
+	var infos []*resource.Info
+	for i := 0; i < 3; i++ {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      fmt.Sprintf("name-%d", i),
+			Namespace: "ns-foo",
+		})
+	}
+
+	tests := []struct {
+		name        string
+		concurrency int
+		failing     string
+
+		expectedErr    string
+		expectedChecks []string
+	}{
+		{
+			name:           "every check is reported",
+			concurrency:    1,
+			expectedErr:    None,
+			expectedChecks: []string{"name-0 done", "name-1 done", "name-2 done"},
+		},
+		{
+			name:           "errors are reported",
+			concurrency:    1,
+			failing:        "name-1",
+			expectedErr:    "name-1 is broken",
+			expectedChecks: []string{"name-0 done", "name-1 name-1 is broken", "name-2 done"},
+		},
+		{
+			name:           "calls are serialized when waiting concurrently",
+			concurrency:    3,
+			expectedErr:    None,
+			expectedChecks: []string{"name-0 done", "name-1 done", "name-2 done"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// not guarded, the race detector reports it if calls are not serialized
+			var checks []string
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        10 * time.Second,
+				Concurrency:    test.concurrency,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					if info.Name == test.failing {
+						return nil, false, fmt.Errorf("%s is broken", info.Name)
+					}
+					return newUnstructured("group/version", "TheKind", info.Namespace, info.Name), true, nil
+				},
+				OnCheck: func(info *resource.Info, obj runtime.Object, done bool, err error) {
+					switch {
+					case err != nil:
+						checks = append(checks, info.Name+" "+err.Error())
+					case done && obj != nil:
+						checks = append(checks, info.Name+" done")
+					default:
+						checks = append(checks, info.Name+" not done")
+					}
+				},
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			sort.Strings(checks)
+			require.Equal(t, test.expectedChecks, checks)
+		})
+	}
+}
+
+func TestWaitForPersists(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	present := func(status string) *unstructured.Unstructured {
+		return addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", status)
+	}
+	recreated := func() *unstructured.Unstructured {
+		obj := present("True")
+		obj.SetUID("another-UID-value")
+		return obj
+	}
+	terminating := func() *unstructured.Unstructured {
+		obj := present("True")
+		now := metav1.Now()
+		obj.SetDeletionTimestamp(&now)
+		obj.SetFinalizers([]string{"example.com/cleanup"})
+		return obj
+	}
+
+	tests := []struct {
+		name       string
+		conditions []string
+		// states are listed in turn, nil for the resource not to be found, and the last one
+		// for ever after. The resource is listed once per poll, and once more per companion
+		// condition.
+		states []*unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "stays present",
+			conditions:  []string{"persists"},
+			states:      []*unstructured.Unstructured{present("False")},
+			expectedErr: None,
+		},
+		{
+			name:        "deleted",
+			conditions:  []string{"persists"},
+			states:      []*unstructured.Unstructured{present("True"), present("True"), nil},
+			expectedErr: "condition unsatisfied on theresource/name-foo: the resource was deleted",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "missing from the start",
+			conditions:  []string{"persists"},
+			states:      []*unstructured.Unstructured{nil},
+			expectedErr: "condition unsatisfied on theresource/name-foo: the resource was deleted",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "recreated",
+			conditions:  []string{"persists"},
+			states:      []*unstructured.Unstructured{present("True"), recreated()},
+			expectedErr: "condition unsatisfied on theresource/name-foo: the resource was deleted and recreated with uid another-UID-value",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "being deleted",
+			conditions:  []string{"persists"},
+			states:      []*unstructured.Unstructured{present("True"), terminating()},
+			expectedErr: "condition unsatisfied on theresource/name-foo: the resource is being deleted",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "stays present and meets the companion condition",
+			conditions:  []string{"persists", "condition=Ready"},
+			states:      []*unstructured.Unstructured{present("True")},
+			expectedErr: None,
+		},
+		{
+			name:        "stops meeting the companion condition",
+			conditions:  []string{"persists", "condition=Ready"},
+			states:      []*unstructured.Unstructured{present("True"), present("True"), present("False")},
+			expectedErr: "condition unsatisfied on theresource/name-foo: condition=Ready was not met",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "companion conditions",
+			conditions:  []string{"persists", "condition=Ready", "jsonpath={.metadata.name}=name-bar"},
+			states:      []*unstructured.Unstructured{present("True")},
+			expectedErr: "condition=Ready,jsonpath={.metadata.name}=name-bar was not met",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			lists := 0
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				state := test.states[len(test.states)-1]
+				if lists < len(test.states) {
+					state = test.states[lists]
+				}
+				if restrictions := action.(clienttesting.ListAction).GetListRestrictions(); restrictions.Fields.Empty() || !strings.Contains(restrictions.Fields.String(), "name-foo") {
+					t.Errorf("expected the resource to be listed by name, got %v", restrictions.Fields)
+				}
+				lists++
+				if state == nil {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(state), nil
+			})
+			conditionFn, err := persistsFuncFor(test.conditions, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        100 * time.Millisecond,
+				PollInterval:   10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+	for _, test := range []struct {
+		conditions []string
+		timeout    time.Duration
+		checkNow   bool
+
+		expectedErr string
+	}{
+		{conditions: []string{"persists"}, timeout: 30 * time.Second, checkNow: true, expectedErr: "a persists condition cannot be used with --check-now"},
+		{conditions: []string{"persists"}, expectedErr: "a persists condition requires a positive --timeout"},
+		{conditions: []string{"persists", "delete"}, timeout: 30 * time.Second, expectedErr: "a persists condition cannot be combined with a delete condition"},
+	} {
+		flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+		flags.ForConditions = test.conditions
+		flags.Timeout = test.timeout
+		flags.CheckNow = test.checkNow
+		if _, err := flags.ToOptions([]string{"pod/foo"}); err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Errorf("%v: expected %q, got %v", test.conditions, test.expectedErr, err)
+		}
+	}
+}
+
+func TestWaitForFinalizersRemoved(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	withFinalizers := func(finalizers ...string) *unstructured.Unstructured {
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		listed    *unstructured.Unstructured
+		listErr   error
+		watchType watch.EventType
+		watched   *unstructured.Unstructured
+
+		expectedErr string
+	}{
+		{
+			name:        "no finalizers",
+			listed:      withFinalizers(),
+			expectedErr: None,
+		},
+		{
+			name:        "already deleted",
+			expectedErr: None,
+		},
+		{
+			name:        "resource type deleted",
+			listErr:     apierrors.NewNotFound(schema.GroupResource{Group: "group", Resource: "theresource"}, ""),
+			expectedErr: None,
+		},
+		{
+			name:        "finalizers removed while watching",
+			listed:      withFinalizers("example.com/cleanup"),
+			watchType:   watch.Modified,
+			watched:     withFinalizers(),
+			expectedErr: None,
+		},
+		{
+			name:        "deleted while watching",
+			listed:      withFinalizers("example.com/cleanup"),
+			watchType:   watch.Deleted,
+			watched:     withFinalizers(),
+			expectedErr: None,
+		},
+		{
+			name:        "finalizers left",
+			listed:      withFinalizers("example.com/cleanup", "example.com/backup"),
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: finalizers (last observed: example.com/cleanup,example.com/backup) to be removed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if test.listErr != nil {
+					return true, nil, test.listErr
+				}
+				if test.listed == nil {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(test.listed), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Action(test.watchType, test.watched)
+					return true, fakeWatch, nil
+				})
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: FinalizersWait{}.IsFinalizersRemoved,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	gr := schema.GroupResource{Group: "group", Resource: "theresource"}
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{name: "server timeout", err: apierrors.NewServerTimeout(gr, "list", 1), transient: true},
+		{name: "timeout", err: apierrors.NewTimeoutError("timed out", 1), transient: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 1), transient: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), transient: true},
+		{name: "connection reset", err: fmt.Errorf("read tcp: %w", syscall.ECONNRESET), transient: true},
+		{name: "connection refused", err: fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED), transient: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, transient: true},
+		{name: "bad request", err: apierrors.NewBadRequest("invalid field selector"), transient: false},
+		{name: "forbidden", err: apierrors.NewForbidden(gr, "name-foo", errors.New("denied")), transient: false},
+		{name: "unauthorized", err: apierrors.NewUnauthorized("expired"), transient: false},
+		{name: "not found", err: apierrors.NewNotFound(gr, "name-foo"), transient: false},
+		{name: "other", err: errors.New("something went wrong"), transient: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isTransientError(test.err); got != test.transient {
+				t.Errorf("expected transient to be %t for %v, got %t", test.transient, test.err, got)
+			}
+		})
+	}
+}
+
+func TestWaitTransientRetries(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	serverTimeout := apierrors.NewServerTimeout(schema.GroupResource{Group: "group", Resource: "theresource"}, "list", 1)
+
+	tests := []struct {
+		name       string
+		forDelete  bool
+		maxRetries int
+		failures   int
+		listErr    error
+
+		expectedErr   string
+		expectedLists int
+	}{
+		{
+			name:          "recovers from transient errors",
+			maxRetries:    3,
+			failures:      2,
+			listErr:       serverTimeout,
+			expectedErr:   None,
+			expectedLists: 3,
+		},
+		{
+			name:          "gives up after too many transient errors",
+			maxRetries:    3,
+			failures:      10,
+			listErr:       serverTimeout,
+			expectedErr:   "could not be completed at this time",
+			expectedLists: 4,
+		},
+		{
+			name:          "does not retry by default",
+			failures:      1,
+			listErr:       serverTimeout,
+			expectedErr:   "could not be completed at this time",
+			expectedLists: 1,
+		},
+		{
+			name:          "does not retry permanent errors",
+			maxRetries:    3,
+			failures:      1,
+			listErr:       apierrors.NewBadRequest("invalid field selector"),
+			expectedErr:   "invalid field selector",
+			expectedLists: 1,
+		},
+		{
+			name:          "recovers from transient errors waiting for deletion",
+			forDelete:     true,
+			maxRetries:    3,
+			failures:      2,
+			listErr:       serverTimeout,
+			expectedErr:   None,
+			expectedLists: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lists := 0
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				lists++
+				if lists <= test.failures {
+					return true, nil, test.listErr
+				}
+				if test.forDelete {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(addCondition(
+					newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+					"the-condition", "True",
+				)), nil
+			})
+			conditionFn := ConditionalWait{conditionName: "the-condition", conditionStatus: "True", errOut: ioutil.Discard}.IsConditionMet
+			if test.forDelete {
+				conditionFn = IsDeleted
+			}
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder:      genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:       fakeClient,
+				Timeout:             10 * time.Minute,
+				MaxTransientRetries: test.maxRetries,
+				Clock:               fakeClock,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			errCh := make(chan error)
+			go func() {
+				errCh <- o.RunWait()
+			}()
+			var err error
+		loop:
+			for {
+				sleeping := make(chan struct{})
+				go func() {
+					fakeClock.BlockUntil(1)
+					close(sleeping)
+				}()
+				select {
+				case err = <-errCh:
+					break loop
+				case <-sleeping:
+					fakeClock.Advance(maxTransientRetryInterval)
+				}
+			}
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if lists != test.expectedLists {
+				t.Errorf("expected %d lists, got %d", test.expectedLists, lists)
+			}
+		})
+	}
+}
+
+func TestWaitForBound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "persistentvolumeclaims"}: "PersistentVolumeClaimList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"},
+			},
+			Name:      "data",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		phase    string
+		watched  string
+		hasPhase bool
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "bound",
+			phase:       "Bound",
+			hasPhase:    true,
+			expectedErr: None,
+		},
+		{
+			name:        "bound while watching",
+			phase:       "Pending",
+			watched:     "Bound",
+			hasPhase:    true,
+			expectedErr: None,
+		},
+		{
+			name:        "pending",
+			phase:       "Pending",
+			hasPhase:    true,
+			expectedErr: "timed out waiting for the condition on persistentvolumeclaims/data: phase (last observed: Pending) to be Bound",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "lost",
+			phase:       "Lost",
+			hasPhase:    true,
+			expectedErr: "condition unsatisfied on persistentvolumeclaims/data: the phase is Lost, it will not become Bound",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "lost while watching",
+			phase:       "Pending",
+			watched:     "Lost",
+			hasPhase:    true,
+			expectedErr: "condition unsatisfied on persistentvolumeclaims/data: the phase is Lost, it will not become Bound",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "no phase",
+			expectedErr: "condition unsatisfied on persistentvolumeclaims/data: the resource does not report a .status.phase to wait on",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newClaim := func(phase string) *unstructured.Unstructured {
+				obj := newUnstructured("v1", "PersistentVolumeClaim", "ns-foo", "data")
+				if test.hasPhase {
+					unstructured.SetNestedField(obj.Object, phase, "status", "phase")
+				}
+				return obj
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "persistentvolumeclaims", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newClaim(test.phase)), nil
+			})
+			if len(test.watched) > 0 {
+				fakeClient.PrependWatchReactor("persistentvolumeclaims", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(newClaim(test.watched))
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor("bound", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathAge(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		condition   string
+		timestamp   string
+		expectedErr string
+	}{
+		{
+			name:        "old enough",
+			condition:   `jsonpath={.status.conditions[?(@.type=="Ready")].lastTransitionTime}>age:30s`,
+			timestamp:   now.Add(-time.Minute).Format(time.RFC3339),
+			expectedErr: None,
+		},
+		{
+			name:        "too new",
+			condition:   `jsonpath={.status.conditions[?(@.type=="Ready")].lastTransitionTime}>age:30s`,
+			timestamp:   now.Add(-10 * time.Second).Format(time.RFC3339),
+			expectedErr: `timed out waiting for the condition on theresource/name-foo: {.status.conditions[?(@.type=="Ready")].lastTransitionTime} (last observed: ` + now.Add(-10*time.Second).Format(time.RFC3339) + `) to have an age > 30s`,
+		},
+		{
+			name:        "young enough",
+			condition:   "jsonpath={.status.conditions[0].lastTransitionTime}<=age:1m",
+			timestamp:   now.Add(-time.Minute).Format(time.RFC3339),
+			expectedErr: None,
+		},
+		{
+			name:        "not a timestamp",
+			condition:   "jsonpath={.status.conditions[0].lastTransitionTime}>age:30s",
+			timestamp:   "yesterday",
+			expectedErr: `jsonpath value "yesterday" is not an RFC3339 timestamp, so its age cannot be compared with age:30s`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+			obj.Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               "Ready",
+						"status":             "True",
+						"lastTransitionTime": test.timestamp,
+					},
+				},
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+				Clock:          clockwork.NewFakeClockAt(now),
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathStringOperators(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+
+		expectedErr string
+	}{
+		{
+			name:        "contains",
+			condition:   "jsonpath={.status.message}*=ready",
+			expectedErr: None,
+		},
+		{
+			name:        "does not contain",
+			condition:   "jsonpath={.status.message}*=failed",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.message} (last observed: all replicas ready) to contain failed",
+		},
+		{
+			name:        "contains any of several values",
+			condition:   "jsonpath={.status.message}*=failed|replicas",
+			expectedErr: None,
+		},
+		{
+			name:        "prefix",
+			condition:   "jsonpath={.status.message}^=all",
+			expectedErr: None,
+		},
+		{
+			name:        "not a prefix",
+			condition:   "jsonpath={.status.message}^=ready",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.message} (last observed: all replicas ready) to start with ready",
+		},
+		{
+			name:        "suffix",
+			condition:   "jsonpath={.status.message}$=ready",
+			expectedErr: None,
+		},
+		{
+			name:        "not a suffix",
+			condition:   "jsonpath={.status.message}$=all",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.message} (last observed: all replicas ready) to end with all",
+		},
+		{
+			name:        "prefix of a number compares its string form",
+			condition:   "jsonpath={.status.readyReplicas}^=1",
+			expectedErr: None,
+		},
+		{
+			name:        "exact match is unchanged",
+			condition:   "jsonpath={.status.message}=ready",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.message} (last observed: all replicas ready) to be ready",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				obj.Object["status"] = map[string]interface{}{
+					"message":       "all replicas ready",
+					"readyReplicas": int64(10),
+				}
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitWatchRecovery(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	expired := newUnstructuredStatus(&metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   "Failure",
+		Code:     410,
+		Reason:   metav1.StatusReasonExpired,
+		Message:  "too old resource version",
+	})
+	newBookmark := func(resourceVersion string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("group/version")
+		obj.SetKind("TheKind")
+		obj.SetResourceVersion(resourceVersion)
+		return obj
+	}
+	newObj := func(resourceVersion string) *unstructured.Unstructured {
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+		obj.SetResourceVersion(resourceVersion)
+		return obj
+	}
+	met := func(resourceVersion string) *unstructured.Unstructured {
+		return addCondition(newObj(resourceVersion), "the-condition", "status-value")
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		// watches returns the events of each watch in turn, with whether the server closes it
+		// afterwards, or the error starting it returns
+		watches func(watchCount int) ([]watch.Event, bool, error)
+		// resourceVersion is the resourceVersion to start watching from
+		resourceVersion string
+
+		expectedActions         []string
+		expectedResourceVersion string
+	}{
+		{
+			name:      "expired event lists again without waiting for the watch to close",
+			condition: "condition=the-condition=status-value",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				if watchCount == 1 {
+					return []watch.Event{{Type: watch.Error, Object: expired}}, false, nil
+				}
+				return []watch.Event{{Type: watch.Modified, Object: met("201")}}, false, nil
+			},
+			expectedActions: []string{"list", "watch 100", "list", "watch 200"},
+		},
+		{
+			name:      "bookmarks are resumed from when the watch closes",
+			condition: "condition=the-condition=status-value",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				if watchCount == 1 {
+					return []watch.Event{
+						{Type: watch.Modified, Object: newObj("110")},
+						{Type: watch.Bookmark, Object: newBookmark("150")},
+					}, true, nil
+				}
+				return []watch.Event{{Type: watch.Modified, Object: met("151")}}, false, nil
+			},
+			expectedActions: []string{"list", "watch 100", "watch 150"},
+		},
+		{
+			name:      "a watch closed without events lists again",
+			condition: "condition=the-condition=status-value",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				if watchCount == 1 {
+					return nil, true, nil
+				}
+				return []watch.Event{{Type: watch.Modified, Object: met("201")}}, false, nil
+			},
+			expectedActions: []string{"list", "watch 100", "list", "watch 200"},
+		},
+		{
+			name:      "an expired resourceVersion to resume from lists again",
+			condition: "condition=the-condition=status-value",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				switch watchCount {
+				case 1:
+					return []watch.Event{{Type: watch.Bookmark, Object: newBookmark("150")}}, true, nil
+				case 2:
+					return nil, false, apierrors.NewResourceExpired("too old resource version")
+				}
+				return []watch.Event{{Type: watch.Modified, Object: met("201")}}, false, nil
+			},
+			expectedActions: []string{"list", "watch 100", "watch 150", "list", "watch 200"},
+		},
+		{
+			name:      "deletion is resumed from a bookmark",
+			condition: "delete",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				if watchCount == 1 {
+					return []watch.Event{{Type: watch.Bookmark, Object: newBookmark("150")}}, true, nil
+				}
+				return []watch.Event{{Type: watch.Deleted, Object: newObj("151")}}, false, nil
+			},
+			expectedActions: []string{"list", "watch 100", "watch 150"},
+		},
+		{
+			name:      "deletion lists again after an expired event",
+			condition: "delete",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				if watchCount == 1 {
+					return []watch.Event{{Type: watch.Error, Object: expired}}, false, nil
+				}
+				return []watch.Event{{Type: watch.Deleted, Object: newObj("201")}}, false, nil
+			},
+			expectedActions: []string{"list", "watch 100", "list", "watch 200"},
+		},
+		{
+			name:            "a recorded resourceVersion is watched from without listing",
+			condition:       "condition=the-condition=status-value",
+			resourceVersion: "50",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				return []watch.Event{{Type: watch.Modified, Object: met("51")}}, false, nil
+			},
+			expectedActions:         []string{"watch 50"},
+			expectedResourceVersion: "51",
+		},
+		{
+			name:            "an expired recorded resourceVersion lists again",
+			condition:       "condition=the-condition=status-value",
+			resourceVersion: "50",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				if watchCount == 1 {
+					return nil, false, apierrors.NewResourceExpired("too old resource version")
+				}
+				return []watch.Event{{Type: watch.Modified, Object: met("101")}}, false, nil
+			},
+			expectedActions:         []string{"watch 50", "list", "watch 100"},
+			expectedResourceVersion: "101",
+		},
+		{
+			name:            "deletion is watched from a recorded resourceVersion",
+			condition:       "delete",
+			resourceVersion: "50",
+			watches: func(watchCount int) ([]watch.Event, bool, error) {
+				return []watch.Event{{Type: watch.Deleted, Object: newObj("51")}}, false, nil
+			},
+			expectedActions: []string{"watch 50"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			listCount := 0
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				listCount++
+				list := newUnstructuredList(newObj("99"))
+				list.SetResourceVersion(fmt.Sprintf("%d", 100*listCount))
+				return true, list, nil
+			})
+			watchCount := 0
+			fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				watchCount++
+				events, closed, err := test.watches(watchCount)
+				if err != nil {
+					return true, nil, err
+				}
+				fakeWatch := watch.NewRaceFreeFake()
+				for _, event := range events {
+					fakeWatch.Action(event.Type, event.Object)
+				}
+				if closed {
+					fakeWatch.Stop()
+				}
+				return true, fakeWatch, nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder:  genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:   fakeClient,
+				Timeout:         10 * time.Second,
+				ResourceVersion: test.resourceVersion,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   streams,
+			}
+
+			result, err := o.Wait(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errOut.Len() > 0 {
+				t.Errorf("unexpected errors: %s", errOut.String())
+			}
+			if len(test.expectedResourceVersion) > 0 && result.Resources[0].ResourceVersion != test.expectedResourceVersion {
+				t.Errorf("expected resourceVersion %s to be recorded, got %s", test.expectedResourceVersion, result.Resources[0].ResourceVersion)
+			}
+			var actions []string
+			for _, action := range fakeClient.Actions() {
+				if watchAction, ok := action.(clienttesting.WatchAction); ok {
+					actions = append(actions, "watch "+watchAction.GetWatchRestrictions().ResourceVersion)
+					continue
+				}
+				actions = append(actions, action.GetVerb())
+			}
+			if !reflect.DeepEqual(actions, test.expectedActions) {
+				t.Errorf("expected actions %v, got %v", test.expectedActions, actions)
+			}
+		})
+	}
+}
+
+func TestPrintResourceVersions(t *testing.T) {
+	streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+	o := &WaitOptions{IOStreams: streams}
+	o.printResourceVersions(Result{
+		Resources: []ResourceStatus{
+			{Resource: "pods", Name: "foo", Met: true, ResourceVersion: "1234"},
+			{Resource: "pods", Name: "bar", Err: &TimeoutError{Resource: "pods", Name: "bar"}, ResourceVersion: "1240"},
+			{Resource: "pods", Name: "baz", Err: errors.New("not found")},
+		},
+	})
+	if expected := "resourceVersion of pods/foo: 1234\nresourceVersion of pods/bar: 1240\n"; errOut.String() != expected {
+		t.Errorf("expected %q, got %q", expected, errOut.String())
+	}
+
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+	flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+	flags.ForConditions = []string{"condition=Ready"}
+	flags.ResourceVersion = "1234"
+	flags.PollInterval = time.Second
+	_, err := flags.ToOptions([]string{"pod/foo"})
+	if err == nil || !strings.Contains(err.Error(), "--resource-version only applies when watching") {
+		t.Fatalf("expected --resource-version to be rejected when polling, got %v", err)
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	var infos []*resource.Info
+	for _, name := range []string{"name-foo", "name-bar"} {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+		})
+	}
+	phases := map[string]string{"name-foo": "Running", "name-bar": "Pending"}
+
+	tests := []struct {
+		name         string
+		pollInterval time.Duration
+	}{
+		{
+			name: "watching",
+		},
+		{
+			name:         "polling",
+			pollInterval: time.Millisecond,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				name := strings.TrimPrefix(action.(clienttesting.ListAction).GetListRestrictions().Fields.String(), "metadata.name=")
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", name)
+				unstructured.SetNestedField(obj.Object, phases[name], "status", "phase")
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor("jsonpath={.status.phase}=Running", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+				PollInterval:   test.pollInterval,
+				ForCondition:   "jsonpath={.status.phase}=Running",
+				PrintSummary:   true,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   streams,
+			}
+			result, err := o.runInterruptible(make(chan os.Signal), func(code int) { t.Errorf("unexpected exit with %d", code) })
+			if err == nil || !strings.Contains(err.Error(), "timed out waiting for the condition on theresource/name-bar") {
+				t.Fatalf("expected a timeout on name-bar, got %v", err)
+			}
+
+			observed := map[string]string{}
+			for _, status := range result.Resources {
+				observed[status.Name] = status.Observed
+			}
+			if !reflect.DeepEqual(observed, phases) {
+				t.Errorf("expected the phases %v to be observed, got %v", phases, observed)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			sort.Strings(lines[1:])
+			expected := []string{
+				"NAME                   OBSERVED   EXPECTED                           RESULT",
+				"theresource/name-bar   Pending    jsonpath={.status.phase}=Running   timeout",
+				"theresource/name-foo   Running    jsonpath={.status.phase}=Running   met",
+			}
+			if !reflect.DeepEqual(lines, expected) {
+				t.Errorf("expected the summary\n%s\ngot\n%s", strings.Join(expected, "\n"), out.String())
+			}
+		})
+	}
+
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+	flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+	flags.ForConditions = []string{"condition=Ready"}
+	*flags.PrintFlags.OutputFormat = "wide"
+	o, err := flags.ToOptions([]string{"pod/foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.PrintSummary {
+		t.Error("expected -o wide to print a summary")
+	}
+	flags.ForConditions = []string{"count>=2"}
+	if _, err := flags.ToOptions([]string{"pod"}); err == nil || !strings.Contains(err.Error(), "-o wide cannot be used with a count or a covers condition") {
+		t.Errorf("expected -o wide to be rejected with a count condition, got %v", err)
+	}
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   *string
+		flags []string
+
+		expectedTimeout time.Duration
+		expectedErr     string
+	}{
+		{
+			name:            "unset",
+			expectedTimeout: 30 * time.Second,
+		},
+		{
+			name:            "empty",
+			env:             pointer.StringPtr(""),
+			expectedTimeout: 30 * time.Second,
+		},
+		{
+			name:            "from the environment",
+			env:             pointer.StringPtr("10m"),
+			expectedTimeout: 10 * time.Minute,
+		},
+		{
+			name:            "no deadline from the environment",
+			env:             pointer.StringPtr("0"),
+			expectedTimeout: 0,
+		},
+		{
+			name:            "the flag wins",
+			env:             pointer.StringPtr("10m"),
+			flags:           []string{"--timeout=5s"},
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:            "the flag wins over an invalid value",
+			env:             pointer.StringPtr("soon"),
+			flags:           []string{"--timeout=5s"},
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:            "deadline alone",
+			flags:           []string{"--deadline=2024-01-01T00:00:00Z"},
+			expectedTimeout: 0,
+		},
+		{
+			name:            "deadline with a timeout from the environment",
+			env:             pointer.StringPtr("10m"),
+			flags:           []string{"--deadline=2024-01-01T00:00:00Z"},
+			expectedTimeout: 10 * time.Minute,
+		},
+		{
+			name:            "deadline with a timeout",
+			flags:           []string{"--deadline=2024-01-01T00:00:00Z", "--timeout=5s"},
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:        "invalid",
+			env:         pointer.StringPtr("soon"),
+			expectedErr: `KUBECTL_WAIT_TIMEOUT must be a non-negative duration such as 10m, got "soon"`,
+		},
+		{
+			name:        "negative",
+			env:         pointer.StringPtr("-1m"),
+			expectedErr: `KUBECTL_WAIT_TIMEOUT must be a non-negative duration such as 10m, got "-1m"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if previous, ok := os.LookupEnv(timeoutEnvVar); ok {
+				defer os.Setenv(timeoutEnvVar, previous)
+			} else {
+				defer os.Unsetenv(timeoutEnvVar)
+			}
+			if test.env != nil {
+				os.Setenv(timeoutEnvVar, *test.env)
+			} else {
+				os.Unsetenv(timeoutEnvVar)
+			}
+			flags := NewWaitFlags(nil, genericclioptions.NewTestIOStreamsDiscard())
+			cmd := &cobra.Command{}
+			flags.AddFlags(cmd)
+			if err := cmd.ParseFlags(test.flags); err != nil {
+				t.Fatal(err)
+			}
+
+			err := flags.timeoutFromEnv(cmd)
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if flags.Timeout != test.expectedTimeout {
+				t.Errorf("expected a timeout of %v, got %v", test.expectedTimeout, flags.Timeout)
+			}
+		})
+	}
+}
+
+func TestWaitUntil(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		status  string
+		timeout time.Duration
+		until   time.Duration
+
+		expectedErr   string
+		expectedPolls int
+	}{
+		{
+			name:        "deadline before the timeout",
+			status:      "False",
+			timeout:     time.Minute,
+			until:       100 * time.Millisecond,
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:        "deadline without a timeout",
+			status:      "False",
+			until:       100 * time.Millisecond,
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:        "timeout before the deadline",
+			status:      "False",
+			timeout:     100 * time.Millisecond,
+			until:       time.Hour,
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:          "deadline passed and condition met",
+			status:        "True",
+			timeout:       time.Minute,
+			until:         -time.Hour,
+			expectedErr:   None,
+			expectedPolls: 1,
+		},
+		{
+			name:          "deadline passed",
+			status:        "False",
+			timeout:       time.Minute,
+			until:         -time.Hour,
+			expectedErr:   "had already passed: condition not met on theresource/name-foo: expected condition Ready (last observed: False) to be true",
+			expectedPolls: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", test.status)), nil
+			})
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+				Until:          time.Now().Add(test.until),
+				PollInterval:   10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: ConditionalWait{conditionName: "Ready", conditionStatus: "true"}.IsConditionMet,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != ExitCodeTimeout {
+					t.Errorf("expected exit code %d, got %d", ExitCodeTimeout, code)
+				}
+			}
+			if result.Elapsed > 10*time.Second {
+				t.Errorf("expected the wait to give up by the earlier of the deadline and the timeout, took %v", result.Elapsed)
+			}
+			if test.expectedPolls > 0 && result.Polls != test.expectedPolls {
+				t.Errorf("expected %d checks, got %d", test.expectedPolls, result.Polls)
+			}
+		})
+	}
+
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+	flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+	flags.ForConditions = []string{"condition=Ready"}
+	flags.Deadline = "tomorrow"
+	if _, err := flags.ToOptions([]string{"pod/foo"}); err == nil || !strings.Contains(err.Error(), `--deadline must be an RFC3339 time such as 2024-01-01T00:00:00Z, got "tomorrow"`) {
+		t.Errorf("expected an invalid --deadline to be rejected, got %v", err)
+	}
+	flags.Deadline = "2024-01-01T00:00:00Z"
+	o, err := flags.ToOptions([]string{"pod/foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !o.Until.Equal(expected) {
+		t.Errorf("expected the wait to give up at %v, got %v", expected, o.Until)
+	}
+}
+
+func TestWaitAggregatesErrors(t *testing.T) {
+	var infos []*resource.Info
+	for _, name := range []string{"ready", "slow", "failed", "slower"} {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+		})
+	}
+	check := func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+		switch info.Name {
+		case "slow", "slower":
+			return nil, false, &TimeoutError{Resource: "pods", Name: info.Name}
+		case "failed":
+			return nil, false, newConditionUnmetError(info, "the pod failed")
+		}
+		return newUnstructured("v1", "Pod", info.Namespace, info.Name), true, nil
+	}
+
+	tests := []struct {
+		name        string
+		infos       []*resource.Info
+		concurrency int
+
+		expectedErrs []string
+		expectedMet  map[string]bool
+		exitCode     int
+	}{
+		{
+			name:        "sequential",
+			infos:       infos,
+			concurrency: 1,
+			expectedErrs: []string{
+				"timed out waiting for the condition on pods/slow",
+				"condition unsatisfied on pods/failed: the pod failed",
+				"timed out waiting for the condition on pods/slower",
+			},
+			expectedMet: map[string]bool{"ready": true, "slow": false, "failed": false, "slower": false},
+			exitCode:    1,
+		},
+		{
+			name:        "concurrent",
+			infos:       infos,
+			concurrency: 4,
+			expectedErrs: []string{
+				"timed out waiting for the condition on pods/slow",
+				"condition unsatisfied on pods/failed: the pod failed",
+				"timed out waiting for the condition on pods/slower",
+			},
+			expectedMet: map[string]bool{"ready": true, "slow": false, "failed": false, "slower": false},
+			exitCode:    1,
+		},
+		{
+			name:        "only timeouts",
+			infos:       []*resource.Info{infos[0], infos[1], infos[3]},
+			concurrency: 1,
+			expectedErrs: []string{
+				"timed out waiting for the condition on pods/slow",
+				"timed out waiting for the condition on pods/slower",
+			},
+			expectedMet: map[string]bool{"ready": true, "slow": false, "slower": false},
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:         "a single failure is returned as is",
+			infos:        []*resource.Info{infos[0], infos[2]},
+			concurrency:  1,
+			expectedErrs: []string{"condition unsatisfied on pods/failed: the pod failed"},
+			expectedMet:  map[string]bool{"ready": true, "failed": false},
+			exitCode:     ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.infos...),
+				Timeout:        10 * time.Second,
+				Concurrency:    test.concurrency,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: check,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			result, err := o.Wait(context.Background())
+
+			if err == nil {
+				t.Fatalf("missing: %q", test.expectedErrs)
+			}
+			for _, expectedErr := range test.expectedErrs {
+				if !strings.Contains(err.Error(), expectedErr) {
+					t.Errorf("expected %q in %q", expectedErr, err.Error())
+				}
+			}
+			code := 1
+			if exitErr, ok := exitErrorFor(err).(utilexec.ExitError); ok {
+				code = exitErr.ExitStatus()
+			}
+			if code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+			}
+			met := map[string]bool{}
+			for _, status := range result.Resources {
+				if status.Resource != "pods" || status.Namespace != "ns-foo" {
+					t.Errorf("unexpected resource %s in %s", status.Resource, status.Namespace)
+				}
+				if status.Met != (status.Err == nil) {
+					t.Errorf("%s: met is %t with error %v", status.Name, status.Met, status.Err)
+				}
+				met[status.Name] = status.Met
+			}
+			if !reflect.DeepEqual(met, test.expectedMet) {
+				t.Errorf("expected %v, got %v", test.expectedMet, met)
+			}
+			if len(result.Satisfied) != 1 {
+				t.Errorf("expected 1 satisfied, got %d", len(result.Satisfied))
+			}
+		})
+	}
+}
+
+func TestWaitForKey(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "secrets"}:     "SecretList",
+		{Version: "v1", Resource: "configmaps"}:  "ConfigMapList",
+		{Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}
+
+	tests := []struct {
+		name     string
+		resource string
+		data     map[string]interface{}
+		watched  map[string]interface{}
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:     "secret with the key",
+			resource: "secrets",
+			data: map[string]interface{}{
+				"data": map[string]interface{}{"tls.crt": "c2VjcmV0", "tls.key": "c2VjcmV0"},
+			},
+			expectedErr: None,
+		},
+		{
+			name:     "secret with the key in stringData",
+			resource: "secrets",
+			data: map[string]interface{}{
+				"stringData": map[string]interface{}{"tls.crt": "secret"},
+			},
+			expectedErr: None,
+		},
+		{
+			name:     "config map with the key in binaryData",
+			resource: "configmaps",
+			data: map[string]interface{}{
+				"binaryData": map[string]interface{}{"tls.crt": "c2VjcmV0"},
+			},
+			expectedErr: None,
+		},
+		{
+			name:     "key added while watching",
+			resource: "secrets",
+			data: map[string]interface{}{
+				"data": map[string]interface{}{"ca.crt": "c2VjcmV0"},
+			},
+			watched: map[string]interface{}{
+				"data": map[string]interface{}{"ca.crt": "c2VjcmV0", "tls.crt": "c2VjcmV0"},
+			},
+			expectedErr: None,
+		},
+		{
+			name:     "missing key",
+			resource: "secrets",
+			data: map[string]interface{}{
+				"data": map[string]interface{}{"tls.key": "c2VjcmV0", "ca.crt": "c2VjcmV0"},
+			},
+			expectedErr: "timed out waiting for the condition on secrets/name-foo: key tls.crt (last observed keys: ca.crt,tls.key) to be present",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "no data",
+			resource:    "secrets",
+			expectedErr: "timed out waiting for the condition on secrets/name-foo: key tls.crt (last observed keys: <none>) to be present",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "not a secret or config map",
+			resource:    "deployments",
+			expectedErr: "condition unsatisfied on deployments/name-foo: has-key only applies to secrets and configmaps",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			infos := []*resource.Info{
+				{
+					Mapping: &meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Version: "v1", Resource: test.resource},
+					},
+					Name:      "name-foo",
+					Namespace: "ns-foo",
+				},
+			}
+			newObj := func(data map[string]interface{}) *unstructured.Unstructured {
+				obj := newUnstructured("v1", "Secret", "ns-foo", "name-foo")
+				for field, value := range data {
+					obj.Object[field] = value
+				}
+				return obj
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", test.resource, func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newObj(test.data)), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor(test.resource, func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(newObj(test.watched))
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor("has-key=tls.crt", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if strings.Contains(err.Error(), "c2VjcmV0") {
+					t.Errorf("the value of a key was printed: %q", err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForMetadata(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}: "PodList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		condition   string
+		labels      map[string]string
+		annotations map[string]string
+		watched     map[string]string
+
+		expectedErr string
+	}{
+		{
+			name:        "value",
+			condition:   "label=environment=production",
+			labels:      map[string]string{"app": "nginx", "environment": "production"},
+			expectedErr: None,
+		},
+		{
+			name:        "present",
+			condition:   "label=sidecar.example.com/injected",
+			labels:      map[string]string{"sidecar.example.com/injected": "false"},
+			expectedErr: None,
+		},
+		{
+			name:        "empty value",
+			condition:   "label=environment=",
+			labels:      map[string]string{"environment": ""},
+			expectedErr: None,
+		},
+		{
+			name:        "stamped while watching",
+			condition:   "label=environment=production",
+			labels:      map[string]string{"app": "nginx"},
+			watched:     map[string]string{"app": "nginx", "environment": "production"},
+			expectedErr: None,
+		},
+		{
+			name:        "another value",
+			condition:   "label=environment=production",
+			labels:      map[string]string{"environment": "staging", "app": "nginx"},
+			expectedErr: "timed out waiting for the condition on pods/name-foo: label environment=production (last observed labels: app=nginx,environment=staging) to be set",
+		},
+		{
+			name:        "absent",
+			condition:   "label=environment",
+			expectedErr: "timed out waiting for the condition on pods/name-foo: label environment (last observed labels: <none>) to be present",
+		},
+		{
+			name:        "annotation value",
+			condition:   "annotation=example.com/state=done",
+			labels:      map[string]string{"example.com/state": "pending"},
+			annotations: map[string]string{"example.com/state": "done"},
+			expectedErr: None,
+		},
+		{
+			name:        "annotation present",
+			condition:   "annotation=example.com/state",
+			annotations: map[string]string{"example.com/state": ""},
+			expectedErr: None,
+		},
+		{
+			name:        "annotation not a label",
+			condition:   "label=example.com/state",
+			annotations: map[string]string{"example.com/state": "done"},
+			expectedErr: "label example.com/state (last observed labels: <none>) to be present",
+		},
+		{
+			name:      "another annotation value",
+			condition: "annotation=example.com/state=done",
+			annotations: map[string]string{
+				"example.com/state": "pending",
+				"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"name-foo","namespace":"ns-foo"}}`,
+			},
+			expectedErr: `annotation example.com/state=done (last observed annotations: example.com/state=pending,kubectl.kubernetes.io/last-applied-configuration={"apiVersion":"v1","kind":"Pod","metadata":{"name":"name-foo","n...) to be set`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newObj := func(labels map[string]string) *unstructured.Unstructured {
+				obj := newUnstructured("v1", "Pod", "ns-foo", "name-foo")
+				obj.SetLabels(labels)
+				obj.SetAnnotations(test.annotations)
+				return obj
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newObj(test.labels)), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("pods", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(newObj(test.watched))
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForOwner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}: "PodList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	replicaSet := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx-5d4f8"}
+	node := metav1.OwnerReference{APIVersion: "v1", Kind: "Node", Name: "node-1"}
+
+	tests := []struct {
+		name      string
+		condition string
+		owners    []metav1.OwnerReference
+		watched   []metav1.OwnerReference
+
+		expectedErr string
+	}{
+		{
+			name:        "owned",
+			condition:   "owned-by=ReplicaSet/nginx-5d4f8",
+			owners:      []metav1.OwnerReference{node, replicaSet},
+			expectedErr: None,
+		},
+		{
+			name:        "kind in lower case with a group",
+			condition:   "owned-by=replicaset.apps/nginx-5d4f8",
+			owners:      []metav1.OwnerReference{replicaSet},
+			expectedErr: None,
+		},
+		{
+			name:        "adopted while watching",
+			condition:   "owned-by=ReplicaSet/nginx-5d4f8",
+			watched:     []metav1.OwnerReference{replicaSet},
+			expectedErr: None,
+		},
+		{
+			name:        "another group",
+			condition:   "owned-by=replicaset.example.com/nginx-5d4f8",
+			owners:      []metav1.OwnerReference{replicaSet},
+			expectedErr: "timed out waiting for the condition on pods/name-foo: owner reference to replicaset.example.com/nginx-5d4f8 (last observed: ReplicaSet/nginx-5d4f8) to be set",
+		},
+		{
+			name:        "another owner",
+			condition:   "owned-by=ReplicaSet/nginx-7c9b2",
+			owners:      []metav1.OwnerReference{node, replicaSet},
+			expectedErr: "timed out waiting for the condition on pods/name-foo: owner reference to ReplicaSet/nginx-7c9b2 (last observed: Node/node-1,ReplicaSet/nginx-5d4f8) to be set",
+		},
+		{
+			name:        "no owners",
+			condition:   "owned-by=ReplicaSet/nginx-5d4f8",
+			expectedErr: "timed out waiting for the condition on pods/name-foo: owner reference to ReplicaSet/nginx-5d4f8 (last observed: <none>) to be set",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newObj := func(owners []metav1.OwnerReference) *unstructured.Unstructured {
+				obj := newUnstructured("v1", "Pod", "ns-foo", "name-foo")
+				obj.SetOwnerReferences(owners)
+				return obj
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newObj(test.owners)), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("pods", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(newObj(test.watched))
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			},
+			Name:      "nginx",
+			Namespace: "ns-foo",
+		},
+	}
+	newDeployment := func(readyReplicas int64) *unstructured.Unstructured {
+		obj := newUnstructured("apps/v1", "Deployment", "ns-foo", "nginx")
+		unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas")
+		if readyReplicas >= 0 {
+			unstructured.SetNestedField(obj.Object, readyReplicas, "status", "readyReplicas")
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name          string
+		template      string
+		readyReplicas int64
+		watched       *unstructured.Unstructured
+
+		expectedErr string
+	}{
+		{
+			name:          "renders true",
+			template:      "{{ ge .status.readyReplicas .spec.replicas }}",
+			readyReplicas: 3,
+			expectedErr:   None,
+		},
+		{
+			name:          "renders true with whitespace",
+			template:      "\n{{ if eq .metadata.name \"nginx\" }} true {{ end }}",
+			readyReplicas: 3,
+			expectedErr:   None,
+		},
+		{
+			name:          "renders true while watching",
+			template:      "{{ ge .status.readyReplicas .spec.replicas }}",
+			readyReplicas: 1,
+			watched:       newDeployment(3),
+			expectedErr:   None,
+		},
+		{
+			name:          "renders false",
+			template:      "{{ ge .status.readyReplicas .spec.replicas }}",
+			readyReplicas: 1,
+			expectedErr:   "timed out waiting for the condition on deployments/nginx: template {{ ge .status.readyReplicas .spec.replicas }} (last observed: false) to render true",
+		},
+		{
+			name:          "renders something else",
+			template:      "{{ .status.readyReplicas }}",
+			readyReplicas: 3,
+			expectedErr:   "template {{ .status.readyReplicas }} (last observed: 3) to render true",
+		},
+		{
+			name:          "fails to execute on a missing field",
+			template:      "{{ ge .status.readyReplicas .spec.replicas }}",
+			readyReplicas: -1,
+			expectedErr:   "error calling ge",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "deployments", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newDeployment(test.readyReplicas)), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("deployments", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(test.watched)
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor("template="+test.template, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForReady(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions [][2]string
+		watched    [][2]string
+
+		expectedErr string
+	}{
+		{
+			name:        "ready",
+			conditions:  [][2]string{{"Ready", "True"}},
+			expectedErr: None,
+		},
+		{
+			name:        "available",
+			conditions:  [][2]string{{"Progressing", "True"}, {"Available", "True"}},
+			expectedErr: None,
+		},
+		{
+			name:        "healthy",
+			conditions:  [][2]string{{"Healthy", "True"}},
+			expectedErr: None,
+		},
+		{
+			name:        "ready takes precedence over synced",
+			conditions:  [][2]string{{"Synced", "True"}, {"Ready", "False"}},
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: the first of the conditions Ready, Available, Synced, Healthy (last observed: Synced=True,Ready=False) to be true",
+		},
+		{
+			name:        "ready while watching",
+			conditions:  [][2]string{{"Ready", "False"}},
+			watched:     [][2]string{{"Ready", "True"}},
+			expectedErr: None,
+		},
+		{
+			name:        "no readiness condition",
+			conditions:  [][2]string{{"Progressing", "True"}},
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: the first of the conditions Ready, Available, Synced, Healthy (last observed: <none>) to be true",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newObj := func(conditions [][2]string) *unstructured.Unstructured {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				for _, condition := range conditions {
+					addCondition(obj, condition[0], condition[1])
+				}
+				return obj
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newObj(test.conditions)), nil
+			})
+			if test.watched != nil {
+				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+					fakeWatch := watch.NewRaceFreeFake()
+					fakeWatch.Modify(newObj(test.watched))
+					return true, fakeWatch, nil
+				})
+			}
+			conditionFn, err := conditionFuncFor("ready", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("custom condition types", func(t *testing.T) {
+		w := NewReadyWait("Established")
+		met, err := w.checkCondition(addCondition(addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "False"), "Established", "True"))
+		if err != nil || !met {
+			t.Errorf("expected Established to be met, got %t, %v", met, err)
+		}
+	})
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+
+		expected    conditionSpec
+		expectedErr string
+	}{
+		{condition: "delete", expected: conditionSpec{kind: conditionKindDelete}},
+		{condition: "Delete", expected: conditionSpec{kind: conditionKindDelete}},
+		{condition: "create", expected: conditionSpec{kind: conditionKindCreate}},
+		{condition: "synced", expected: conditionSpec{kind: conditionKindSynced}},
+		{condition: "bound", expected: conditionSpec{kind: conditionKindBound}},
+		{condition: "ready", expected: conditionSpec{kind: conditionKindReady}},
+		{condition: "no-finalizers", expected: conditionSpec{kind: conditionKindNoFinalizers}},
+		{condition: "job-complete", expected: conditionSpec{kind: conditionKindJobComplete}},
+		{condition: "hpa-stable", expected: conditionSpec{kind: conditionKindHPAStable}},
+		{condition: "rollout", expected: conditionSpec{kind: conditionKindRollout}},
+		{condition: "containers-ready", expected: conditionSpec{kind: conditionKindContainersReady}},
+		{condition: "containers-ready=2", expected: conditionSpec{kind: conditionKindContainersReady, count: 2}},
+		{condition: "container-ready=app", expected: conditionSpec{kind: conditionKindContainerReady, container: "app"}},
+		{condition: "container-ready=", expectedErr: "container-ready requires the name of a container"},
+		{condition: "containers-ready=none", expectedErr: `containers-ready count "none" must be a positive integer`},
+		{condition: "init-complete", expected: conditionSpec{kind: conditionKindInitComplete}},
+		{condition: "init-complete=2", expected: conditionSpec{kind: conditionKindInitComplete, count: 2}},
+		{condition: "established", expected: conditionSpec{kind: conditionKindEstablished}},
+		{condition: "persists", expected: conditionSpec{kind: conditionKindPersists}},
+		{condition: "has-endpoints", expected: conditionSpec{kind: conditionKindHasEndpoints}},
+		{condition: "has-endpoints=3", expected: conditionSpec{kind: conditionKindHasEndpoints, count: 3}},
+		{condition: "has-endpoints=some", expectedErr: `has-endpoints count "some" must be a positive integer`},
+		{condition: "new-replicaset-ready=2", expected: conditionSpec{kind: conditionKindNewReplicaSetReady, count: 2}},
+		{condition: "new-replicaset-ready=0", expectedErr: `new-replicaset-ready count "0" must be a positive integer`},
+		{condition: "init-complete=-1", expectedErr: `init-complete count "-1" must be a positive integer`},
+		{condition: "has-key=tls.crt", expected: conditionSpec{kind: conditionKindHasKey, key: "tls.crt"}},
+		{condition: "has-key=", expectedErr: "has-key requires a key"},
+		{condition: "label=environment=production", expected: conditionSpec{kind: conditionKindHasLabel, metadataKey: "environment", metadataValue: "production", metadataHasValue: true}},
+		{condition: "label=app.kubernetes.io/name", expected: conditionSpec{kind: conditionKindHasLabel, metadataKey: "app.kubernetes.io/name"}},
+		{condition: "label=environment=", expected: conditionSpec{kind: conditionKindHasLabel, metadataKey: "environment", metadataHasValue: true}},
+		{condition: "label=", expectedErr: "label requires a key"},
+		{condition: "label==production", expectedErr: "label requires a key"},
+		{condition: "annotation=example.com/state=done", expected: conditionSpec{kind: conditionKindHasAnnotation, metadataKey: "example.com/state", metadataValue: "done", metadataHasValue: true}},
+		{condition: "annotation=example.com/checksum=a=b", expected: conditionSpec{kind: conditionKindHasAnnotation, metadataKey: "example.com/checksum", metadataValue: "a=b", metadataHasValue: true}},
+		{condition: "annotation=example.com/state", expected: conditionSpec{kind: conditionKindHasAnnotation, metadataKey: "example.com/state"}},
+		{condition: "annotation=", expectedErr: "annotation requires a key"},
+		{condition: "owned-by=ReplicaSet/nginx-5d4f8", expected: conditionSpec{kind: conditionKindOwnedBy, ownerKind: "ReplicaSet", ownerName: "nginx-5d4f8"}},
+		{condition: "owned-by=replicaset.apps/nginx-5d4f8", expected: conditionSpec{kind: conditionKindOwnedBy, ownerKind: "replicaset.apps", ownerName: "nginx-5d4f8"}},
+		{condition: "owned-by=nginx-5d4f8", expectedErr: "owned-by requires the kind and the name of the owner"},
+		{condition: "owned-by=ReplicaSet/", expectedErr: "owned-by requires the kind and the name of the owner"},
+		{
+			condition: "condition=Ready",
+			expected:  conditionSpec{kind: conditionKindCondition, conditionName: "Ready", conditionStatus: "true"},
+		},
+		{
+			condition: "condition=Ready=False",
+			expected:  conditionSpec{kind: conditionKindCondition, conditionName: "Ready", conditionStatus: "False"},
+		},
+		{
+			condition: "condition=Available=true,reason=MinimumReplicasAvailable",
+			expected:  conditionSpec{kind: conditionKindCondition, conditionName: "Available", conditionStatus: "true", conditionReason: "MinimumReplicasAvailable"},
+		},
+		{condition: "condition=Available,reason=", expectedErr: "condition reason cannot be empty"},
+		{
+			condition: "condition=Ready=True,Unknown",
+			expected:  conditionSpec{kind: conditionKindCondition, conditionName: "Ready", conditionStatus: "True,Unknown"},
+		},
+		{condition: "condition=Ready=True,", expectedErr: `condition status cannot be empty in "True,"`},
+		{
+			condition: "template={{ .status.ready }}",
+			expected:  conditionSpec{kind: conditionKindTemplate, template: "{{ .status.ready }}"},
+		},
+		{
+			condition: "jsonpath={.metadata.resourceVersion}changed",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.metadata.resourceVersion}", jsonPathOperator: "changed"},
+		},
+		{
+			condition: "jsonpath={.status.phase}=Running",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.phase}", jsonPathOperator: "=", jsonPathCondition: "Running"},
+		},
+		{
+			condition: "jsonpath-cmp={.status.readyReplicas}>={.spec.replicas}",
+			expected:  conditionSpec{kind: conditionKindJSONPathCmp, jsonPathExpression: "{.status.readyReplicas}", jsonPathOperator: ">=", jsonPathCondition: "{.spec.replicas}"},
+		},
+		{
+			condition: "rollout-settled",
+			expected:  conditionSpec{kind: conditionKindRolloutSettled},
+		},
+		{
+			condition: "rollout-settled=any-generation",
+			expected:  conditionSpec{kind: conditionKindRolloutSettled, anyGeneration: true},
+		},
+		{
+			condition: "jsonpath-all={.status.containerStatuses[*].ready}=true",
+			expected:  conditionSpec{kind: conditionKindJSONPathAll, jsonPathExpression: "{.status.containerStatuses[*].ready}", jsonPathOperator: "=", jsonPathCondition: "true"},
+		},
+		{
+			condition: "jsonpath-any={.status.containerStatuses[*].restartCount}>0",
+			expected:  conditionSpec{kind: conditionKindJSONPathAny, jsonPathExpression: "{.status.containerStatuses[*].restartCount}", jsonPathOperator: ">", jsonPathCondition: "0"},
+		},
+		{
+			condition: "jsonpath={.metadata.labels.shard}covers=0,1,2",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.metadata.labels.shard}", jsonPathOperator: "covers", jsonPathCondition: "0,1,2"},
+		},
+		{
+			condition: "jsonpath={.status.phase}==Running",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.phase}", jsonPathOperator: "=", jsonPathCondition: "Running"},
+		},
+		{
+			condition: "jsonpath={.status.url}=https://host/path?a=b",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.url}", jsonPathOperator: "=", jsonPathCondition: "https://host/path?a=b"},
+		},
+		{
+			condition: "jsonpath={.status.readyReplicas}>=3",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.readyReplicas}", jsonPathOperator: ">=", jsonPathCondition: "3"},
+		},
+		{
+			condition: "jsonpath={.status.readyReplicas}<3",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.readyReplicas}", jsonPathOperator: "<", jsonPathCondition: "3"},
+		},
+		{
+			condition: "jsonpath={.status.conditions}#>=2",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.conditions}", jsonPathOperator: "#>=", jsonPathCondition: "2"},
+		},
+		{
+			condition: "jsonpath={.status.conditions[*]}#>2",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.conditions[*]}", jsonPathOperator: "#>", jsonPathCondition: "2"},
+		},
+		{
+			condition: `jsonpath={.status.version}~=^v1\.27\.`,
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.version}", jsonPathOperator: "~=", jsonPathCondition: `^v1\.27\.`},
+		},
+		{
+			condition: "jsonpath={.status.message}*=ready",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.message}", jsonPathOperator: "*=", jsonPathCondition: "ready"},
+		},
+		{
+			condition: `jsonpath={.status.conditions[?(@.type=="Ready")].status}!=False`,
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: `{.status.conditions[?(@.type=="Ready")].status}`, jsonPathOperator: "!=", jsonPathCondition: "False"},
+		},
+		{
+			condition: "jsonpath={.metadata.creationTimestamp}>age:30s",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.metadata.creationTimestamp}", jsonPathOperator: ">", jsonPathCondition: "age:30s"},
+		},
+		{
+			condition: "jsonpath={.status.loadBalancer.ingress[0].ip}",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.status.loadBalancer.ingress[0].ip}", jsonPathOperator: "exists"},
+		},
+		{
+			condition: "jsonpath=!{.metadata.finalizers}",
+			expected:  conditionSpec{kind: conditionKindJSONPath, jsonPathExpression: "{.metadata.finalizers}", jsonPathOperator: "absent"},
+		},
+		{
+			condition: "condition!=Ready",
+			expected:  conditionSpec{kind: conditionKindCondition, conditionName: "Ready", conditionStatus: "True", conditionNegated: true},
+		},
+		{condition: "condition!=", expectedErr: "condition!= requires a condition name"},
+		{condition: "condition!=Ready=False", expectedErr: "condition!=Ready does not take a status, it waits for any status other than True"},
+		{condition: "condition!=Ready,reason=Foo", expectedErr: "condition!=Ready cannot require a reason"},
+		{condition: "foo", expectedErr: `unrecognized condition: "foo"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.condition, func(t *testing.T) {
+			spec, err := parseCondition(test.condition)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if spec != test.expected {
+				t.Fatalf("expected %#v, got %#v", test.expected, spec)
+			}
+			reparsed, err := parseCondition(spec.String())
+			if err != nil {
+				t.Fatalf("%q does not parse again: %v", spec.String(), err)
+			}
+			if reparsed != spec {
+				t.Errorf("%q parses as %#v, expected %#v", spec.String(), reparsed, spec)
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathBool(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+
+		expectedErr string
+	}{
+		{
+			name:        "true",
+			condition:   "jsonpath={.status.ready}=true",
+			expectedErr: None,
+		},
+		{
+			name:        "True",
+			condition:   "jsonpath={.status.ready}=True",
+			expectedErr: None,
+		},
+		{
+			name:        "1",
+			condition:   "jsonpath={.status.ready}=1",
+			expectedErr: None,
+		},
+		{
+			name:        "any of several values",
+			condition:   "jsonpath={.status.ready}=yes|TRUE",
+			expectedErr: None,
+		},
+		{
+			name:        "false",
+			condition:   "jsonpath={.status.ready}=False",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.ready} (last observed: true) to be False",
+		},
+		{
+			name:        "not a boolean",
+			condition:   "jsonpath={.status.ready}=yes",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:        "not equal",
+			condition:   "jsonpath={.status.ready}!=0",
+			expectedErr: None,
+		},
+		{
+			name:        "a string is still compared as a string",
+			condition:   "jsonpath={.status.phase}=true",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: {.status.phase} (last observed: True) to be true",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+				obj.Object["status"] = map[string]interface{}{
+					"ready": true,
+					"phase": "True",
+				}
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestJSONPathSyntaxError(t *testing.T) {
+	const hint = "\nExpressions look like {.status.phase} or {.status.conditions[?(@.type==\"Ready\")].status}; " +
+		"the braces and the leading dot may be left out, as in status.phase."
+	tests := []struct {
+		name      string
+		condition string
+
+		expectedErr string
+	}{
+		{
+			name:      "unterminated array",
+			condition: "jsonpath={.status.conditions[0}=True",
+			expectedErr: `jsonpath expression "{.status.conditions[0}" is not valid: unterminated array` +
+				"\n  {.status.conditions[0}" +
+				"\n                       ^" + hint,
+		},
+		{
+			name:      "unrecognized character",
+			condition: "jsonpath={.spec.containers[0];}=nginx",
+			expectedErr: `jsonpath expression "{.spec.containers[0];}" is not valid: unrecognized character in action: U+003B ';'` +
+				"\n  {.spec.containers[0];}" +
+				"\n                      ^" + hint,
+		},
+		{
+			name:      "unrecognized character in the compared expression",
+			condition: "jsonpath={.status.readyReplicas}={.spec.replicas[0]!}",
+			expectedErr: `jsonpath expression "{.spec.replicas[0]!}" is not valid: unrecognized character in action: U+0021 '!'` +
+				"\n  {.spec.replicas[0]!}" +
+				"\n                    ^" + hint,
+		},
+		{
+			name:      "missing closing brace",
+			condition: "jsonpath={.status.phase=Running",
+			expectedErr: `jsonpath expression "{.status.phase" is not valid` +
+				"\nThe expression is missing a closing }, as in {.status.phase}." + hint,
+		},
+		{
+			name:      "missing opening brace",
+			condition: "jsonpath=.status.phase}=Running",
+			expectedErr: `jsonpath expression ".status.phase}" is not valid` +
+				"\nThe expression is missing an opening {, as in {.status.phase}." + hint,
+		},
+		{
+			name:      "several expressions",
+			condition: "jsonpath={.status.phase}{.status.reason}=Running",
+			expectedErr: `jsonpath expression "{.status.phase}{.status.reason}" is not valid` +
+				"\nOnly one {...} expression can be waited for at a time." + hint,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := conditionFuncFor(test.condition, false)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", test.expectedErr)
+			}
+			if err.Error() != test.expectedErr {
+				t.Errorf("expected error:\n%s\ngot:\n%s", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestWaitMixedKinds(t *testing.T) {
+	newInfo := func(gvk schema.GroupVersionKind, resourceName, name string) *resource.Info {
+		return &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource:         gvk.GroupVersion().WithResource(resourceName),
+				GroupVersionKind: gvk,
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+		}
+	}
+	pod := newInfo(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "pods", "busybox1")
+	deployment := newInfo(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, "deployments", "nginx")
+	job := newInfo(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, "jobs", "pi")
+	secret := newInfo(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, "secrets", "my-tls")
+
+	tests := []struct {
+		name       string
+		conditions []string
+		mode       WaitMode
+		infos      []*resource.Info
+
+		expectedChecked []string
+		expectedErr     string
+		exitCode        int
+	}{
+		{
+			name:            "generic condition on every kind",
+			conditions:      []string{"condition=Ready"},
+			infos:           []*resource.Info{pod, deployment, job},
+			expectedChecked: []string{"busybox1", "nginx", "pi"},
+			expectedErr:     None,
+		},
+		{
+			name:            "delete on every kind",
+			conditions:      []string{"delete"},
+			infos:           []*resource.Info{pod, deployment, secret},
+			expectedChecked: []string{"busybox1", "nginx", "my-tls"},
+			expectedErr:     None,
+		},
+		{
+			name:            "rollout on a pod",
+			conditions:      []string{"rollout"},
+			infos:           []*resource.Info{pod, deployment},
+			expectedChecked: []string{"nginx"},
+			expectedErr:     "condition unsatisfied on pods/busybox1: rollout only applies to deployments, daemonsets and statefulsets, not Pod",
+			exitCode:        ExitCodeConditionUnmet,
+		},
+		{
+			name:            "rollout on a pod in any mode",
+			conditions:      []string{"rollout"},
+			mode:            WaitModeAny,
+			infos:           []*resource.Info{pod, deployment},
+			expectedChecked: []string{"nginx"},
+			expectedErr:     None,
+		},
+		{
+			name:            "job-complete on a deployment",
+			conditions:      []string{"job-complete"},
+			infos:           []*resource.Info{job, deployment},
+			expectedChecked: []string{"pi"},
+			expectedErr:     "condition unsatisfied on deployments/nginx: job-complete only applies to jobs, not Deployment.apps",
+			exitCode:        ExitCodeConditionUnmet,
+		},
+		{
+			name:        "hpa-stable on a deployment",
+			conditions:  []string{"hpa-stable"},
+			infos:       []*resource.Info{deployment},
+			expectedErr: "condition unsatisfied on deployments/nginx: hpa-stable only applies to horizontalpodautoscalers, not Deployment.apps",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "established on a deployment",
+			conditions:  []string{"established"},
+			infos:       []*resource.Info{deployment},
+			expectedErr: "condition unsatisfied on deployments/nginx: established only applies to customresourcedefinitions, not Deployment.apps",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "has-endpoints on a pod",
+			conditions:  []string{"has-endpoints"},
+			infos:       []*resource.Info{pod},
+			expectedErr: "condition unsatisfied on pods/busybox1: has-endpoints only applies to services, not Pod",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "rollout-settled on a job",
+			conditions:  []string{"rollout-settled"},
+			infos:       []*resource.Info{job},
+			expectedErr: "condition unsatisfied on jobs/pi: rollout-settled only applies to deployments, not Job.batch",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "new-replicaset-ready on a pod",
+			conditions:  []string{"new-replicaset-ready=2"},
+			infos:       []*resource.Info{pod},
+			expectedErr: "condition unsatisfied on pods/busybox1: new-replicaset-ready only applies to deployments, not Pod",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:            "has-key on a pod",
+			conditions:      []string{"has-key=tls.crt"},
+			infos:           []*resource.Info{secret, pod},
+			expectedChecked: []string{"my-tls"},
+			expectedErr:     "condition unsatisfied on pods/busybox1: has-key only applies to secrets and configmaps, not Pod",
+			exitCode:        ExitCodeConditionUnmet,
+		},
+		{
+			name:        "container-ready on a job",
+			conditions:  []string{"container-ready=app"},
+			infos:       []*resource.Info{job},
+			expectedErr: "condition unsatisfied on jobs/pi: container-ready only applies to pods, not Job.batch",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:            "one of several conditions on the wrong kind",
+			conditions:      []string{"condition=Ready", "containers-ready"},
+			infos:           []*resource.Info{pod, job},
+			expectedChecked: []string{"busybox1"},
+			expectedErr:     "condition unsatisfied on jobs/pi: containers-ready only applies to pods, not Job.batch",
+			exitCode:        ExitCodeConditionUnmet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditionFnFor, err := conditionFnForKinds(test.conditions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var checked []string
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.infos...),
+				Timeout:        10 * time.Second,
+				Mode:           test.mode,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					checked = append(checked, info.Name)
+					return newUnstructured(info.Mapping.GroupVersionKind.GroupVersion().String(), info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name), true, nil
+				},
+				ConditionFnFor: conditionFnFor,
+				IOStreams:      genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			_, err = o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+			if !reflect.DeepEqual(checked, test.expectedChecked) {
+				t.Errorf("expected %v to be checked, got %v", test.expectedChecked, checked)
+			}
+		})
+	}
+}
+
+func TestWaitCheckNow(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []string
+		obj        *unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "condition met",
+			conditions:  []string{"condition=Ready"},
+			obj:         addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "True"),
+			expectedErr: None,
+		},
+		{
+			name:        "condition not met",
+			conditions:  []string{"condition=Ready"},
+			obj:         addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "False"),
+			expectedErr: "condition not met on theresource/name-foo: expected condition Ready (last observed: False) to be true",
+			exitCode:    ExitCodeNotMet,
+		},
+		{
+			name:        "resource gone",
+			conditions:  []string{"condition=Ready"},
+			expectedErr: "condition not met on theresource/name-foo: expected condition Ready (last observed: <none>) to be true",
+			exitCode:    ExitCodeNotMet,
+		},
+		{
+			name:        "one of several conditions not met",
+			conditions:  []string{"condition=Ready", "jsonpath={.status.phase}=Running"},
+			obj:         addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "True"),
+			expectedErr: "condition not met on theresource/name-foo: expected {.status.phase} (last observed: <none>) to be Running (unsatisfied condition: jsonpath={.status.phase}=Running)",
+			exitCode:    ExitCodeNotMet,
+		},
+		{
+			name:        "deleted",
+			conditions:  []string{"delete"},
+			expectedErr: None,
+		},
+		{
+			name:        "not deleted",
+			conditions:  []string{"delete"},
+			obj:         newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+			expectedErr: "condition not met on theresource/name-foo: expected deletion (last observed: present)",
+			exitCode:    ExitCodeNotMet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if test.obj == nil {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(test.obj), nil
+			})
+			var conditionFn ConditionFunc
+			var err error
+			if len(test.conditions) > 1 {
+				conditionFn, err = allConditionsFuncFor(test.conditions, false)
+			} else {
+				conditionFn, err = conditionFuncFor(test.conditions[0], false)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        time.Hour,
+				CheckNow:       true,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+			for _, action := range fakeClient.Actions() {
+				if !action.Matches("list", "theresource") {
+					t.Errorf("expected the resource to only be listed, got %s", action.GetVerb())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitFlagsStdinResourceFinder(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+
+	manifest, err := ioutil.TempFile("", "wait-manifest-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(manifest.Name())
+	if _, err := manifest.WriteString("apiVersion: v1\nkind: Service\nmetadata:\n  name: nginx\n"); err != nil {
+		t.Fatal(err)
+	}
+	manifest.Close()
+
+	streams, in, _, _ := genericclioptions.NewTestIOStreams()
+	in.WriteString("apiVersion: v1\nkind: Pod\nmetadata:\n  name: busybox1\n---\napiVersion: v1\nkind: ReplicationController\nmetadata:\n  name: rc1\n")
+	flags := NewWaitFlags(tf, streams)
+	*flags.ResourceBuilderFlags.FileNameFlags.Filenames = []string{"-", manifest.Name()}
+	builderFlags := *flags.ResourceBuilderFlags
+	builderFlags.Latest = false
+
+	finder, err := flags.resourceFinder(&builderFlags, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Len() != 0 {
+		t.Errorf("expected stdin to be read up front, %d bytes are left", in.Len())
+	}
+	expected := []string{"Pod test/busybox1", "ReplicationController test/rc1", "Service test/nginx"}
+	// the resources are looked up again, for instance with --wait-for-resources, and stdin
+	// must not have to be read twice
+	for i := 0; i < 2; i++ {
+		var found []string
+		err := finder.Do().Visit(func(info *resource.Info, err error) error {
+			if err != nil {
+				return err
+			}
+			found = append(found, fmt.Sprintf("%s %s/%s", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(found)
+		if !reflect.DeepEqual(found, expected) {
+			t.Errorf("lookup %d: expected %v, got %v", i+1, expected, found)
+		}
+	}
+}
+
+func TestWaitFlagsChunkedResourceFinder(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+
+	// the pods are listed two at a time, the way a server chunks a long list
+	pages := map[string]struct {
+		names []string
+		next  string
+	}{
+		"":       {names: []string{"pod-1", "pod-2"}, next: "page-2"},
+		"page-2": {names: []string{"pod-3", "pod-4"}, next: "page-3"},
+		"page-3": {names: []string{"pod-5"}},
+	}
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "GET" || req.URL.Path != "/api/v1/namespaces/test/pods" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL)
+			http.NotFound(w, req)
+			return
+		}
+		limits = append(limits, req.URL.Query().Get("limit"))
+		page, ok := pages[req.URL.Query().Get("continue")]
+		if !ok {
+			t.Errorf("unexpected continue token in %s", req.URL)
+			http.NotFound(w, req)
+			return
+		}
+		var items []string
+		for _, name := range page.names {
+			items = append(items, fmt.Sprintf(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":%q,"namespace":"test"}}`, name))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"apiVersion":"v1","kind":"PodList","metadata":{"continue":%q},"items":[%s]}`, page.next, strings.Join(items, ","))
+	}))
+	defer server.Close()
+	tf.ClientConfigVal = &restclient.Config{Host: server.URL}
+
+	getter := discoveryClientGetter{
+		RESTClientGetter: tf,
+		discovery:        memory.NewMemCacheClient(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}),
+	}
+	flags := NewWaitFlags(getter, genericclioptions.NewTestIOStreamsDiscard())
+	flags.ChunkSize = 2
+	builderFlags := *flags.ResourceBuilderFlags
+	builderFlags.Latest = false
+	*builderFlags.All = true
+
+	finder, err := flags.resourceFinder(&builderFlags, []string{"pods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	err = finder.Do().Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+		found = append(found, info.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"pod-1", "pod-2", "pod-3", "pod-4", "pod-5"}; !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got %v", expected, found)
+	}
+	if expected := []string{"2", "2", "2"}; !reflect.DeepEqual(limits, expected) {
+		t.Errorf("expected the pods to be listed with limits %v, got %v", expected, limits)
+	}
+}
+
+func TestWaitFlagsFieldSelector(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+
+	var fieldSelectors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "GET" || req.URL.Path != "/api/v1/namespaces/test/pods" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL)
+			http.NotFound(w, req)
+			return
+		}
+		fieldSelectors = append(fieldSelectors, req.URL.Query().Get("fieldSelector"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"apiVersion":"v1","kind":"PodList","metadata":{},"items":[{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod-1","namespace":"test"}}]}`)
+	}))
+	defer server.Close()
+	tf.ClientConfigVal = &restclient.Config{Host: server.URL}
+
+	getter := discoveryClientGetter{
+		RESTClientGetter: tf,
+		discovery:        memory.NewMemCacheClient(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}),
+	}
+	flags := NewWaitFlags(getter, genericclioptions.NewTestIOStreamsDiscard())
+	*flags.ResourceBuilderFlags.FieldSelector = "status.phase!=Succeeded"
+	builderFlags := *flags.ResourceBuilderFlags
+	builderFlags.Latest = false
+
+	finder, err := flags.resourceFinder(&builderFlags, []string{"pods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	err = finder.Do().Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+		found = append(found, info.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"pod-1"}; !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got %v", expected, found)
+	}
+	if expected := []string{"status.phase!=Succeeded"}; !reflect.DeepEqual(fieldSelectors, expected) {
+		t.Errorf("expected the pods to be listed with the field selectors %v, got %v", expected, fieldSelectors)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+		*flags.ResourceBuilderFlags.FieldSelector = "status.phase"
+		flags.ForConditions = []string{"condition=Ready"}
+		_, err := flags.ToOptions([]string{"pods"})
+		if err == nil || !strings.Contains(err.Error(), `invalid --field-selector "status.phase"`) {
+			t.Fatalf("expected the field selector to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestWaitFlagsExclude(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+
+	tests := []struct {
+		name    string
+		exclude []string
+
+		expected    []ResourceLocation
+		expectedErr string
+	}{
+		{
+			name:        "none",
+			expectedErr: None,
+		},
+		{
+			name:    "several",
+			exclude: []string{"pod/foo", "deployments.apps/bar"},
+			expected: []ResourceLocation{
+				{GroupResource: schema.GroupResource{Resource: "pods"}, Name: "foo"},
+				{GroupResource: schema.GroupResource{Group: "apps", Resource: "deployments"}, Name: "bar"},
+			},
+			expectedErr: None,
+		},
+		{
+			name:        "missing name",
+			exclude:     []string{"pod/"},
+			expectedErr: `--exclude must list resources as TYPE/NAME, such as pod/foo, got "pod/"`,
+		},
+		{
+			name:        "missing type",
+			exclude:     []string{"foo"},
+			expectedErr: `--exclude must list resources as TYPE/NAME, such as pod/foo, got "foo"`,
+		},
+		{
+			name:        "unknown type",
+			exclude:     []string{"unknown/foo"},
+			expectedErr: "unable to exclude unknown/foo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+			flags.ForConditions = []string{"condition=Ready"}
+			flags.Exclude = test.exclude
+			o, err := flags.ToOptions([]string{"pods"})
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(o.Exclude, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, o.Exclude)
+			}
+		})
+	}
+}
+
+func TestWaitFlagsMinReady(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test")
+	defer tf.Cleanup()
+
+	tests := []struct {
+		name string
+		args []string
+
+		expectedSettle time.Duration
+		expectedErr    string
+	}{
+		{
+			name:           "min-ready",
+			args:           []string{"--min-ready=10s"},
+			expectedSettle: 10 * time.Second,
+			expectedErr:    None,
+		},
+		{
+			name:           "settle",
+			args:           []string{"--settle=10s"},
+			expectedSettle: 10 * time.Second,
+			expectedErr:    None,
+		},
+		{
+			name:        "min-ready with settle",
+			args:        []string{"--min-ready=10s", "--settle=5s"},
+			expectedErr: "--min-ready is an alias of --settle, and cannot be combined with it",
+		},
+		{
+			name:        "min-ready longer than the timeout",
+			args:        []string{"--min-ready=1m", "--timeout=30s"},
+			expectedErr: "--settle must be shorter than --timeout",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+			cmd := &cobra.Command{}
+			flags.AddFlags(cmd)
+			if err := cmd.Flags().Parse(append([]string{"--for=condition=Ready"}, test.args...)); err != nil {
+				t.Fatal(err)
+			}
+			o, err := flags.ToOptions([]string{"pod/foo"})
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			if o.Settle != test.expectedSettle {
+				t.Errorf("expected a settle window of %v, got %v", test.expectedSettle, o.Settle)
+			}
+		})
+	}
+}
+
+// discoveryClientGetter is a RESTClientGetter with a fake discovery client, which resource
+// builders need to expand resource type arguments
+type discoveryClientGetter struct {
+	genericclioptions.RESTClientGetter
+	discovery discovery.CachedDiscoveryInterface
+}
+
+func (g discoveryClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return g.discovery, nil
+}
+
+func TestWaitShowTiming(t *testing.T) {
+	var infos []*resource.Info
+	for _, name := range []string{"name-foo", "name-bar"} {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+		})
+	}
+	polls := map[string]int{"name-foo": 3, "name-bar": 1}
+
+	for _, showTiming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("show timing %t", showTiming), func(t *testing.T) {
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			flags := NewWaitFlags(nil, streams)
+			printer, err := flags.PrintFlags.ToPrinter()
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        time.Minute,
+				Clock:          fakeClock,
+
+				Printer: printer,
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					start := fakeClock.Now()
+					for i := 0; i < polls[info.Name]; i++ {
+						fakeClock.Advance(4733 * time.Millisecond)
+						o.recordProgress(info, start, "", i == polls[info.Name]-1)
+					}
+					return newUnstructured("group/version", "TheKind", info.Namespace, info.Name), true, nil
+				},
+				IOStreams: streams,
+			}
+			if showTiming {
+				o.PrinterFor = flags.timingPrinterFor
+			}
+
+			result, err := o.Wait(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := "thekind.group/name-foo condition met\nthekind.group/name-bar condition met\n"
+			if showTiming {
+				expected = "thekind.group/name-foo condition met after 3 polls in 14.2s\n" +
+					"thekind.group/name-bar condition met after 1 poll in 4.7s\n"
+			}
+			if out.String() != expected {
+				t.Errorf("expected output:\n%s\ngot:\n%s", expected, out.String())
+			}
+			if result.Polls != 4 {
+				t.Errorf("expected 4 polls, got %d", result.Polls)
+			}
+			for _, status := range result.Resources {
+				if status.Polls != polls[status.Name] {
+					t.Errorf("%s: expected %d polls, got %d", status.Name, polls[status.Name], status.Polls)
+				}
+				if expected := time.Duration(polls[status.Name]) * 4733 * time.Millisecond; status.Elapsed != expected {
+					t.Errorf("%s: expected %v elapsed, got %v", status.Name, expected, status.Elapsed)
+				}
+			}
+		})
+	}
+
+	t.Run("with an output format", func(t *testing.T) {
+		tf := cmdtesting.NewTestFactory().WithNamespace("test")
+		defer tf.Cleanup()
+		flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+		*flags.PrintFlags.OutputFormat = "json"
+		flags.ShowTiming = true
+		flags.ForConditions = []string{"condition=Ready"}
+		_, err := flags.ToOptions([]string{"pod/foo"})
+		if err == nil || !strings.Contains(err.Error(), "--show-timing only applies to the default output") {
+			t.Fatalf("expected --show-timing to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestWaitForNegatedCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		conditionType string
+		status        string
+
+		expectedErr string
+	}{
+		{
+			name:          "false",
+			conditionType: "Ready",
+			status:        "False",
+			expectedErr:   None,
+		},
+		{
+			name:          "unknown",
+			conditionType: "Ready",
+			status:        "Unknown",
+			expectedErr:   None,
+		},
+		{
+			name:          "true",
+			conditionType: "Ready",
+			status:        "True",
+			expectedErr:   "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: True) to be present and not True",
+		},
+		{
+			name:          "absent",
+			conditionType: "Initialized",
+			status:        "False",
+			expectedErr:   "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: <none>) to be present and not True",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(addCondition(
+					newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+					test.conditionType, test.status,
+				)), nil
+			})
+			conditionFn, err := conditionFuncFor("condition!=Ready", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWaitRequireTransition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	ready := func(status string) *unstructured.Unstructured {
+		return addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", status)
+	}
+	running := func(phase string) *unstructured.Unstructured {
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+		unstructured.SetNestedField(obj.Object, phase, "status", "phase")
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		mode      TransitionMode
+		listed    *unstructured.Unstructured
+		watched   []*unstructured.Unstructured
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "already met without requiring a transition",
+			condition:   "condition=Ready",
+			mode:        TransitionModeNone,
+			listed:      ready("True"),
+			expectedErr: None,
+		},
+		{
+			name:        "already met, then unmet and met again",
+			condition:   "condition=Ready",
+			mode:        TransitionModeWait,
+			listed:      ready("True"),
+			watched:     []*unstructured.Unstructured{ready("False"), ready("True")},
+			expectedErr: None,
+		},
+		{
+			name:        "already met and never unmet",
+			condition:   "condition=Ready",
+			mode:        TransitionModeWait,
+			listed:      ready("True"),
+			watched:     []*unstructured.Unstructured{ready("True")},
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:        "unmet, then met",
+			condition:   "condition=Ready",
+			mode:        TransitionModeWait,
+			listed:      ready("False"),
+			watched:     []*unstructured.Unstructured{ready("True")},
+			expectedErr: None,
+		},
+		{
+			name:        "missing, then met",
+			condition:   "condition=Ready",
+			mode:        TransitionModeWait,
+			watched:     []*unstructured.Unstructured{ready("True")},
+			expectedErr: None,
+		},
+		{
+			name:        "already met when failing without a transition",
+			condition:   "condition=Ready",
+			mode:        TransitionModeFail,
+			listed:      ready("True"),
+			expectedErr: "condition unsatisfied on theresource/name-foo: the condition was already met when the wait started, so no transition was observed",
+			exitCode:    ExitCodeConditionUnmet,
+		},
+		{
+			name:        "unmet, then met when failing without a transition",
+			condition:   "condition=Ready",
+			mode:        TransitionModeFail,
+			listed:      ready("False"),
+			watched:     []*unstructured.Unstructured{ready("True")},
+			expectedErr: None,
+		},
+		{
+			name:        "jsonpath already met, then unmet and met again",
+			condition:   "jsonpath={.status.phase}=Running",
+			mode:        TransitionModeWait,
+			listed:      running("Running"),
+			watched:     []*unstructured.Unstructured{running("Pending"), running("Running")},
+			expectedErr: None,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if test.listed == nil {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(test.listed), nil
+			})
+			fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				fakeWatch := watch.NewRaceFreeFake()
+				for _, obj := range test.watched {
+					fakeWatch.Modify(obj)
+				}
+				return true, fakeWatch, nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder:    genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:     fakeClient,
+				Timeout:           100 * time.Millisecond,
+				RequireTransition: test.mode,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitWriters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	var infos []*resource.Info
+	for _, name := range []string{"name-foo", "name-bar"} {
+		infos = append(infos, &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+		})
+	}
+
+	for _, concurrency := range []int{1, 2} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				name := strings.TrimPrefix(action.(clienttesting.ListAction).GetListRestrictions().Fields.String(), "metadata.name=")
+				return true, newUnstructuredList(addCondition(
+					newUnstructured("group/version", "TheKind", "ns-foo", name),
+					"the-condition", "False",
+				)), nil
+			})
+			fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				name := strings.TrimPrefix(action.(clienttesting.WatchAction).GetWatchRestrictions().Fields.String(), "metadata.name=")
+				fakeWatch := watch.NewRaceFreeFake()
+				fakeWatch.Error(&apierrors.NewInternalError(errors.New("boom")).ErrStatus)
+				fakeWatch.Action(watch.Modified, addCondition(
+					newUnstructured("group/version", "TheKind", "ns-foo", name),
+					"the-condition", "True",
+				))
+				return true, fakeWatch, nil
+			})
+			conditionFn, err := conditionFuncFor("condition=the-condition", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Second,
+				Concurrency:    concurrency,
+
+				Printer:     &printers.NamePrinter{Operation: "condition met"},
+				ConditionFn: conditionFn,
+				IOStreams:   streams,
+			}
+			if err := o.RunWait(); err != nil {
+				t.Fatal(err)
+			}
+
+			// the result goes to Out and the errors seen while waiting to ErrOut, a line each
+			outLines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			sort.Strings(outLines)
+			if expected := []string{"thekind.group/name-bar condition met", "thekind.group/name-foo condition met"}; !reflect.DeepEqual(outLines, expected) {
+				t.Errorf("expected output %q, got %q", expected, out.String())
+			}
+			errLines := strings.Split(strings.TrimSpace(errOut.String()), "\n")
+			if len(errLines) != 2 {
+				t.Fatalf("expected an error line per resource, got %q", errOut.String())
+			}
+			for _, line := range errLines {
+				if !strings.HasPrefix(line, "error: An error occurred while waiting for the condition to be satisfied: ") {
+					t.Errorf("unexpected error line %q", line)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitQuiet(t *testing.T) {
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+			Object:    newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"),
+		},
+	}
+	conditionFnReturning := func(done bool) ConditionFunc {
+		return func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+			return info.Object, done, nil
+		}
+	}
+
+	tests := []struct {
+		name        string
+		quiet       bool
+		conditionFn ConditionFunc
+		count       *CountWait
+
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name:        "met",
+			conditionFn: conditionFnReturning(true),
+			expectedOut: "thekind.group/name-foo condition met\n",
+		},
+		{
+			name:        "met quietly",
+			quiet:       true,
+			conditionFn: conditionFnReturning(true),
+		},
+		{
+			name:  "count met quietly",
+			quiet: true,
+			count: &CountWait{operator: "=", count: 1},
+		},
+		{
+			name:        "unmet quietly",
+			quiet:       true,
+			conditionFn: conditionFnReturning(false),
+			expectedErr: "condition unsatisfied on theresource/name-foo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        time.Second,
+				Quiet:          test.quiet,
+
+				Printer:     &printers.NamePrinter{Operation: "condition met"},
+				ConditionFn: test.conditionFn,
+				Count:       test.count,
+				IOStreams:   streams,
+			}
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if out.String() != test.expectedOut {
+				t.Errorf("expected output %q, got %q", test.expectedOut, out.String())
+			}
+		})
+	}
+
+	t.Run("with an output format", func(t *testing.T) {
+		tf := cmdtesting.NewTestFactory().WithNamespace("test")
+		defer tf.Cleanup()
+		flags := NewWaitFlags(tf, genericclioptions.NewTestIOStreamsDiscard())
+		*flags.PrintFlags.OutputFormat = "name"
+		flags.Quiet = true
+		flags.ForConditions = []string{"condition=Ready"}
+		_, err := flags.ToOptions([]string{"pod/foo"})
+		if err == nil || !strings.Contains(err.Error(), "--quiet cannot be used with -o or --template") {
+			t.Fatalf("expected --quiet to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestWaitForNamespaceDeletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "namespaces"}: "NamespaceList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource:         schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+				GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+			},
+			Name: "foo",
+		},
+	}
+	terminating := func() *unstructured.Unstructured {
+		namespace := newUnstructured("v1", "Namespace", "", "foo")
+		namespace.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		unstructured.SetNestedStringSlice(namespace.Object, []string{"kubernetes"}, "spec", "finalizers")
+		unstructured.SetNestedSlice(namespace.Object, []interface{}{
+			map[string]interface{}{"type": "NamespaceDeletionDiscoveryFailure", "status": "False"},
+			map[string]interface{}{"type": "NamespaceContentRemaining", "status": "True", "message": "Some resources are remaining: pods. has 1 resource instances"},
+			map[string]interface{}{"type": "NamespaceFinalizersRemaining", "status": "True", "message": "Some content in the namespace has finalizers remaining: example.com/block in 1 resource instances"},
+		}, "status", "conditions")
+		return namespace
+	}
+
+	tests := []struct {
+		name            string
+		namespace       *unstructured.Unstructured
+		checkNow        bool
+		resourceVersion string
+
+		expectedErr string
+		exitCode    int
+	}{
+		{
+			name:        "deleted",
+			expectedErr: None,
+		},
+		{
+			name:      "stuck terminating",
+			namespace: terminating(),
+			expectedErr: "timed out waiting for the condition on namespaces/foo: deletion (last observed: terminating); termination is blocked by spec.finalizers kubernetes; " +
+				"NamespaceContentRemaining: Some resources are remaining: pods. has 1 resource instances; " +
+				"NamespaceFinalizersRemaining: Some content in the namespace has finalizers remaining: example.com/block in 1 resource instances",
+			exitCode: ExitCodeTimeout,
+		},
+		{
+			name:        "stuck terminating when checked now",
+			namespace:   terminating(),
+			checkNow:    true,
+			expectedErr: "condition not met on namespaces/foo: expected deletion (last observed: terminating); termination is blocked by spec.finalizers kubernetes; ",
+			exitCode:    ExitCodeNotMet,
+		},
+		{
+			name:        "not terminating",
+			namespace:   newUnstructured("v1", "Namespace", "", "foo"),
+			expectedErr: "timed out waiting for the condition on namespaces/foo: deletion (last observed: present)",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:            "resumed from a resourceVersion without a change",
+			namespace:       terminating(),
+			resourceVersion: "123",
+			expectedErr:     "timed out waiting for the condition on namespaces/foo",
+			exitCode:        ExitCodeTimeout,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if test.namespace == nil {
+					return true, newUnstructuredList(), nil
+				}
+				return true, newUnstructuredList(test.namespace), nil
+			})
+			fakeClient.PrependWatchReactor("namespaces", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				return true, watch.NewRaceFreeFake(), nil
+			})
+			conditionFnFor, err := conditionFnForKinds([]string{"delete"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder:  genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:   fakeClient,
+				Timeout:         10 * time.Millisecond,
+				CheckNow:        test.checkNow,
+				ForCondition:    "delete",
+				ResourceVersion: test.resourceVersion,
+
+				Printer:        printers.NewDiscardingPrinter(),
+				ConditionFn:    IsDeleted,
+				ConditionFnFor: conditionFnFor,
+				IOStreams:      genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONPathNumericComparison(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		operator  string
+		condition string
+
+		expected    bool
+		expectedErr string
+	}{
+		{name: "float number against a float", value: 0.85, operator: ">=", condition: "0.8", expected: true},
+		{name: "float number below a float", value: 0.75, operator: ">=", condition: "0.8", expected: false},
+		{name: "float string against a float", value: "0.85", operator: ">", condition: "0.8", expected: true},
+		{name: "integer number against a float", value: int64(1), operator: ">=", condition: "0.8", expected: true},
+		{name: "float number against an integer", value: 2.5, operator: "<", condition: "3", expected: true},
+		{name: "integer-looking float against an integer", value: 3.0, operator: "=", condition: "3", expected: true},
+		{name: "integer string against an integer", value: "3", operator: "!=", condition: "3", expected: false},
+		{name: "large integers keep their precision", value: int64(9007199254740993), operator: ">", condition: "9007199254740992", expected: true},
+		{name: "float with an exponent", value: 1500.0, operator: ">", condition: "1e3", expected: true},
+		{name: "not a number", value: "high", operator: ">", condition: "0.8", expectedErr: `jsonpath value "high" is not a number`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := newJSONPathWait("{.status.utilization}", test.operator, test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+			obj.Object["status"] = map[string]interface{}{"utilization": test.value}
+			met, err := w.checkCondition(obj)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+			}
+			if met != test.expected {
+				t.Errorf("expected met to be %t, got %t", test.expected, met)
+			}
+		})
+	}
+
+	for _, condition := range []string{"NaN", "Inf", "0.8.1"} {
+		if _, err := newJSONPathWait("{.status.utilization}", ">=", condition, false); err == nil {
+			t.Errorf("expected %q to be rejected", condition)
+		}
+	}
+}
+
+// sequenceResourceFinder finds the next set of resources every time it is asked, and the last
+// one once they have all been found
+type sequenceResourceFinder struct {
+	sets  [][]*resource.Info
+	calls int
+}
+
+func (f *sequenceResourceFinder) Do() resource.Visitor {
+	set := f.sets[len(f.sets)-1]
+	if f.calls < len(f.sets) {
+		set = f.sets[f.calls]
+	}
+	f.calls++
+	return genericclioptions.NewSimpleFakeResourceFinder(set...).Do()
+}
+
+func TestWaitRelist(t *testing.T) {
+	newInfo := func(name string) *resource.Info {
+		return &resource.Info{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      name,
+			Namespace: "ns-foo",
+			Object:    newUnstructured("group/version", "TheKind", "ns-foo", name),
+		}
+	}
+	foo, bar := newInfo("name-foo"), newInfo("name-bar")
+
+	tests := []struct {
+		name     string
+		checkNow bool
+		// relists is how many times the condition on name-foo returns ErrRelist before it is met
+		relists int
+
+		expectedChecks  map[string]int
+		expectedLookups int
+		expectedErr     string
+		exitCode        int
+	}{
+		{
+			name:            "the resources are looked up again once",
+			relists:         1,
+			expectedChecks:  map[string]int{"name-foo": 2, "name-bar": 1},
+			expectedLookups: 2,
+			expectedErr:     None,
+		},
+		{
+			name:            "without relisting",
+			expectedChecks:  map[string]int{"name-foo": 1},
+			expectedLookups: 1,
+			expectedErr:     None,
+		},
+		{
+			name:        "relisting until the timeout",
+			relists:     math.MaxInt32,
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+			exitCode:    ExitCodeTimeout,
+		},
+		{
+			name:            "relisting when checked now",
+			checkNow:        true,
+			relists:         1,
+			expectedChecks:  map[string]int{"name-foo": 1},
+			expectedLookups: 1,
+			expectedErr:     "condition not met on theresource/name-foo: the resources have to be looked up again",
+			exitCode:        ExitCodeNotMet,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			finder := &sequenceResourceFinder{sets: [][]*resource.Info{{foo}, {foo, bar}}}
+			checks := map[string]int{}
+			o := &WaitOptions{
+				ResourceFinder: finder,
+				Timeout:        10 * time.Millisecond,
+				CheckNow:       test.checkNow,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					checks[info.Name]++
+					if info.Name == "name-foo" && checks[info.Name] <= test.relists {
+						return info.Object, false, fmt.Errorf("a new resource matches: %w", ErrRelist)
+					}
+					return info.Object, true, nil
+				},
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+				return
+			}
+			if !reflect.DeepEqual(checks, test.expectedChecks) {
+				t.Errorf("expected checks %v, got %v", test.expectedChecks, checks)
+			}
+			if finder.calls != test.expectedLookups {
+				t.Errorf("expected the resources to be looked up %d times, got %d", test.expectedLookups, finder.calls)
+			}
+			if result.Matched != len(test.expectedChecks) || len(result.Satisfied) != len(test.expectedChecks) {
+				t.Errorf("expected %d resources to be matched and satisfied, got %d and %d", len(test.expectedChecks), result.Matched, len(result.Satisfied))
+			}
+		})
+	}
+}
+
+func TestWaitMaxPolls(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	newObj := func(status string) *unstructured.Unstructured {
+		return addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", status)
+	}
+
+	tests := []struct {
+		name         string
+		forCondition string
+		pollInterval time.Duration
+		timeout      time.Duration
+		maxPolls     int
+		count        *CountWait
+		watched      []*unstructured.Unstructured
+
+		expectedPolls int
+		expectedErr   string
+		exitCode      int
+	}{
+		{
+			name:          "polling",
+			forCondition:  "condition=Ready",
+			pollInterval:  time.Millisecond,
+			timeout:       time.Minute,
+			maxPolls:      3,
+			expectedPolls: 3,
+			expectedErr:   "condition not met after 3 checks on theresource/name-foo: condition Ready (last observed: False) to be true",
+			exitCode:      ExitCodeMaxPollsExceeded,
+		},
+		{
+			name:          "watching",
+			forCondition:  "condition=Ready",
+			timeout:       time.Minute,
+			maxPolls:      3,
+			watched:       []*unstructured.Unstructured{newObj("False"), newObj("False")},
+			expectedPolls: 3,
+			expectedErr:   "condition not met after 3 checks on theresource/name-foo",
+			exitCode:      ExitCodeMaxPollsExceeded,
+		},
+		{
+			name:          "met on the last check",
+			forCondition:  "condition=Ready",
+			timeout:       time.Minute,
+			maxPolls:      3,
+			watched:       []*unstructured.Unstructured{newObj("False"), newObj("True")},
+			expectedPolls: 3,
+			expectedErr:   None,
+		},
+		{
+			name:         "timeout first",
+			forCondition: "condition=Ready",
+			pollInterval: time.Millisecond,
+			timeout:      10 * time.Millisecond,
+			maxPolls:     math.MaxInt32,
+			expectedErr:  "timed out waiting for the condition on theresource/name-foo",
+			exitCode:     ExitCodeTimeout,
+		},
+		{
+			name:          "delete",
+			forCondition:  "delete",
+			pollInterval:  time.Millisecond,
+			timeout:       time.Minute,
+			maxPolls:      2,
+			expectedPolls: 2,
+			expectedErr:   "condition not met after 2 checks on theresource/name-foo",
+			exitCode:      ExitCodeMaxPollsExceeded,
+		},
+		{
+			name:          "count",
+			forCondition:  "count=3",
+			pollInterval:  time.Millisecond,
+			timeout:       time.Minute,
+			maxPolls:      2,
+			count:         &CountWait{operator: "=", count: 3},
+			expectedPolls: 2,
+			expectedErr:   "condition not met after 2 checks: found 1 resources, expected count=3",
+			exitCode:      ExitCodeMaxPollsExceeded,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(newObj("False")), nil
+			})
+			fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				fakeWatch := watch.NewRaceFreeFake()
+				for _, obj := range test.watched {
+					fakeWatch.Modify(obj)
+				}
+				return true, fakeWatch, nil
+			})
+			conditionFn := ConditionalWait{conditionName: "Ready", conditionStatus: "true"}.IsConditionMet
+			if test.forCondition == "delete" {
+				conditionFn = IsDeleted
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        test.timeout,
+				ForCondition:   test.forCondition,
+				PollInterval:   test.pollInterval,
+				MaxPolls:       test.maxPolls,
+				Count:          test.count,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				if code := exitErrorFor(err).(utilexec.ExitError).ExitStatus(); code != test.exitCode {
+					t.Errorf("expected exit code %d, got %d", test.exitCode, code)
+				}
+			}
+			if test.expectedPolls > 0 && result.Polls != test.expectedPolls {
+				t.Errorf("expected %d polls, got %d", test.expectedPolls, result.Polls)
+			}
+		})
+	}
+}
+
+func TestWaitForSubresource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
 			},
-			timeout:      3 * time.Second,
-			jsonPathExp:  "{.metadata.name}",
-			jsonPathCond: "foo", // use incorrect name so it'll keep waiting
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
 
-			expectedErr: "timed out waiting for the condition on theresource/foo-b6699dcfb-rnv7t",
-			validateActions: func(t *testing.T, actions []clienttesting.Action) {
-				if len(actions) != 4 {
-					t.Fatal(spew.Sdump(actions))
+	tests := []struct {
+		name        string
+		subresource string
+		// status is the status of the condition on the subresource, or "" if it is not found
+		status string
+
+		expectedErr string
+	}{
+		{
+			name:        "checked on the subresource",
+			subresource: "status",
+			status:      "True",
+			expectedErr: None,
+		},
+		{
+			name:        "not met on the subresource",
+			subresource: "status",
+			status:      "False",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: False) to be true",
+		},
+		{
+			name:        "subresource not found",
+			subresource: "status",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+		{
+			name:        "checked on the resource",
+			expectedErr: "timed out waiting for the condition on theresource/name-foo: condition Ready (last observed: False) to be true",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "False")), nil
+			})
+			fakeClient.PrependReactor("get", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if action.GetSubresource() != test.subresource || len(test.status) == 0 {
+					return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "group", Resource: "theresource"}, "name-foo")
 				}
-				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
-					t.Error(spew.Sdump(actions))
+				return true, addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", test.status), nil
+			})
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+				Subresource:    test.subresource,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: ConditionalWait{conditionName: "Ready", conditionStatus: "true", errOut: ioutil.Discard}.IsConditionMet,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+
+			err := o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
 				}
-				if !actions[1].Matches("watch", "theresource") || actions[1].(clienttesting.WatchAction).GetWatchRestrictions().ResourceVersion != "234" {
-					t.Error(spew.Sdump(actions))
+			}
+			for _, action := range fakeClient.Actions() {
+				if len(test.subresource) > 0 && !action.Matches("get", "theresource") {
+					t.Errorf("expected the subresource to be fetched with get, got %s", action.GetVerb())
 				}
-				if !actions[2].Matches("list", "theresource") || actions[2].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
-					t.Error(spew.Sdump(actions))
+			}
+		})
+	}
+}
+
+func TestSubresourceCheck(t *testing.T) {
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	discoveryClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{{Name: "deployments"}, {Name: "deployments/status"}, {Name: "deployments/scale"}},
+		},
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "configmaps"}},
+		},
+	}
+	deployments := &meta.RESTMapping{Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}}
+	configMaps := &meta.RESTMapping{Resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}
+
+	tests := []struct {
+		subresource string
+		mapping     *meta.RESTMapping
+
+		expectedErr string
+	}{
+		{subresource: "scale", mapping: deployments, expectedErr: None},
+		{subresource: "status", mapping: deployments, expectedErr: None},
+		{subresource: "status", mapping: configMaps, expectedErr: "configmaps has no status subresource"},
+	}
+	for _, test := range tests {
+		t.Run(test.subresource+" of "+test.mapping.Resource.Resource, func(t *testing.T) {
+			_, err := subresourceCheckFor(discoveryClient, test.subresource, nil)(test.mapping)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
 				}
-				if !actions[3].Matches("watch", "theresource") || actions[3].(clienttesting.WatchAction).GetWatchRestrictions().ResourceVersion != "234" {
-					t.Error(spew.Sdump(actions))
+			}
+		})
+	}
+}
+
+func TestJSONPathConditionFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wait-jsonpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"image.txt": "nginx@sha256:0123abcd\n",
+		"crlf.txt":  "nginx@sha256:0123abcd\r\n",
+		"empty.txt": "\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		operator  string
+		condition string
+
+		expected    bool
+		expectedErr string
+	}{
+		{name: "matches the file", operator: "=", condition: "@" + filepath.Join(dir, "image.txt"), expected: true},
+		{name: "matches a file with a CRLF", operator: "=", condition: "@" + filepath.Join(dir, "crlf.txt"), expected: true},
+		{name: "quoted", operator: "=", condition: "'@" + filepath.Join(dir, "image.txt") + "'", expected: true},
+		{name: "differs from the file", operator: "!=", condition: "@" + filepath.Join(dir, "image.txt"), expected: false},
+		{name: "prefix read from the file", operator: "^=", condition: "@" + filepath.Join(dir, "image.txt"), expected: true},
+		{name: "missing file", operator: "=", condition: "@" + filepath.Join(dir, "missing.txt"), expectedErr: "unable to read the jsonpath wait condition from " + filepath.Join(dir, "missing.txt")},
+		{name: "empty file", operator: "=", condition: "@" + filepath.Join(dir, "empty.txt"), expectedErr: "jsonpath wait condition read from " + filepath.Join(dir, "empty.txt") + " cannot be empty"},
+		{name: "no file name", operator: "=", condition: "@", expectedErr: "jsonpath wait condition @ must be followed by the name of a file"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := newJSONPathWait("{.spec.image}", test.operator, test.condition, false)
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
+				}
+				return
+			}
+			obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+			obj.Object["spec"] = map[string]interface{}{"image": "nginx@sha256:0123abcd"}
+			met, err := w.checkCondition(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if met != test.expected {
+				t.Errorf("expected met to be %t, got %t", test.expected, met)
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathChanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	newObj := func(value string) *unstructured.Unstructured {
+		obj := newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")
+		obj.Object["spec"] = map[string]interface{}{"value": value}
+		return obj
+	}
+
+	tests := []struct {
+		name         string
+		pollInterval time.Duration
+		// listed are the values of the object listed on every poll, the last one repeating
+		listed  []string
+		watched []string
+
+		expectedPolls int
+		expectedErr   string
+	}{
+		{
+			name:          "changed on a later poll",
+			pollInterval:  time.Millisecond,
+			listed:        []string{"1", "1", "2"},
+			expectedPolls: 3,
+			expectedErr:   None,
+		},
+		{
+			name:          "changed on the watch",
+			listed:        []string{"1"},
+			watched:       []string{"1", "2"},
+			expectedPolls: 3,
+			expectedErr:   None,
+		},
+		{
+			name:         "unchanged",
+			pollInterval: time.Millisecond,
+			listed:       []string{"1"},
+			expectedErr:  "timed out waiting for the condition on theresource/name-foo: {.spec.value} (last observed: 1) to change",
+		},
+		{
+			name:        "changed to the same value",
+			listed:      []string{"1"},
+			watched:     []string{"1"},
+			expectedErr: "timed out waiting for the condition on theresource/name-foo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lists := 0
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				value := test.listed[len(test.listed)-1]
+				if lists < len(test.listed) {
+					value = test.listed[lists]
+				}
+				lists++
+				return true, newUnstructuredList(newObj(value)), nil
+			})
+			fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
+				fakeWatch := watch.NewRaceFreeFake()
+				for _, value := range test.watched {
+					fakeWatch.Modify(newObj(value))
+				}
+				return true, fakeWatch, nil
+			})
+			conditionFn, err := conditionFuncFor("jsonpath={.spec.value}changed", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        20 * time.Millisecond,
+				PollInterval:   test.pollInterval,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			result, err := o.Wait(context.Background())
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
 				}
+			}
+			if test.expectedPolls > 0 && result.Polls != test.expectedPolls {
+				t.Errorf("expected %d polls, got %d", test.expectedPolls, result.Polls)
+			}
+		})
+	}
+}
+
+func TestWaitConditionDeadline(t *testing.T) {
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
 			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+
+		expectedOK        bool
+		expectedRemaining time.Duration
+	}{
+		{
+			name:              "with a timeout",
+			timeout:           time.Minute,
+			expectedOK:        true,
+			expectedRemaining: time.Minute,
+		},
+		{
+			name: "without a timeout",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				deadline    time.Time
+				deadlineOK  bool
+				remaining   time.Duration
+				remainingOK bool
+			)
+			fakeClock := clockwork.NewFakeClock()
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				Timeout:        test.timeout,
+				Clock:          fakeClock,
+
+				Printer: printers.NewDiscardingPrinter(),
+				ConditionFn: func(ctx context.Context, info *resource.Info, o *WaitOptions) (runtime.Object, bool, error) {
+					deadline, deadlineOK = o.Deadline()
+					remaining, remainingOK = o.Remaining()
+					return info.Object, true, nil
+				},
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			if _, err := o.Wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if deadlineOK != test.expectedOK || remainingOK != test.expectedOK {
+				t.Fatalf("expected a deadline to be %v, got %v and %v", test.expectedOK, deadlineOK, remainingOK)
+			}
+			if !test.expectedOK {
+				return
+			}
+			if expected := fakeClock.Now().Add(test.timeout); !deadline.Equal(expected) {
+				t.Errorf("expected the deadline %v, got %v", expected, deadline)
+			}
+			if remaining != test.expectedRemaining {
+				t.Errorf("expected %v remaining, got %v", test.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestReportFailure(t *testing.T) {
+	result := Result{
+		Matched: 2,
+		Elapsed: 3 * time.Second,
+		Resources: []ResourceStatus{
+			{Resource: "pods", Namespace: "ns-foo", Name: "foo", Met: true, Elapsed: time.Second},
+			{Resource: "pods", Namespace: "ns-foo", Name: "bar", Err: &TimeoutError{Resource: "pods", Name: "bar", Detail: "condition Ready (last observed: False) to be true", Observed: "False"}, Elapsed: 3 * time.Second, ResourceVersion: "1240"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		errorFormat ErrorFormat
+		err         error
+
+		expectedErr    string
+		expectedReport *ErrorReport
+		exitCode       int
+	}{
+		{
+			name:        "text",
+			errorFormat: ErrorFormatText,
+			err:         result.Resources[1].Err,
+			expectedErr: "error: timed out waiting for the condition on pods/bar: condition Ready (last observed: False) to be true",
+			exitCode:    ExitCodeTimeout,
 		},
 		{
-			name: "handles watch condition change",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			name:        "json",
+			errorFormat: ErrorFormatJSON,
+			err:         result.Resources[1].Err,
+			expectedReport: &ErrorReport{
+				Outcome:        WaitOutcomeTimeout,
+				Error:          "timed out waiting for the condition on pods/bar: condition Ready (last observed: False) to be true",
+				Expected:       "condition=Ready",
+				Matched:        2,
+				ElapsedSeconds: 3,
+				Resources: []ResourceErrorReport{
+					{
+						Resource:       "pods/bar",
+						Namespace:      "ns-foo",
+						Outcome:        WaitOutcomeTimeout,
+						Error:          "timed out waiting for the condition on pods/bar: condition Ready (last observed: False) to be true",
+						Observed:       "False",
+						ElapsedSeconds: 3,
+
+						ResourceVersion: "1240",
 					},
-					Name:      "foo-b6699dcfb-rnv7t",
-					Namespace: "default",
 				},
 			},
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-					unstructuredObj := createUnstructured(t, podYAML)
-					unstructuredObj.SetName("foo")
-					return true, newUnstructuredList(), nil
-				})
-				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
-					fakeWatch := watch.NewRaceFreeFake()
-					fakeWatch.Action(watch.Modified, createUnstructured(t, podYAML))
-					return true, fakeWatch, nil
-				})
-				return fakeClient
-			},
-			timeout:      10 * time.Second,
-			jsonPathExp:  "{.metadata.name}",
-			jsonPathCond: "foo-b6699dcfb-rnv7t",
-
-			expectedErr: None,
-			validateActions: func(t *testing.T, actions []clienttesting.Action) {
-				if len(actions) != 2 {
-					t.Fatal(spew.Sdump(actions))
-				}
-				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
-					t.Error(spew.Sdump(actions))
-				}
-				if !actions[1].Matches("watch", "theresource") {
-					t.Error(spew.Sdump(actions))
-				}
-			},
+			exitCode: ExitCodeTimeout,
 		},
 		{
-			name: "handles watch created",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
+			name:        "json without an exit code of its own",
+			errorFormat: ErrorFormatJSON,
+			err:         errors.New("the server is currently unable to handle the request"),
+			expectedReport: &ErrorReport{
+				Outcome:        WaitOutcomeError,
+				Error:          "the server is currently unable to handle the request",
+				Expected:       "condition=Ready",
+				Matched:        2,
+				ElapsedSeconds: 3,
+				Resources: []ResourceErrorReport{
+					{
+						Resource:       "pods/bar",
+						Namespace:      "ns-foo",
+						Outcome:        WaitOutcomeTimeout,
+						Error:          "timed out waiting for the condition on pods/bar: condition Ready (last observed: False) to be true",
+						Observed:       "False",
+						ElapsedSeconds: 3,
+
+						ResourceVersion: "1240",
 					},
-					Name:      "foo-b6699dcfb-rnv7t",
-					Namespace: "default",
 				},
 			},
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
-					fakeWatch := watch.NewRaceFreeFake()
-					fakeWatch.Action(watch.Added, createUnstructured(t, podYAML))
-					return true, fakeWatch, nil
-				})
-				return fakeClient
-			},
-			timeout:      10 * time.Second,
-			jsonPathExp:  "{.spec.containers[0].image}",
-			jsonPathCond: "nginx",
+			exitCode: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &WaitOptions{
+				ForCondition: "condition=Ready",
+				ErrorFormat:  test.errorFormat,
+				IOStreams:    streams,
+			}
+			err := o.reportFailure(result, test.err)
 
-			expectedErr: None,
-			validateActions: func(t *testing.T, actions []clienttesting.Action) {
-				if len(actions) != 2 {
-					t.Fatal(spew.Sdump(actions))
-				}
-				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
-					t.Error(spew.Sdump(actions))
+			exitErr, ok := err.(utilexec.ExitError)
+			if !ok {
+				t.Fatalf("expected an exit error, got %v", err)
+			}
+			if exitErr.ExitStatus() != test.exitCode {
+				t.Errorf("expected exit code %d, got %d", test.exitCode, exitErr.ExitStatus())
+			}
+			if test.expectedReport == nil {
+				if err.Error() != test.expectedErr {
+					t.Errorf("expected %q, got %q", test.expectedErr, err.Error())
 				}
-				if !actions[1].Matches("watch", "theresource") {
-					t.Error(spew.Sdump(actions))
+				if errOut.Len() != 0 {
+					t.Errorf("expected nothing on stderr, got %q", errOut.String())
 				}
-			},
-		},
-		{
-			name: "ignores watch error",
-			infos: []*resource.Info{
-				{
-					Mapping: &meta.RESTMapping{
-						Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
-					},
-					Name:      "foo-b6699dcfb-rnv7t",
-					Namespace: "default",
+				return
+			}
+			if len(err.Error()) != 0 {
+				t.Errorf("expected the error to have no message of its own, got %q", err.Error())
+			}
+			var report ErrorReport
+			if err := json.Unmarshal(errOut.Bytes(), &report); err != nil {
+				t.Fatalf("expected a JSON error report on stderr, got %q: %v", errOut.String(), err)
+			}
+			if !reflect.DeepEqual(report, *test.expectedReport) {
+				t.Errorf("expected %+v, got %+v", *test.expectedReport, report)
+			}
+		})
+	}
+
+	t.Run("observed on a timeout", func(t *testing.T) {
+		scheme := runtime.NewScheme()
+		listMapping := map[schema.GroupVersionResource]string{
+			{Group: "group", Version: "version", Resource: "theresource"}: "TheKindList",
+		}
+		fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+		fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, newUnstructuredList(addCondition(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo"), "Ready", "False")), nil
+		})
+		o := &WaitOptions{
+			ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(&resource.Info{
+				Mapping: &meta.RESTMapping{
+					Resource: schema.GroupVersionResource{Group: "group", Version: "version", Resource: "theresource"},
 				},
+				Name:      "name-foo",
+				Namespace: "ns-foo",
+			}),
+			DynamicClient: fakeClient,
+			Timeout:       10 * time.Millisecond,
+			PollInterval:  time.Millisecond,
+
+			Printer:     printers.NewDiscardingPrinter(),
+			ConditionFn: ConditionalWait{conditionName: "Ready", conditionStatus: "true", errOut: ioutil.Discard}.IsConditionMet,
+			IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+		}
+		result, err := o.Wait(context.Background())
+		if err == nil {
+			t.Fatal("expected the wait to time out")
+		}
+		report := errorReportFor(result, err, "condition=Ready")
+		if len(report.Resources) != 1 || report.Resources[0].Observed != "False" || report.Resources[0].Outcome != WaitOutcomeTimeout {
+			t.Errorf("expected the resource to time out having last observed False, got %+v", report.Resources)
+		}
+	})
+}
+
+func TestWaitForJSONPathCmp(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
 			},
-			fakeClient: func() *dynamicfakeclient.FakeDynamicClient {
-				fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
-				fakeClient.PrependReactor("list", "theresource", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, newUnstructuredList(newUnstructured("group/version", "TheKind", "ns-foo", "name-foo")), nil
-				})
-				count := 0
-				fakeClient.PrependWatchReactor("theresource", func(action clienttesting.Action) (handled bool, ret watch.Interface, err error) {
-					fakeWatch := watch.NewRaceFreeFake()
-					if count == 0 {
-						fakeWatch.Error(newUnstructuredStatus(&metav1.Status{
-							TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
-							Status:   "Failure",
-							Code:     500,
-							Message:  "Bad",
-						}))
-						fakeWatch.Stop()
-					} else {
-						fakeWatch.Action(watch.Modified, createUnstructured(t, podYAML))
-					}
-					count++
-					return true, fakeWatch, nil
-				})
-				return fakeClient
-			},
-			timeout:      10 * time.Second,
-			jsonPathExp:  "{.metadata.name}",
-			jsonPathCond: "foo-b6699dcfb-rnv7t",
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		spec      map[string]interface{}
+		status    map[string]interface{}
 
+		expectedErr string
+	}{
+		{
+			name:        "ready replicas reached",
+			condition:   "jsonpath-cmp={.status.readyReplicas}>={.spec.replicas}",
+			spec:        map[string]interface{}{"replicas": int64(3)},
+			status:      map[string]interface{}{"readyReplicas": int64(3)},
 			expectedErr: None,
-			validateActions: func(t *testing.T, actions []clienttesting.Action) {
-				if len(actions) != 4 {
-					t.Fatal(spew.Sdump(actions))
-				}
-				if !actions[0].Matches("list", "theresource") || actions[0].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
-					t.Error(spew.Sdump(actions))
-				}
-				if !actions[1].Matches("watch", "theresource") {
-					t.Error(spew.Sdump(actions))
-				}
-				if !actions[2].Matches("list", "theresource") || actions[2].(clienttesting.ListAction).GetListRestrictions().Fields.String() != "metadata.name=foo-b6699dcfb-rnv7t" {
-					t.Error(spew.Sdump(actions))
-				}
-				if !actions[3].Matches("watch", "theresource") {
-					t.Error(spew.Sdump(actions))
+		},
+		{
+			name:        "equal as numbers",
+			condition:   "jsonpath-cmp={.status.utilization}={.spec.target}",
+			spec:        map[string]interface{}{"target": "0.5"},
+			status:      map[string]interface{}{"utilization": 0.50},
+			expectedErr: None,
+		},
+		{
+			name:        "ready replicas not reached",
+			condition:   "jsonpath-cmp={.status.readyReplicas}>={.spec.replicas}",
+			spec:        map[string]interface{}{"replicas": int64(3)},
+			status:      map[string]interface{}{"readyReplicas": int64(2)},
+			expectedErr: "timed out waiting for the condition on deployments/name-foo: {.status.readyReplicas} (last observed: 2) to be >= {.spec.replicas}",
+		},
+		{
+			name:        "not resolved yet",
+			condition:   "jsonpath-cmp={.status.readyReplicas}>={.spec.replicas}",
+			spec:        map[string]interface{}{"replicas": int64(3)},
+			expectedErr: "timed out waiting for the condition on deployments/name-foo: {.status.readyReplicas} (last observed: <none>) to be >= {.spec.replicas}",
+		},
+		{
+			name:        "not a number",
+			condition:   "jsonpath-cmp={.status.readyReplicas}>={.spec.replicas}",
+			spec:        map[string]interface{}{"replicas": "all"},
+			status:      map[string]interface{}{"readyReplicas": int64(3)},
+			expectedErr: `jsonpath value "all" of {.spec.replicas} is not a number`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := newUnstructured("apps/v1", "Deployment", "ns-foo", "name-foo")
+			obj.Object["spec"] = test.spec
+			if test.status != nil {
+				obj.Object["status"] = test.status
+			}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "deployments", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &WaitOptions{
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
+				DynamicClient:  fakeClient,
+				Timeout:        10 * time.Millisecond,
+				PollInterval:   time.Millisecond,
+
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err = o.RunWait()
+
+			switch {
+			case err == nil && len(test.expectedErr) == 0:
+			case err != nil && len(test.expectedErr) == 0:
+				t.Fatal(err)
+			case err == nil && len(test.expectedErr) != 0:
+				t.Fatalf("missing: %q", test.expectedErr)
+			case err != nil && len(test.expectedErr) != 0:
+				if !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
 				}
+			}
+		})
+	}
+}
+
+func TestWaitForJSONPathQuantifier(t *testing.T) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		{Group: "", Version: "v1", Resource: "pods"}: "PodList",
+	}
+	infos := []*resource.Info{
+		{
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
 			},
+			Name:      "name-foo",
+			Namespace: "ns-foo",
+		},
+	}
+	containerStatuses := func(ready ...bool) []interface{} {
+		statuses := []interface{}{}
+		for i, r := range ready {
+			statuses = append(statuses, map[string]interface{}{"name": fmt.Sprintf("c%d", i), "ready": r, "restartCount": int64(i)})
+		}
+		return statuses
+	}
+
+	tests := []struct {
+		name              string
+		condition         string
+		containerStatuses []interface{}
+
+		expectedErr string
+	}{
+		{
+			name:              "every container ready",
+			condition:         "jsonpath-all={.status.containerStatuses[*].ready}=true",
+			containerStatuses: containerStatuses(true, true, true),
+			expectedErr:       None,
+		},
+		{
+			name:              "one container not ready",
+			condition:         "jsonpath-all={.status.containerStatuses[*].ready}=true",
+			containerStatuses: containerStatuses(true, false, true),
+			expectedErr:       "timed out waiting for the condition on pods/name-foo: every value of {.status.containerStatuses[*].ready} (last observed: true false true) to be true",
+		},
+		{
+			name:              "no container",
+			condition:         "jsonpath-all={.status.containerStatuses[*].ready}=true",
+			containerStatuses: containerStatuses(),
+			expectedErr:       "timed out waiting for the condition on pods/name-foo",
+		},
+		{
+			name:              "one container ready",
+			condition:         "jsonpath-any={.status.containerStatuses[*].ready}=true",
+			containerStatuses: containerStatuses(false, true),
+			expectedErr:       None,
+		},
+		{
+			name:              "no container ready",
+			condition:         "jsonpath-any={.status.containerStatuses[*].ready}=true",
+			containerStatuses: containerStatuses(false, false),
+			expectedErr:       "timed out waiting for the condition on pods/name-foo: any value of {.status.containerStatuses[*].ready} (last observed: false false) to be true",
+		},
+		{
+			name:              "a container restarted",
+			condition:         "jsonpath-any={.status.containerStatuses[*].restartCount}>0",
+			containerStatuses: containerStatuses(true, true),
+			expectedErr:       None,
+		},
+		{
+			name:              "every name matches",
+			condition:         "jsonpath-all={.status.containerStatuses[*].name}~=^c[0-9]$",
+			containerStatuses: containerStatuses(true, false),
+			expectedErr:       None,
+		},
+		{
+			name:              "a single value",
+			condition:         "jsonpath-all={.status.containerStatuses[0].ready}=true",
+			containerStatuses: containerStatuses(true, false),
+			expectedErr:       None,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fakeClient := test.fakeClient()
-			j, _ := newJSONPathParser(test.jsonPathExp)
+			obj := newUnstructured("v1", "Pod", "ns-foo", "name-foo")
+			obj.Object["status"] = map[string]interface{}{"containerStatuses": test.containerStatuses}
+			fakeClient := dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, newUnstructuredList(obj), nil
+			})
+			conditionFn, err := conditionFuncFor(test.condition, false)
+			if err != nil {
+				t.Fatal(err)
+			}
 			o := &WaitOptions{
-				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(test.infos...),
+				ResourceFinder: genericclioptions.NewSimpleFakeResourceFinder(infos...),
 				DynamicClient:  fakeClient,
-				Timeout:        test.timeout,
+				Timeout:        10 * time.Millisecond,
+				PollInterval:   time.Millisecond,
 
-				Printer: printers.NewDiscardingPrinter(),
-				ConditionFn: JSONPathWait{
-					jsonPathCondition: test.jsonPathCond,
-					jsonPathParser:    j, errOut: ioutil.Discard}.IsJSONPathConditionMet,
-				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+				Printer:     printers.NewDiscardingPrinter(),
+				ConditionFn: conditionFn,
+				IOStreams:   genericclioptions.NewTestIOStreamsDiscard(),
 			}
-
-			err := o.RunWait()
+			err = o.RunWait()
 
 			switch {
 			case err == nil && len(test.expectedErr) == 0:
@@ -1680,8 +10488,6 @@ func TestWaitForJSONPathCondition(t *testing.T) {
 					t.Fatalf("expected %q, got %q", test.expectedErr, err.Error())
 				}
 			}
-
-			test.validateActions(t, fakeClient.Actions())
 		})
 	}
 }
@@ -89,7 +89,7 @@ func GetAllReplicaSets(deployment *appsv1.Deployment, c appsclient.AppsV1Interfa
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	newRS := findNewReplicaSet(deployment, rsList)
+	newRS := FindNewReplicaSet(deployment, rsList)
 	oldRSes, allOldRSes := findOldReplicaSets(deployment, rsList, newRS)
 	return oldRSes, allOldRSes, newRS, nil
 }
@@ -104,7 +104,7 @@ func GetAllReplicaSetsInChunks(deployment *appsv1.Deployment, c appsclient.AppsV
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	newRS := findNewReplicaSet(deployment, rsList)
+	newRS := FindNewReplicaSet(deployment, rsList)
 	oldRSes, allOldRSes := findOldReplicaSets(deployment, rsList, newRS)
 	return oldRSes, allOldRSes, newRS, nil
 }
@@ -181,7 +181,7 @@ func equalIgnoreHash(template1, template2 *corev1.PodTemplateSpec) bool {
 }
 
 // FindNewReplicaSet returns the new RS this given deployment targets (the one with the same pod template).
-func findNewReplicaSet(deployment *appsv1.Deployment, rsList []*appsv1.ReplicaSet) *appsv1.ReplicaSet {
+func FindNewReplicaSet(deployment *appsv1.Deployment, rsList []*appsv1.ReplicaSet) *appsv1.ReplicaSet {
 	sort.Sort(replicaSetsByCreationTimestamp(rsList))
 	for i := range rsList {
 		if equalIgnoreHash(&rsList[i].Spec.Template, &deployment.Spec.Template) {